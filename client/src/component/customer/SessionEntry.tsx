@@ -0,0 +1,44 @@
+import { useState, type FormEvent } from "react";
+
+interface SessionEntryProps {
+  onSubmit: (sessionId: string) => void;
+}
+
+// 직원 화면에 표시된 세션 코드를 입력받는다 (키오스크 주소에 ?session=이 없을 때)
+export default function SessionEntry({ onSubmit }: SessionEntryProps) {
+  const [code, setCode] = useState("");
+
+  const handleSubmit = (e: FormEvent) => {
+    e.preventDefault();
+    const sessionId = code.trim();
+    if (sessionId) {
+      onSubmit(sessionId);
+    }
+  };
+
+  return (
+    <div className="w-full h-screen flex items-center justify-center bg-gray-100">
+      <form
+        onSubmit={handleSubmit}
+        className="bg-white rounded-xl p-8 shadow-lg max-w-md w-full mx-4 text-center"
+      >
+        <h2 className="text-2xl font-bold text-blue-700 mb-2">상담 세션 연결</h2>
+        <p className="text-gray-600 text-sm mb-6">
+          직원 화면에 표시된 세션 코드를 입력해주세요
+        </p>
+        <input
+          value={code}
+          onChange={(e) => setCode(e.target.value)}
+          placeholder="세션 코드"
+          className="w-full border border-gray-300 rounded-lg px-4 py-2 mb-4 font-mono"
+        />
+        <button
+          type="submit"
+          className="w-full px-6 py-3 bg-blue-600 text-white rounded-lg hover:bg-blue-700 font-semibold"
+        >
+          연결하기
+        </button>
+      </form>
+    </div>
+  );
+}
@@ -0,0 +1,62 @@
+import { kioskUrl } from "../../util/kiosk";
+
+interface SessionControlProps {
+  sessionId: string | null;
+  connected: boolean;
+  onStart: () => void;
+  onEnd: () => void;
+}
+
+// 상담 세션 시작·종료와 고객 키오스크 연결 주소 표시
+export default function SessionControl({
+  sessionId,
+  connected,
+  onStart,
+  onEnd,
+}: SessionControlProps) {
+  const customerUrl = sessionId ? kioskUrl(sessionId) : "";
+
+  return (
+    <div className="bg-white p-4 rounded-lg shadow mb-6">
+      <div className="flex items-center justify-between">
+        <div>
+          <h2 className="text-xl font-semibold">🗂️ 상담 세션</h2>
+          {sessionId ? (
+            <>
+              <p className="text-sm text-gray-600 mt-1">
+                세션 코드{" "}
+                <span className="font-mono font-bold text-blue-600">
+                  {sessionId}
+                </span>
+              </p>
+              <p className="text-xs text-gray-500 mt-1 break-all">
+                고객 키오스크 주소: {customerUrl}
+              </p>
+            </>
+          ) : (
+            <p className="text-sm text-gray-600 mt-1">
+              진행 중인 세션이 없습니다
+            </p>
+          )}
+        </div>
+        {sessionId ? (
+          <button
+            onClick={onEnd}
+            disabled={!connected}
+            className="px-4 py-2 bg-red-600 text-white rounded-lg hover:bg-red-700 font-semibold disabled:opacity-50"
+          >
+            세션 종료
+          </button>
+        ) : (
+          <button
+            onClick={onStart}
+            disabled={!connected}
+            className="px-4 py-2 bg-blue-600 text-white rounded-lg hover:bg-blue-700 font-semibold disabled:opacity-50"
+          >
+            세션 시작
+          </button>
+        )}
+      </div>
+    </div>
+  );
+}
@@ -311,6 +311,9 @@ export const PAGE_CONTENTS: Record<string, PageContent> = {
   },
 } as const;
 
+// 이 화면이 설명하는 상품 (서버 약관 카탈로그의 products[].id)
+export const PRODUCT_ID = "shinhan-global-multi-asset";
+
 // 페이지 이름 매핑
 export const PAGE_NAMES: Record<string, string> = {
   productJoin: "상품 가입",
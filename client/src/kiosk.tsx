@@ -0,0 +1,18 @@
+import { StrictMode } from "react";
+import { createRoot } from "react-dom/client";
+import { BrowserRouter as Router, Routes, Route } from "react-router-dom";
+import CustomerView from "./pages/CustomerView";
+import { websocketService } from "./util/WebSocketService";
+
+// 고객 키오스크 번들. 고객 토큰만 참조하므로 직원·감독자 토큰은 이 번들에 들어가지 않는다
+websocketService.setToken("customer", import.meta.env.VITE_CUSTOMER_TOKEN);
+
+createRoot(document.getElementById("root")!).render(
+  <StrictMode>
+    <Router>
+      <Routes>
+        <Route path="*" element={<CustomerView />} />
+      </Routes>
+    </Router>
+  </StrictMode>
+);
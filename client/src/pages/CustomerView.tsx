@@ -1,5 +1,6 @@
 /* eslint-disable react-hooks/exhaustive-deps */
 import { useEffect, useRef, useState } from "react";
+import { useSearchParams } from "react-router-dom";
 import { websocketService, type GazeData } from "../util/WebSocketService";
 
 import Calibration from "../component/customer/Calibration";
@@ -9,6 +10,7 @@ import SystemChecking from "../component/customer/SystemChecking";
 import { domUtils, webgazerUtils } from "../util/utilFunction";
 import { PAGE_CONTENTS, type PageType } from "../constant/content";
 import Navigation from "../component/customer/Navigation";
+import SessionEntry from "../component/customer/SessionEntry";
 
 declare global {
   interface Window {
@@ -24,6 +26,32 @@ export default function CustomerView() {
     "checking" | "needed" | "ready"
   >("checking");
   const [currentPage, setCurrentPage] = useState<PageType>("productJoin");
+  const currentPageRef = useRef<PageType>(currentPage);
+  // 직원 화면이 시작한 세션 (/customer?session=<세션 코드>)
+  const [searchParams, setSearchParams] = useSearchParams();
+  const sessionId = searchParams.get("session");
+
+  useEffect(() => {
+    // 고객 역할로 연결하고, 세션 방에 참여하면 지금 보고 있는 페이지를 알린다
+    websocketService.onSession(() => {
+      websocketService.sendPageChange(currentPageRef.current);
+    });
+    // 직원이 세션을 끝내면 다음 고객을 위해 세션 입력 화면으로 돌아간다
+    websocketService.onSessionEnd(() => {
+      setSearchParams({});
+    });
+    websocketService.connect("customer");
+
+    return () => {
+      websocketService.disconnect();
+    };
+  }, []);
+
+  useEffect(() => {
+    if (sessionId) {
+      websocketService.joinSession(sessionId);
+    }
+  }, [sessionId]);
 
   useEffect(() => {
     // WebGazer 상태 확인
@@ -57,7 +85,12 @@ export default function CustomerView() {
 
           const sectionId = domUtils.getSectionIdFromPoint(data.x, data.y);
 
-          websocketService.sendGazeData(data.x, data.y, sectionId, currentPage);
+          websocketService.sendGazeData(
+            data.x,
+            data.y,
+            sectionId,
+            currentPageRef.current
+          );
         }
       });
     } catch (error) {
@@ -105,9 +138,16 @@ export default function CustomerView() {
   };
 
   useEffect(() => {
+    currentPageRef.current = currentPage;
     websocketService.sendPageChange(currentPage);
   }, [currentPage]);
 
+  if (!sessionId) {
+    return (
+      <SessionEntry onSubmit={(id) => setSearchParams({ session: id })} />
+    );
+  }
+
   if (calibrationStatus === "needed") {
     return <Calibration onComplete={handleCalibrationComplete} />;
   }
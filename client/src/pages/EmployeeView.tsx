@@ -4,8 +4,9 @@ import {
   websocketService,
   type GazeData,
   type PageChangeData,
+  type Session,
 } from "../util/WebSocketService";
-import { PAGE_NAMES, PAGE_SECTIONS } from "../constant/content";
+import { PAGE_NAMES, PAGE_SECTIONS, PRODUCT_ID } from "../constant/content";
 import {
   type SectionStatus,
   findPageBySection,
@@ -17,6 +18,7 @@ import EmployeeHeader from "../component/employee/EmployeeHeader";
 import CurrentPageStatus from "../component/employee/CurrentPageStatus";
 import OverallProgress from "../component/employee/OverallProgress";
 import SectionProgress from "../component/employee/SectionProgress";
+import SessionControl from "../component/employee/SessionControl";
 
 // 비활성 상태 판정: 3초간 데이터 없으면 비활성
 const INACTIVE_THRESHOLD_MS = 3000;
@@ -31,6 +33,9 @@ export default function EmployeeView() {
     Record<string, Record<string, SectionStatus>>
   >({});
 
+  const [sessionId, setSessionId] = useState<string | null>(null);
+  const sessionIdRef = useRef<string | null>(null);
+
   const [lastActiveSection, setLastActiveSection] = useState<string>("");
   const [pageProgress, setPageProgress] = useState<Record<string, number>>({});
 
@@ -112,6 +117,29 @@ export default function EmployeeView() {
     setConnectionStatus("disconnected");
   };
 
+  // 새 세션은 이전 고객의 진행 상황 없이 시작한다
+  const handleSession = (session: Session) => {
+    if (session.sessionId !== sessionIdRef.current) {
+      setAllPageSections({});
+      setLastActiveSection("");
+    }
+    sessionIdRef.current = session.sessionId;
+    setSessionId(session.sessionId);
+  };
+
+  const handleSessionEnd = () => {
+    sessionIdRef.current = null;
+    setSessionId(null);
+  };
+
+  const handleStartSession = () => {
+    websocketService.startSession({
+      branchId: import.meta.env.VITE_BRANCH_ID ?? "",
+      employeeId: import.meta.env.VITE_EMPLOYEE_ID ?? "",
+      productId: PRODUCT_ID,
+    });
+  };
+
   const calculateAllPagesProgress = () => {
     Object.keys(PAGE_SECTIONS).forEach((pageKey) => {
       const pageSections = PAGE_SECTIONS[pageKey];
@@ -151,9 +179,19 @@ export default function EmployeeView() {
     websocketService.onDisconnect(handleDisconnect);
     websocketService.onGazeData(handleGazeData);
     websocketService.onPageChange(handlePageChange);
+    websocketService.onSession(handleSession);
+    websocketService.onSessionEnd(handleSessionEnd);
+    websocketService.connect("employee");
+
+    return () => {
+      websocketService.disconnect();
+    };
   }, []);
 
-  const showOverlay = connectionStatus === "disconnected" || !isCustomerActive;
+  // 세션이 없을 때는 고객 비활성 안내 대신 세션 시작 버튼을 보여준다
+  const showOverlay =
+    connectionStatus === "disconnected" ||
+    (sessionId !== null && !isCustomerActive);
 
   return (
     <div className="w-full h-screen bg-gray-100 relative">
@@ -161,7 +199,7 @@ export default function EmployeeView() {
         showOverlay={showOverlay}
         connectionStatus={connectionStatus}
         onReconnect={() => {
-          websocketService.connect();
+          websocketService.connect("employee");
         }}
       />
 
@@ -171,6 +209,13 @@ export default function EmployeeView() {
       />
 
       <div className="max-w-7xl mx-auto p-6">
+        <SessionControl
+          sessionId={sessionId}
+          connected={connectionStatus === "connected"}
+          onStart={handleStartSession}
+          onEnd={() => websocketService.endSession()}
+        />
+
         <CurrentPageStatus
           currentPage={currentPage}
           pageNames={PAGE_NAMES}
@@ -1,4 +1,5 @@
 import { Link } from "react-router-dom";
+import { kioskUrl } from "../util/kiosk";
 import { useEffect, useRef } from "react";
 
 export default function Home() {
@@ -68,12 +69,12 @@ export default function Home() {
         </p>
 
         <div className="space-y-4 w-full flex flex-col mt-12">
-          <Link
-            to="/customer"
+          <a
+            href={kioskUrl()}
             className="w-full bg-blue-600 hover:bg-blue-700  font-semibold py-3 px-4 rounded-lg shadow transition-colors duration-200"
           >
             <p className="text-center text-white">고객 페이지로 이동하기</p>
-          </Link>
+          </a>
 
           <Link
             to="/employee"
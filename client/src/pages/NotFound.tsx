@@ -1,4 +1,5 @@
 import { Link } from "react-router-dom";
+import { kioskUrl } from "../util/kiosk";
 
 export default function NotFound() {
   return (
@@ -21,12 +22,12 @@ export default function NotFound() {
           >
             홈으로 이동하기
           </Link>
-          <Link
-            to="/customer"
+          <a
+            href={kioskUrl()}
             className="w-full bg-gray-300 hover:bg-gray-200 text-gray-700 font-medium py-3 px-4 rounded-lg transition-colors duration-200"
           >
             고객 페이지로 이동하기
-          </Link>
+          </a>
 
           <Link
             to="/employee"
@@ -1,28 +1,22 @@
+import { StrictMode } from "react";
+import { createRoot } from "react-dom/client";
 import { BrowserRouter as Router, Routes, Route } from "react-router-dom";
-import { useEffect } from "react";
-import { websocketService } from "./util/WebSocketService";
-import CustomerView from "./pages/CustomerView";
 import EmployeeView from "./pages/EmployeeView";
 import NotFound from "./pages/NotFound";
 import Home from "./pages/Home";
+import { websocketService } from "./util/WebSocketService";
 
-export default function App() {
-  useEffect(() => {
-    websocketService.connect();
-
-    return () => {
-      websocketService.disconnect();
-    };
-  }, []);
+// 창구 직원 번들. 고객 화면은 키오스크 번들로 따로 배포한다
+websocketService.setToken("employee", import.meta.env.VITE_EMPLOYEE_TOKEN);
 
-  return (
+createRoot(document.getElementById("root")!).render(
+  <StrictMode>
     <Router>
       <Routes>
         <Route path="/" element={<Home />} />
-        <Route path="/customer" element={<CustomerView />} />
         <Route path="/employee" element={<EmployeeView />} />
         <Route path="*" element={<NotFound />} />
       </Routes>
     </Router>
-  );
-}
+  </StrictMode>
+);
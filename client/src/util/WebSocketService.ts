@@ -12,41 +12,118 @@ export interface PageChangeData {
   timestamp: number;
 }
 
+// 서버가 hello 메시지로 확인하는 연결 역할
+export type Role = "customer" | "employee" | "supervisor";
+
+export interface HelloData {
+  role: Role;
+  token: string;
+}
+
+// 상담 세션 (서버의 models.Session)
+export interface Session {
+  sessionId: string;
+  branchId: string;
+  employeeId: string;
+  productId: string;
+  termsVersion: string;
+  startedAt: string;
+  endedAt?: string;
+}
+
+export interface SessionStartData {
+  branchId: string;
+  employeeId: string;
+  productId: string;
+}
+
 export interface WebSocketMessage {
-  type: "gazeData" | "pageChange" | "gaze" | "status" | "clientCount" | "error";
-  data: GazeData | PageChangeData | string;
+  type:
+    | "helloAck"
+    | "sessionStarted"
+    | "sessionJoined"
+    | "sessionEnded"
+    | "gazeData"
+    | "pageChange"
+    | "gaze"
+    | "status"
+    | "clientCount"
+    | "error";
+  data: GazeData | PageChangeData | Session | string | { role: Role };
 }
 
 class WebSocketService {
   private socket: WebSocket | null = null;
   private reconnectAttempts = 0;
   private maxReconnectAttempts = 5;
+  private role: Role | null = null;
+  // 역할별 접속 토큰. 번들마다 진입점(kiosk.tsx, staff.tsx)이 자기 역할 토큰만 넣는다
+  private tokens: Partial<Record<Role, string>> = {};
+  // 서버가 helloAck로 역할을 확인한 뒤에만 메시지를 보낸다
+  private authenticated = false;
+  // 참여할(참여 중인) 세션. 재연결하면 helloAck 뒤에 같은 세션에 다시 참여한다
+  private sessionId: string | null = null;
+  // 서버가 sessionStarted/sessionJoined로 방 참여를 확인했는지
+  private joined = false;
 
   // 콜백 함수들을 private 속성으로 정의
   private gazeCallback?: (data: GazeData) => void;
   private pageChangeCallback?: (data: PageChangeData) => void;
   private connectCallback?: () => void;
+  private sessionCallback?: (session: Session) => void;
+  private sessionEndCallback?: (session: Session) => void;
   private disconnectCallback?: () => void;
   private errorCallback?: (error: string) => void;
 
-  connect() {
+  setToken(role: Role, token: string | undefined) {
+    if (token) {
+      this.tokens[role] = token;
+    }
+  }
+
+  // 연결 후 첫 메시지로 hello(역할, 토큰)를 보내고, helloAck를 받으면 연결된 것으로 본다
+  connect(role: Role) {
+    this.role = role;
+    const token = this.tokens[role];
+    if (!token) {
+      console.error(`${role} 역할의 접속 토큰이 설정되지 않음`);
+      if (this.errorCallback) {
+        this.errorCallback("접속 토큰 없음");
+      }
+      return;
+    }
+
+    // 다시 연결할 때는 이전 소켓을 먼저 정리한다
+    if (this.socket) {
+      const previous = this.socket;
+      this.socket = null;
+      previous.close();
+    }
+
     try {
       const wsUrl = import.meta.env.DEV
         ? import.meta.env.VITE_WS_URL
         : import.meta.env.VITE_WS_EC2_URL;
 
-      this.socket = new WebSocket(wsUrl);
+      const socket = new WebSocket(wsUrl);
+      this.socket = socket;
+      this.authenticated = false;
+      this.joined = false;
 
-      this.socket.onopen = () => {
-        console.log("✅ WebSocket 연결됨");
-        this.reconnectAttempts = 0;
-        if (this.connectCallback) {
-          this.connectCallback();
-        }
+      socket.onopen = () => {
+        console.log("✅ WebSocket 연결됨, 인증 요청");
+        const hello: HelloData = { role, token };
+        socket.send(JSON.stringify({ type: "hello", data: hello }));
       };
 
-      this.socket.onclose = (event) => {
+      socket.onclose = (event) => {
+        // 끊었거나 새 연결로 바뀐 소켓의 종료는 무시
+        if (this.socket !== socket) {
+          return;
+        }
         console.log("❌ WebSocket 연결 종료", event);
+        this.authenticated = false;
+        this.joined = false;
         if (this.disconnectCallback) {
           this.disconnectCallback();
         }
@@ -57,7 +134,7 @@ class WebSocketService {
         this.attemptReconnect();
       };
 
-      this.socket.onerror = (error) => {
+      socket.onerror = (error) => {
         console.error("WebSocket 에러:", error);
         // 에러 콜백 호출 추가
         if (this.errorCallback) {
@@ -66,7 +143,7 @@ class WebSocketService {
       };
 
       // 메시지 핸들러 설정
-      this.socket.onmessage = this.handleMessage;
+      socket.onmessage = this.handleMessage;
     } catch (error) {
       console.error("WebSocket 연결 실패:", error);
       // 에러 콜백 호출 추가
@@ -85,14 +162,63 @@ class WebSocketService {
     this.disconnectCallback = callback;
   }
 
+  // 세션 방 참여(시작) 리스너
+  onSession(callback: (session: Session) => void) {
+    this.sessionCallback = callback;
+  }
+
+  onSessionEnd(callback: (session: Session) => void) {
+    this.sessionEndCallback = callback;
+  }
+
+  private isReady() {
+    return (
+      this.authenticated &&
+      this.socket !== null &&
+      this.socket.readyState === WebSocket.OPEN
+    );
+  }
+
+  // 서버는 세션 방에 참여한 연결의 시선·페이지 데이터만 받는다
+  private isInSession() {
+    return this.isReady() && this.joined;
+  }
+
+  private send(type: string, data: unknown) {
+    this.socket?.send(JSON.stringify({ type, data }));
+  }
+
+  // 새 상담 세션 시작 (직원). sessionStarted를 받으면 그 세션 방에 참여한 상태가 된다
+  startSession(req: SessionStartData) {
+    if (this.isReady()) {
+      this.send("sessionStart", req);
+    }
+  }
+
+  // 이미 시작된 세션에 참여. 아직 인증 전이면 helloAck를 받은 뒤 참여한다
+  joinSession(sessionId: string) {
+    this.sessionId = sessionId;
+    this.joined = false;
+    if (this.isReady()) {
+      this.send("sessionJoin", { sessionId });
+    }
+  }
+
+  // 참여 중인 세션 종료 (직원)
+  endSession() {
+    if (this.isInSession() && this.sessionId) {
+      this.send("sessionEnd", { sessionId: this.sessionId });
+    }
+  }
+
   // 페이지 변경 데이터 전송
   sendPageChange(currentPage: string) {
-    if (this.socket && this.socket.readyState === WebSocket.OPEN) {
+    if (this.isInSession()) {
       const pageData: PageChangeData = {
         currentPage,
         timestamp: Date.now(),
       };
-      this.socket.send(
+      this.socket?.send(
         JSON.stringify({
           type: "pageChange",
           data: pageData,
@@ -108,7 +234,7 @@ class WebSocketService {
     sectionId?: string | null,
     currentPage?: string
   ) {
-    if (this.socket && this.socket.readyState === WebSocket.OPEN) {
+    if (this.isInSession()) {
       const gazeData: GazeData = {
         x,
         y,
@@ -116,7 +242,7 @@ class WebSocketService {
         currentPage,
         timestamp: Date.now(),
       };
-      this.socket.send(
+      this.socket?.send(
         JSON.stringify({
           type: "gazeData",
           data: gazeData,
@@ -141,18 +267,28 @@ class WebSocketService {
   }
 
   private attemptReconnect() {
-    if (this.reconnectAttempts < this.maxReconnectAttempts) {
+    const role = this.role;
+    if (role && this.reconnectAttempts < this.maxReconnectAttempts) {
       this.reconnectAttempts++;
       setTimeout(() => {
+        // 기다리는 동안 직접 끊었거나 다른 역할로 연결했으면 다시 잇지 않는다
+        if (this.role !== role) {
+          return;
+        }
         console.log(
           `재연결 시도 ${this.reconnectAttempts}/${this.maxReconnectAttempts}`
         );
-        this.connect();
+        this.connect(role);
       }, 2000 * this.reconnectAttempts);
     }
   }
 
+  // 직접 끊은 연결은 다시 잇지 않는다
   disconnect() {
+    this.role = null;
+    this.authenticated = false;
+    this.sessionId = null;
+    this.joined = false;
     if (this.socket) {
       this.socket.close();
       this.socket = null;
@@ -166,6 +302,41 @@ class WebSocketService {
       const message: WebSocketMessage = JSON.parse(event.data);
 
       switch (message.type) {
+        case "helloAck":
+          console.log("🔐 인증 완료:", this.role);
+          this.authenticated = true;
+          this.reconnectAttempts = 0;
+          if (this.sessionId) {
+            this.send("sessionJoin", { sessionId: this.sessionId });
+          }
+          if (this.connectCallback) {
+            this.connectCallback();
+          }
+          break;
+
+        case "sessionStarted":
+        case "sessionJoined": {
+          const session = message.data as Session;
+          console.log("🚪 세션 참여:", session.sessionId);
+          this.sessionId = session.sessionId;
+          this.joined = true;
+          if (this.sessionCallback) {
+            this.sessionCallback(session);
+          }
+          break;
+        }
+
+        case "sessionEnded": {
+          const session = message.data as Session;
+          console.log("🏁 세션 종료:", session.sessionId);
+          this.sessionId = null;
+          this.joined = false;
+          if (this.sessionEndCallback) {
+            this.sessionEndCallback(session);
+          }
+          break;
+        }
+
         case "gazeData":
         case "gaze":
           if (this.gazeCallback && typeof message.data === "object") {
@@ -0,0 +1,10 @@
+// 고객 키오스크는 따로 빌드·배포하므로 직원 화면에서는 설정된 주소로 안내한다
+const kioskOrigin = (
+  import.meta.env.VITE_KIOSK_URL ?? window.location.origin
+).replace(/\/+$/, "");
+
+export function kioskUrl(sessionId?: string) {
+  return sessionId
+    ? `${kioskOrigin}/customer?session=${sessionId}`
+    : `${kioskOrigin}/customer`;
+}
@@ -4,6 +4,14 @@ interface ImportMetaEnv {
   readonly VITE_WS_URL: string;
   readonly VITE_WS_EC2_URL: string;
   readonly VITE_API_URL: string;
+  // 역할별 WebSocket 접속 토큰 (서버 환경 변수와 같은 값). 키오스크 번들은 고객 토큰만 읽는다
+  readonly VITE_CUSTOMER_TOKEN?: string;
+  readonly VITE_EMPLOYEE_TOKEN?: string;
+  // 직원 화면이 안내하는 고객 키오스크 주소 (키오스크는 따로 배포한다)
+  readonly VITE_KIOSK_URL?: string;
+  // 직원 화면이 세션을 시작할 때 기록하는 지점·직원 ID
+  readonly VITE_BRANCH_ID?: string;
+  readonly VITE_EMPLOYEE_ID?: string;
 }
 
 interface ImportMeta {
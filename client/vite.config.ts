@@ -6,8 +6,36 @@
 //   plugins: [react()],
 // });
 
-import { defineConfig } from "vite";
+import { defineConfig, type Plugin } from "vite";
 import tailwindcss from "@tailwindcss/vite";
-export default defineConfig({
-  plugins: [tailwindcss()],
+
+// 고객 키오스크(kiosk)와 창구 직원(staff) 화면은 따로 빌드한다.
+// 각 번들은 자기 진입점만 포함하므로 키오스크 번들에는 고객 토큰만 들어간다.
+const apps = ["kiosk", "staff"] as const;
+type App = (typeof apps)[number];
+
+// index.html의 진입 스크립트를 빌드할 화면의 것으로 바꾼다
+function appEntry(app: App): Plugin {
+  return {
+    name: "app-entry",
+    transformIndexHtml: {
+      order: "pre",
+      handler: (html) =>
+        html.replace(/\/src\/(kiosk|staff)\.tsx/, `/src/${app}.tsx`),
+    },
+  };
+}
+
+export default defineConfig(({ command, mode }) => {
+  const app = apps.find((name) => name === mode);
+  // 빌드는 화면을 반드시 지정한다 (vite build --mode kiosk | staff)
+  if (!app && command === "build") {
+    throw new Error(`빌드할 화면을 --mode ${apps.join(" | ")} 로 지정해야 함`);
+  }
+  // 개발 서버는 --mode kiosk 가 아니면 직원 화면을 띄운다
+  const target = app ?? "staff";
+  return {
+    plugins: [tailwindcss(), appEntry(target)],
+    build: { outDir: `dist/${target}` },
+  };
 });
@@ -0,0 +1,75 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+
+    "shinhan-eyetracking/server/models"
+)
+
+// 보정 품질 지표 (단위 px)
+type CalibrationQuality struct {
+    PointCount  int     // 서로 다른 목표점 수
+    SampleCount int     // 예측 샘플 수 (목표점당 클릭 수만큼)
+    AccuracyPx  float64 // 예측점과 목표점 사이 거리의 평균
+    PrecisionPx float64 // 목표점별 예측점이 자기 중심에서 흩어진 정도 (RMS)
+    MaxErrorPx  float64 // 가장 큰 예측 오차
+}
+
+type calibrationTarget struct {
+    x, y float64
+}
+
+// 클라이언트가 보낸 오차 대신 목표점/예측점에서 직접 계산한다
+func EvaluateCalibration(points []models.CalibrationPoint) (CalibrationQuality, error) {
+    if len(points) == 0 {
+        return CalibrationQuality{}, fmt.Errorf("보정 점이 없음")
+    }
+
+    groups := make(map[calibrationTarget][]models.CalibrationPoint)
+    var order []calibrationTarget
+    var q CalibrationQuality
+    var errorSum float64
+
+    for i, p := range points {
+        for _, v := range []float64{p.TargetX, p.TargetY, p.PredictedX, p.PredictedY} {
+            if math.IsNaN(v) || math.IsInf(v, 0) {
+                return CalibrationQuality{}, fmt.Errorf("보정 점 %d 좌표가 유효하지 않음", i)
+            }
+        }
+
+        d := math.Hypot(p.PredictedX-p.TargetX, p.PredictedY-p.TargetY)
+        errorSum += d
+        q.MaxErrorPx = math.Max(q.MaxErrorPx, d)
+
+        key := calibrationTarget{p.TargetX, p.TargetY}
+        if _, exists := groups[key]; !exists {
+            order = append(order, key)
+        }
+        groups[key] = append(groups[key], p)
+    }
+
+    q.PointCount = len(order)
+    q.SampleCount = len(points)
+    q.AccuracyPx = errorSum / float64(len(points))
+
+    // 목표점마다 예측점 중심을 구하고 중심으로부터의 제곱 거리를 모은다
+    var squareSum float64
+    for _, key := range order {
+        group := groups[key]
+        var cx, cy float64
+        for _, p := range group {
+            cx += p.PredictedX
+            cy += p.PredictedY
+        }
+        cx /= float64(len(group))
+        cy /= float64(len(group))
+        for _, p := range group {
+            dx, dy := p.PredictedX-cx, p.PredictedY-cy
+            squareSum += dx*dx + dy*dy
+        }
+    }
+    q.PrecisionPx = math.Sqrt(squareSum / float64(len(points)))
+
+    return q, nil
+}
@@ -0,0 +1,280 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+
+    "shinhan-eyetracking/server/models"
+)
+
+type Algorithm string
+
+const (
+    // 속도 임계값 기반 (Velocity-Threshold Identification)
+    AlgorithmIVT Algorithm = "ivt"
+    // 분산 임계값 기반 (Dispersion-Threshold Identification)
+    AlgorithmIDT Algorithm = "idt"
+)
+
+type Config struct {
+    Algorithm           Algorithm
+    VelocityThreshold   float64 // I-VT: 이 속도(px/s) 미만이면 고정
+    DispersionThreshold float64 // I-DT: (maxX-minX)+(maxY-minY) 허용치(px)
+    MinFixationMs       int64   // 이보다 짧은 고정은 버린다
+    MaxGapMs            int64   // 샘플 간격이 이보다 길면 추적 끊김으로 보고 고정을 끊는다
+}
+
+func DefaultConfig() Config {
+    return Config{
+        Algorithm:           AlgorithmIDT,
+        VelocityThreshold:   1500,
+        DispersionThreshold: 100,
+        MinFixationMs:       100,
+        MaxGapMs:            500,
+    }
+}
+
+func (c Config) Validate() error {
+    switch c.Algorithm {
+    case AlgorithmIVT:
+        if c.VelocityThreshold <= 0 {
+            return fmt.Errorf("I-VT 속도 임계값은 양수여야 함: %v", c.VelocityThreshold)
+        }
+    case AlgorithmIDT:
+        if c.DispersionThreshold <= 0 {
+            return fmt.Errorf("I-DT 분산 임계값은 양수여야 함: %v", c.DispersionThreshold)
+        }
+    default:
+        return fmt.Errorf("알 수 없는 고정 검출 알고리즘: %q", c.Algorithm)
+    }
+    if c.MaxGapMs <= 0 {
+        return fmt.Errorf("최대 샘플 간격은 양수여야 함: %d", c.MaxGapMs)
+    }
+    return nil
+}
+
+type Fixation struct {
+    SessionID   string  `json:"sessionId"`
+    PageID      string  `json:"pageId"`
+    SectionID   string  `json:"sectionId,omitempty"`
+    X           float64 `json:"x"` // 중심점
+    Y           float64 `json:"y"`
+    StartMs     int64   `json:"start"`
+    DurationMs  int64   `json:"duration"`
+    SampleCount int     `json:"sampleCount"`
+}
+
+func (f Fixation) EndMs() int64 {
+    return f.StartMs + f.DurationMs
+}
+
+type Saccade struct {
+    SessionID  string  `json:"sessionId"`
+    PageID     string  `json:"pageId"`
+    FromX      float64 `json:"fromX"`
+    FromY      float64 `json:"fromY"`
+    ToX        float64 `json:"toX"`
+    ToY        float64 `json:"toY"`
+    StartMs    int64   `json:"start"`
+    DurationMs int64   `json:"duration"`
+    Amplitude  float64 `json:"amplitude"` // px
+}
+
+type Events struct {
+    Fixations []Fixation
+    Saccades  []Saccade
+}
+
+func (e *Events) append(other Events) {
+    e.Fixations = append(e.Fixations, other.Fixations...)
+    e.Saccades = append(e.Saccades, other.Saccades...)
+}
+
+// 샘플을 하나씩 받아 완료된 고정/도약을 돌려주는 실시간 검출기. 세션마다 하나씩 사용
+type Detector struct {
+    cfg    Config
+    window []models.GazeData
+    // I-DT: 현재 창이 고정으로 확정되었는지
+    inFixation bool
+    // 도약 계산을 위한 직전 고정
+    lastFixation *Fixation
+}
+
+func NewDetector(cfg Config) *Detector {
+    return &Detector{cfg: cfg}
+}
+
+func (d *Detector) Add(sample models.GazeData) Events {
+    var events Events
+
+    if n := len(d.window); n > 0 {
+        prev := d.window[n-1]
+        // 시간이 역행한 샘플은 무시
+        if sample.Timestamp < prev.Timestamp {
+            return events
+        }
+        // 추적 끊김이나 페이지 전환은 고정을 끊는다
+        if sample.Timestamp-prev.Timestamp > d.cfg.MaxGapMs || pageOf(sample) != pageOf(prev) {
+            events.append(d.Flush())
+        }
+    }
+
+    switch d.cfg.Algorithm {
+    case AlgorithmIVT:
+        events.append(d.addIVT(sample))
+    default:
+        events.append(d.addIDT(sample))
+    }
+    return events
+}
+
+// 진행 중인 고정을 마무리 (페이지 전환, 세션 종료 시)
+func (d *Detector) Flush() Events {
+    var events Events
+    if d.inFixation || d.cfg.Algorithm == AlgorithmIVT {
+        events.append(d.emit(d.window))
+    }
+    d.window = nil
+    d.inFixation = false
+    // 끊긴 뒤에는 이전 고정과 도약으로 잇지 않는다
+    d.lastFixation = nil
+    return events
+}
+
+func (d *Detector) addIVT(sample models.GazeData) Events {
+    var events Events
+    n := len(d.window)
+    if n == 0 {
+        d.window = append(d.window, sample)
+        return events
+    }
+
+    prev := d.window[n-1]
+    dt := sample.Timestamp - prev.Timestamp
+    velocity := math.Inf(1)
+    if dt > 0 {
+        velocity = distance(prev.X, prev.Y, sample.X, sample.Y) / float64(dt) * 1000
+    }
+
+    if velocity < d.cfg.VelocityThreshold {
+        d.window = append(d.window, sample)
+        return events
+    }
+
+    // 빠른 이동: 지금까지의 고정을 마무리하고 새 샘플부터 다시 시작
+    events.append(d.emit(d.window))
+    d.window = []models.GazeData{sample}
+    return events
+}
+
+func (d *Detector) addIDT(sample models.GazeData) Events {
+    var events Events
+    d.window = append(d.window, sample)
+
+    if d.inFixation {
+        if dispersion(d.window) <= d.cfg.DispersionThreshold {
+            return events
+        }
+        // 분산 초과: 마지막 샘플을 제외한 창을 고정으로 확정
+        events.append(d.emit(d.window[:len(d.window)-1]))
+        d.window = []models.GazeData{sample}
+        d.inFixation = false
+        return events
+    }
+
+    // 최소 고정 시간만큼 창이 채워질 때까지 대기
+    for len(d.window) > 0 && span(d.window) >= d.cfg.MinFixationMs {
+        if dispersion(d.window) <= d.cfg.DispersionThreshold {
+            d.inFixation = true
+            break
+        }
+        // 창 시작점이 고정이 아니면 한 칸 민다
+        d.window = d.window[1:]
+    }
+    return events
+}
+
+func (d *Detector) emit(samples []models.GazeData) Events {
+    var events Events
+    if len(samples) == 0 || span(samples) < d.cfg.MinFixationMs {
+        return events
+    }
+
+    fixation := centroid(samples)
+    if last := d.lastFixation; last != nil && last.PageID == fixation.PageID {
+        events.Saccades = append(events.Saccades, Saccade{
+            SessionID:  fixation.SessionID,
+            PageID:     fixation.PageID,
+            FromX:      last.X,
+            FromY:      last.Y,
+            ToX:        fixation.X,
+            ToY:        fixation.Y,
+            StartMs:    last.EndMs(),
+            DurationMs: fixation.StartMs - last.EndMs(),
+            Amplitude:  distance(last.X, last.Y, fixation.X, fixation.Y),
+        })
+    }
+
+    events.Fixations = append(events.Fixations, fixation)
+    d.lastFixation = &fixation
+    return events
+}
+
+// 중심점과 가장 많이 본 섹션
+func centroid(samples []models.GazeData) Fixation {
+    var sumX, sumY float64
+    sectionVotes := make(map[string]int)
+    bestSection, bestVotes := "", 0
+
+    for _, s := range samples {
+        sumX += s.X
+        sumY += s.Y
+        section := stringValue(s.SectionID)
+        sectionVotes[section]++
+        if sectionVotes[section] > bestVotes {
+            bestSection, bestVotes = section, sectionVotes[section]
+        }
+    }
+
+    first := samples[0]
+    n := float64(len(samples))
+    return Fixation{
+        SessionID:   first.SessionID,
+        PageID:      pageOf(first),
+        SectionID:   bestSection,
+        X:           sumX / n,
+        Y:           sumY / n,
+        StartMs:     first.Timestamp,
+        DurationMs:  span(samples),
+        SampleCount: len(samples),
+    }
+}
+
+func dispersion(samples []models.GazeData) float64 {
+    minX, maxX := samples[0].X, samples[0].X
+    minY, maxY := samples[0].Y, samples[0].Y
+    for _, s := range samples[1:] {
+        minX, maxX = math.Min(minX, s.X), math.Max(maxX, s.X)
+        minY, maxY = math.Min(minY, s.Y), math.Max(maxY, s.Y)
+    }
+    return (maxX - minX) + (maxY - minY)
+}
+
+func span(samples []models.GazeData) int64 {
+    return samples[len(samples)-1].Timestamp - samples[0].Timestamp
+}
+
+func distance(x1, y1, x2, y2 float64) float64 {
+    return math.Hypot(x2-x1, y2-y1)
+}
+
+func pageOf(s models.GazeData) string {
+    return stringValue(s.CurrentPage)
+}
+
+func stringValue(s *string) string {
+    if s == nil {
+        return ""
+    }
+    return *s
+}
@@ -0,0 +1,116 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "shinhan-eyetracking/server/models"
+)
+
+// 50Hz로 (x, y) 근처를 약간 흔들리며 바라보는 샘플
+func steadyGaze(startMs int64, count int, x, y float64) []models.GazeData {
+    page := "productDetail"
+    samples := make([]models.GazeData, count)
+    for i := range samples {
+        jitter := float64(i%3) - 1
+        samples[i] = models.GazeData{
+            X:           x + jitter,
+            Y:           y - jitter,
+            Timestamp:   startMs + int64(i)*20,
+            CurrentPage: &page,
+            SessionID:   "s1",
+        }
+    }
+    return samples
+}
+
+func detect(cfg Config, samples []models.GazeData) Events {
+    d := NewDetector(cfg)
+    var events Events
+    for _, s := range samples {
+        events.append(d.Add(s))
+    }
+    events.append(d.Flush())
+    return events
+}
+
+func algorithmConfigs() map[string]Config {
+    ivt := DefaultConfig()
+    ivt.Algorithm = AlgorithmIVT
+    return map[string]Config{
+        "I-DT": DefaultConfig(),
+        "I-VT": ivt,
+    }
+}
+
+func TestDetectorSplitsFixationsAtSaccade(t *testing.T) {
+    // 0~180ms는 (100, 100), 200~380ms는 (500, 400)을 본다
+    samples := append(steadyGaze(0, 10, 100, 100), steadyGaze(200, 10, 500, 400)...)
+
+    for name, cfg := range algorithmConfigs() {
+        t.Run(name, func(t *testing.T) {
+            events := detect(cfg, samples)
+
+            if len(events.Fixations) != 2 {
+                t.Fatalf("고정 %d개, 기대값 2개: %+v", len(events.Fixations), events.Fixations)
+            }
+            first, second := events.Fixations[0], events.Fixations[1]
+            if first.StartMs != 0 || first.DurationMs != 180 || first.SampleCount != 10 {
+                t.Fatalf("첫 고정 구간 이상: %+v", first)
+            }
+            if second.StartMs != 200 || second.DurationMs != 180 || second.SampleCount != 10 {
+                t.Fatalf("두 번째 고정 구간 이상: %+v", second)
+            }
+            if math.Abs(first.X-100) > 1 || math.Abs(second.Y-400) > 1 {
+                t.Fatalf("고정 중심점 이상: %+v, %+v", first, second)
+            }
+
+            if len(events.Saccades) != 1 {
+                t.Fatalf("도약 %d개, 기대값 1개: %+v", len(events.Saccades), events.Saccades)
+            }
+            saccade := events.Saccades[0]
+            if saccade.StartMs != 180 || saccade.DurationMs != 20 {
+                t.Fatalf("도약 구간 이상: %+v", saccade)
+            }
+            if want := distance(first.X, first.Y, second.X, second.Y); math.Abs(saccade.Amplitude-want) > 1e-9 {
+                t.Fatalf("도약 거리 = %v, 기대값 %v", saccade.Amplitude, want)
+            }
+        })
+    }
+}
+
+func TestDetectorDropsShortFixations(t *testing.T) {
+    // 80ms만 머문 곳은 최소 고정 시간(100ms)에 못 미친다
+    samples := append(steadyGaze(0, 5, 100, 100), steadyGaze(100, 10, 500, 400)...)
+
+    for name, cfg := range algorithmConfigs() {
+        t.Run(name, func(t *testing.T) {
+            events := detect(cfg, samples)
+
+            if len(events.Fixations) != 1 || events.Fixations[0].StartMs != 100 {
+                t.Fatalf("긴 고정 하나만 남아야 함: %+v", events.Fixations)
+            }
+            if len(events.Saccades) != 0 {
+                t.Fatalf("버린 고정과 도약으로 이으면 안 됨: %+v", events.Saccades)
+            }
+        })
+    }
+}
+
+func TestDetectorBreaksFixationOnTrackingGap(t *testing.T) {
+    // 같은 자리라도 최대 샘플 간격(500ms)을 넘겨 끊기면 다른 고정이고, 도약으로 잇지 않는다
+    samples := append(steadyGaze(0, 10, 100, 100), steadyGaze(1000, 10, 100, 100)...)
+
+    for name, cfg := range algorithmConfigs() {
+        t.Run(name, func(t *testing.T) {
+            events := detect(cfg, samples)
+
+            if len(events.Fixations) != 2 || events.Fixations[1].StartMs != 1000 {
+                t.Fatalf("끊긴 지점에서 고정이 나뉘어야 함: %+v", events.Fixations)
+            }
+            if len(events.Saccades) != 0 {
+                t.Fatalf("추적이 끊긴 고정을 도약으로 이음: %+v", events.Saccades)
+            }
+        })
+    }
+}
@@ -0,0 +1,184 @@
+package analysis
+
+import (
+    "fmt"
+    "image"
+    "image/color"
+    "math"
+
+    "shinhan-eyetracking/server/models"
+)
+
+type HeatmapOptions struct {
+    Width  int     // 출력 이미지 너비 (px)
+    Height int     // 출력 이미지 높이 (px)
+    Sigma  float64 // 가우시안 반경 (출력 px)
+}
+
+func DefaultHeatmapOptions() HeatmapOptions {
+    return HeatmapOptions{
+        Width:  1920,
+        Height: 1080,
+        Sigma:  40,
+    }
+}
+
+const (
+    // 밀도 누적은 이 크기의 격자 단위로 계산한 뒤 원본 해상도로 보간한다
+    heatmapCell = 4
+    // 출력 크기 상한. 요청 하나가 NRGBA 이미지로 약 16MB 넘게 잡지 않도록 한다
+    maxHeatmapSide   = 4096
+    maxHeatmapPixels = 4 << 20
+)
+
+// 출력 크기와 가우시안 반경 확인. 블러 비용이 격자 칸 수 × 커널 크기라 반경은 출력의 긴 변까지만 받는다
+func (o HeatmapOptions) Validate() error {
+    if o.Width <= 0 || o.Height <= 0 || o.Width > maxHeatmapSide || o.Height > maxHeatmapSide ||
+        o.Width*o.Height > maxHeatmapPixels {
+        return fmt.Errorf("잘못된 히트맵 크기: %dx%d (최대 %d px, 한 변 %d px)",
+            o.Width, o.Height, maxHeatmapPixels, maxHeatmapSide)
+    }
+    maxSigma := float64(max(o.Width, o.Height))
+    if math.IsNaN(o.Sigma) || math.IsInf(o.Sigma, 0) || o.Sigma <= 0 || o.Sigma > maxSigma {
+        return fmt.Errorf("가우시안 반경은 0보다 크고 %v px 이하여야 함: %v", maxSigma, o.Sigma)
+    }
+    return nil
+}
+
+// 시선 좌표를 가우시안으로 누적한 밀도 히트맵. 투명 배경 위에 파랑→빨강으로 칠한다.
+// 뷰포트가 기록된 샘플은 뷰포트 대비 비율로 출력 크기에 맞추고, 없는 샘플은 출력 좌표계의 px로 본다
+func RenderHeatmap(points []models.GazeData, opts HeatmapOptions) (*image.NRGBA, error) {
+    if err := opts.Validate(); err != nil {
+        return nil, err
+    }
+
+    gw := (opts.Width + heatmapCell - 1) / heatmapCell
+    gh := (opts.Height + heatmapCell - 1) / heatmapCell
+    grid := make([]float64, gw*gh)
+
+    // 격자에 점 개수 누적 (화면 밖 좌표는 제외)
+    for _, p := range points {
+        x, y := p.X, p.Y
+        if math.IsNaN(x) || math.IsNaN(y) {
+            continue
+        }
+        if p.ViewportWidth > 0 && p.ViewportHeight > 0 {
+            x = x / float64(p.ViewportWidth) * float64(opts.Width)
+            y = y / float64(p.ViewportHeight) * float64(opts.Height)
+        }
+        gx := int(x) / heatmapCell
+        gy := int(y) / heatmapCell
+        if x < 0 || y < 0 || gx >= gw || gy >= gh {
+            continue
+        }
+        grid[gy*gw+gx]++
+    }
+
+    // 가로·세로 분리 가우시안 블러
+    kernel := gaussianKernel(opts.Sigma / heatmapCell)
+    grid = convolve(grid, gw, gh, kernel, true)
+    grid = convolve(grid, gw, gh, kernel, false)
+
+    maxValue := 0.0
+    for _, v := range grid {
+        maxValue = math.Max(maxValue, v)
+    }
+
+    img := image.NewNRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+    if maxValue == 0 {
+        return img, nil
+    }
+
+    for y := 0; y < opts.Height; y++ {
+        for x := 0; x < opts.Width; x++ {
+            v := sampleBilinear(grid, gw, gh, (float64(x)+0.5)/heatmapCell-0.5, (float64(y)+0.5)/heatmapCell-0.5)
+            img.SetNRGBA(x, y, heatColor(v/maxValue))
+        }
+    }
+    return img, nil
+}
+
+func gaussianKernel(sigma float64) []float64 {
+    if sigma < 0.5 {
+        sigma = 0.5
+    }
+    radius := int(math.Ceil(sigma * 3))
+    kernel := make([]float64, 2*radius+1)
+    for i := range kernel {
+        d := float64(i - radius)
+        kernel[i] = math.Exp(-(d * d) / (2 * sigma * sigma))
+    }
+    return kernel
+}
+
+func convolve(src []float64, w, h int, kernel []float64, horizontal bool) []float64 {
+    dst := make([]float64, len(src))
+    radius := len(kernel) / 2
+
+    for y := 0; y < h; y++ {
+        for x := 0; x < w; x++ {
+            v := src[y*w+x]
+            if v == 0 {
+                continue
+            }
+            // 값이 있는 칸만 주변으로 퍼뜨린다 (희소한 격자에서 빠름)
+            for k, weight := range kernel {
+                tx, ty := x, y
+                if horizontal {
+                    tx += k - radius
+                } else {
+                    ty += k - radius
+                }
+                if tx < 0 || ty < 0 || tx >= w || ty >= h {
+                    continue
+                }
+                dst[ty*w+tx] += v * weight
+            }
+        }
+    }
+    return dst
+}
+
+func sampleBilinear(grid []float64, w, h int, fx, fy float64) float64 {
+    fx = math.Max(0, math.Min(fx, float64(w-1)))
+    fy = math.Max(0, math.Min(fy, float64(h-1)))
+    x0, y0 := int(fx), int(fy)
+    x1, y1 := min(x0+1, w-1), min(y0+1, h-1)
+    tx, ty := fx-float64(x0), fy-float64(y0)
+
+    top := grid[y0*w+x0]*(1-tx) + grid[y0*w+x1]*tx
+    bottom := grid[y1*w+x0]*(1-tx) + grid[y1*w+x1]*tx
+    return top*(1-ty) + bottom*ty
+}
+
+// 0~1 밀도를 파랑→초록→노랑→빨강 그라데이션으로. 밀도가 낮을수록 투명
+func heatColor(v float64) color.NRGBA {
+    if v <= 0.01 {
+        return color.NRGBA{}
+    }
+
+    stops := []struct {
+        at      float64
+        r, g, b float64
+    }{
+        {0.0, 0, 0, 255},
+        {0.35, 0, 255, 0},
+        {0.65, 255, 255, 0},
+        {1.0, 255, 0, 0},
+    }
+
+    r, g, b := stops[len(stops)-1].r, stops[len(stops)-1].g, stops[len(stops)-1].b
+    for i := 1; i < len(stops); i++ {
+        if v <= stops[i].at {
+            lo, hi := stops[i-1], stops[i]
+            t := (v - lo.at) / (hi.at - lo.at)
+            r = lo.r + (hi.r-lo.r)*t
+            g = lo.g + (hi.g-lo.g)*t
+            b = lo.b + (hi.b-lo.b)*t
+            break
+        }
+    }
+
+    alpha := math.Min(1, v*1.5) * 200
+    return color.NRGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: uint8(alpha)}
+}
@@ -0,0 +1,68 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+
+    "shinhan-eyetracking/server/models"
+)
+
+func TestHeatmapOptionsValidate(t *testing.T) {
+    cases := []struct {
+        name  string
+        opts  HeatmapOptions
+        valid bool
+    }{
+        {"기본값", DefaultHeatmapOptions(), true},
+        {"긴 변과 같은 반경", HeatmapOptions{Width: 800, Height: 600, Sigma: 800}, true},
+        {"긴 변보다 큰 반경", HeatmapOptions{Width: 800, Height: 600, Sigma: 801}, false},
+        {"아주 큰 반경", HeatmapOptions{Width: 1920, Height: 1080, Sigma: 20000}, false},
+        {"0 반경", HeatmapOptions{Width: 800, Height: 600, Sigma: 0}, false},
+        {"음수 반경", HeatmapOptions{Width: 800, Height: 600, Sigma: -1}, false},
+        {"NaN 반경", HeatmapOptions{Width: 800, Height: 600, Sigma: math.NaN()}, false},
+        {"무한대 반경", HeatmapOptions{Width: 800, Height: 600, Sigma: math.Inf(1)}, false},
+        {"0 너비", HeatmapOptions{Width: 0, Height: 600, Sigma: 40}, false},
+        {"한 변 상한 초과", HeatmapOptions{Width: maxHeatmapSide + 1, Height: 10, Sigma: 40}, false},
+        {"픽셀 수 상한 초과", HeatmapOptions{Width: maxHeatmapSide, Height: maxHeatmapSide, Sigma: 40}, false},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            err := tc.opts.Validate()
+            if tc.valid && err != nil {
+                t.Fatalf("정상 옵션을 거부: %v", err)
+            }
+            if !tc.valid && err == nil {
+                t.Fatalf("잘못된 옵션을 받아들임: %+v", tc.opts)
+            }
+            if _, renderErr := RenderHeatmap(nil, tc.opts); (renderErr == nil) != tc.valid {
+                t.Fatalf("RenderHeatmap 결과가 Validate와 다름: %v", renderErr)
+            }
+        })
+    }
+}
+
+// 뷰포트가 기록된 샘플은 뷰포트 대비 비율로 출력 크기에 맞춘다
+func TestRenderHeatmapNormalizesByViewport(t *testing.T) {
+    opts := HeatmapOptions{Width: 400, Height: 200, Sigma: 8}
+    points := []models.GazeData{
+        // 1920x1080 화면의 정중앙
+        {X: 960, Y: 540, ViewportWidth: 1920, ViewportHeight: 1080},
+        // 뷰포트 없는 샘플은 출력 좌표 그대로
+        {X: 50, Y: 50},
+    }
+
+    img, err := RenderHeatmap(points, opts)
+    if err != nil {
+        t.Fatalf("히트맵 생성 실패: %v", err)
+    }
+
+    for _, p := range []struct{ x, y int }{{200, 100}, {50, 50}} {
+        if a := img.NRGBAAt(p.x, p.y).A; a == 0 {
+            t.Fatalf("(%d, %d)에 밀도가 없음", p.x, p.y)
+        }
+    }
+    if a := img.NRGBAAt(350, 20).A; a != 0 {
+        t.Fatalf("시선이 없는 곳에 밀도가 있음: alpha %d", a)
+    }
+}
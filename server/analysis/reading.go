@@ -0,0 +1,159 @@
+package analysis
+
+import (
+    "fmt"
+    "math"
+)
+
+type ReadingLabel string
+
+const (
+    LabelReading  ReadingLabel = "reading"  // 줄을 따라 읽어 내려감
+    LabelSkimming ReadingLabel = "skimming" // 훑어봄
+    LabelStaring  ReadingLabel = "staring"  // 한 곳만 응시
+)
+
+type ReadingConfig struct {
+    LineHeight      float64 // 한 줄 높이 (px)
+    MinForwardRatio float64 // 읽기로 보기 위한 좌→우 진행 비율
+    MinCoverage     float64 // 읽기로 보기 위한 세로 커버리지 (훑은 줄 수 / 섹션 높이의 줄 수)
+    ReturnMinDx     float64 // 줄 바꿈으로 볼 최소 왼쪽 이동 거리 (px)
+    StareMinMs      int64   // 응시로 보기 위한 최소 방문 시간
+}
+
+func DefaultReadingConfig() ReadingConfig {
+    return ReadingConfig{
+        LineHeight:      28,
+        MinForwardRatio: 0.4,
+        MinCoverage:     0.5,
+        ReturnMinDx:     150,
+        StareMinMs:      1000,
+    }
+}
+
+func (c ReadingConfig) Validate() error {
+    if !(c.LineHeight > 0) || math.IsInf(c.LineHeight, 0) {
+        return fmt.Errorf("줄 높이는 양수여야 함: %v", c.LineHeight)
+    }
+    if !(c.MinForwardRatio >= 0 && c.MinForwardRatio <= 1) {
+        return fmt.Errorf("좌→우 진행 비율은 0~1이어야 함: %v", c.MinForwardRatio)
+    }
+    if !(c.MinCoverage >= 0 && c.MinCoverage <= 1) {
+        return fmt.Errorf("세로 커버리지는 0~1이어야 함: %v", c.MinCoverage)
+    }
+    if !(c.ReturnMinDx >= 0) {
+        return fmt.Errorf("줄 바꿈 최소 이동 거리는 음수일 수 없음: %v", c.ReturnMinDx)
+    }
+    if c.StareMinMs < 0 {
+        return fmt.Errorf("응시 최소 시간은 음수일 수 없음: %d", c.StareMinMs)
+    }
+    return nil
+}
+
+// 한 섹션에 연속으로 머문 구간
+type Visit struct {
+    SessionID string     `json:"sessionId"`
+    PageID    string     `json:"pageId"`
+    SectionID string     `json:"sectionId"`
+    Fixations []Fixation `json:"-"`
+}
+
+type VisitClassification struct {
+    PageID        string       `json:"pageId"`
+    SectionID     string       `json:"sectionId"`
+    StartMs       int64        `json:"start"`
+    DurationMs    int64        `json:"duration"` // 고정 시간의 합
+    FixationCount int          `json:"fixationCount"`
+    ForwardRatio  float64      `json:"forwardRatio"`
+    LineReturns   int          `json:"lineReturns"`
+    LinesCovered  int          `json:"linesCovered"`
+    Coverage      float64      `json:"coverage"`
+    Label         ReadingLabel `json:"label"`
+}
+
+// 시간순 고정 목록을 섹션 방문 단위로 나눈다. 섹션 밖 고정은 방문을 끊는다
+func SplitVisits(fixations []Fixation) []Visit {
+    var visits []Visit
+    var current *Visit
+
+    for _, f := range fixations {
+        if f.SectionID == "" {
+            current = nil
+            continue
+        }
+        if current == nil || current.PageID != f.PageID || current.SectionID != f.SectionID {
+            visits = append(visits, Visit{
+                SessionID: f.SessionID,
+                PageID:    f.PageID,
+                SectionID: f.SectionID,
+            })
+            current = &visits[len(visits)-1]
+        }
+        current.Fixations = append(current.Fixations, f)
+    }
+    return visits
+}
+
+// sectionLines는 섹션 본문 높이를 cfg.LineHeight 줄 단위로 잰 값 (세로 커버리지의 분모). 0이면 커버리지를 따지지 않는다
+func ClassifyVisit(visit Visit, sectionLines int, cfg ReadingConfig) VisitClassification {
+    fixations := visit.Fixations
+    result := VisitClassification{
+        PageID:        visit.PageID,
+        SectionID:     visit.SectionID,
+        FixationCount: len(fixations),
+    }
+    if len(fixations) == 0 {
+        result.Label = LabelSkimming
+        return result
+    }
+
+    result.StartMs = fixations[0].StartMs
+    lines := make(map[int]bool)
+    forward := 0
+
+    for i, f := range fixations {
+        result.DurationMs += f.DurationMs
+        lines[int(math.Floor(f.Y/cfg.LineHeight))] = true
+
+        if i == 0 {
+            continue
+        }
+        dx := f.X - fixations[i-1].X
+        dy := f.Y - fixations[i-1].Y
+
+        switch {
+        // 같은 줄에서 오른쪽으로 진행
+        case dx > 0 && math.Abs(dy) < cfg.LineHeight/2:
+            forward++
+        // 왼쪽 끝으로 돌아가며 아래 줄로 이동
+        case dx <= -cfg.ReturnMinDx && dy >= cfg.LineHeight/2 && dy <= cfg.LineHeight*2.5:
+            result.LineReturns++
+        }
+    }
+
+    if transitions := len(fixations) - 1; transitions > 0 {
+        result.ForwardRatio = float64(forward) / float64(transitions)
+    }
+    result.LinesCovered = len(lines)
+    if sectionLines > 0 {
+        result.Coverage = math.Min(1, float64(result.LinesCovered)/float64(sectionLines))
+    } else {
+        result.Coverage = 1
+    }
+
+    result.Label = classify(result, cfg)
+    return result
+}
+
+func classify(v VisitClassification, cfg ReadingConfig) ReadingLabel {
+    // 한 줄 안에서 거의 움직이지 않고 오래 머묾
+    if v.LinesCovered <= 1 && v.LineReturns == 0 && v.DurationMs >= cfg.StareMinMs &&
+        (v.FixationCount <= 2 || v.ForwardRatio < cfg.MinForwardRatio) {
+        return LabelStaring
+    }
+    if v.ForwardRatio >= cfg.MinForwardRatio && v.Coverage >= cfg.MinCoverage &&
+        (v.LineReturns > 0 || v.LinesCovered >= 2) {
+        return LabelReading
+    }
+    return LabelSkimming
+}
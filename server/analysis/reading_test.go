@@ -0,0 +1,169 @@
+package analysis
+
+import (
+    "math"
+    "testing"
+)
+
+// (x, y) 위치마다 durationMs씩 머문 섹션 방문
+func visitOf(durationMs int64, points ...[2]float64) Visit {
+    visit := Visit{SessionID: "s1", PageID: "productJoin", SectionID: "risk-warning"}
+    for i, p := range points {
+        visit.Fixations = append(visit.Fixations, Fixation{
+            SessionID:  "s1",
+            PageID:     "productJoin",
+            SectionID:  "risk-warning",
+            X:          p[0],
+            Y:          p[1],
+            StartMs:    int64(i) * (durationMs + 50),
+            DurationMs: durationMs,
+        })
+    }
+    return visit
+}
+
+// top부터 lines줄을 한 줄에 네 번씩 왼쪽에서 오른쪽으로 읽어 내려가는 고정 위치
+func readLines(top float64, lines int, lineHeight float64) [][2]float64 {
+    var points [][2]float64
+    for line := 0; line < lines; line++ {
+        y := top + float64(line)*lineHeight
+        for _, x := range []float64{100, 250, 400, 550} {
+            points = append(points, [2]float64{x, y})
+        }
+    }
+    return points
+}
+
+func TestClassifyVisit(t *testing.T) {
+    cfg := DefaultReadingConfig()
+
+    cases := []struct {
+        name         string
+        visit        Visit
+        sectionLines int
+        label        ReadingLabel
+        lineReturns  int
+        linesCovered int
+        coverage     float64
+    }{
+        {"세 줄을 읽어 내려감", visitOf(200, readLines(100, 3, 28)...), 5, LabelReading, 2, 3, 0.6},
+        {"읽었지만 섹션의 일부만", visitOf(200, readLines(100, 3, 28)...), 9, LabelSkimming, 2, 3, 1.0 / 3},
+        {"섹션 높이를 모르면 커버리지를 따지지 않음", visitOf(200, readLines(100, 2, 28)...), 0, LabelReading, 1, 2, 1},
+        {"여기저기 훑어봄", visitOf(200, [2]float64{500, 100}, [2]float64{100, 300}, [2]float64{400, 50}, [2]float64{200, 250}), 5, LabelSkimming, 0, 4, 0.8},
+        {"줄을 건너뛴 왼쪽 이동은 줄 바꿈이 아님", visitOf(200, [2]float64{100, 100}, [2]float64{550, 100}, [2]float64{100, 200}, [2]float64{550, 200}), 5, LabelSkimming, 0, 2, 0.4},
+        {"한 곳을 오래 응시", visitOf(500, [2]float64{300, 100}, [2]float64{300, 102}, [2]float64{300, 99}), 5, LabelStaring, 0, 1, 0.2},
+        {"한 곳을 잠깐 봄", visitOf(300, [2]float64{300, 100}), 5, LabelSkimming, 0, 1, 0.2},
+        {"고정 없음", Visit{SectionID: "risk-warning"}, 5, LabelSkimming, 0, 0, 0},
+    }
+
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            got := ClassifyVisit(c.visit, c.sectionLines, cfg)
+
+            if got.Label != c.label {
+                t.Fatalf("분류 = %s, 기대값 %s: %+v", got.Label, c.label, got)
+            }
+            if got.LineReturns != c.lineReturns || got.LinesCovered != c.linesCovered {
+                t.Fatalf("줄 바꿈 %d회·훑은 줄 %d줄, 기대값 %d회·%d줄", got.LineReturns, got.LinesCovered, c.lineReturns, c.linesCovered)
+            }
+            if math.Abs(got.Coverage-c.coverage) > 1e-9 {
+                t.Fatalf("커버리지 = %v, 기대값 %v", got.Coverage, c.coverage)
+            }
+            if got.FixationCount != len(c.visit.Fixations) {
+                t.Fatalf("고정 %d개, 기대값 %d개", got.FixationCount, len(c.visit.Fixations))
+            }
+        })
+    }
+}
+
+func TestClassifyThresholds(t *testing.T) {
+    cfg := DefaultReadingConfig()
+
+    cases := []struct {
+        name  string
+        visit VisitClassification
+        label ReadingLabel
+    }{
+        {"응시 최소 시간과 같음", VisitClassification{FixationCount: 2, DurationMs: 1000, LinesCovered: 1}, LabelStaring},
+        {"응시 최소 시간 미만", VisitClassification{FixationCount: 2, DurationMs: 999, LinesCovered: 1}, LabelSkimming},
+        {"한 줄이라도 오른쪽으로 진행하면 응시가 아님", VisitClassification{FixationCount: 5, DurationMs: 2000, LinesCovered: 1, ForwardRatio: 0.5, Coverage: 1}, LabelSkimming},
+        {"줄 바꿈이 있으면 응시가 아님", VisitClassification{FixationCount: 2, DurationMs: 2000, LinesCovered: 1, LineReturns: 1}, LabelSkimming},
+        {"줄 바꿈 없이 두 줄 진행", VisitClassification{FixationCount: 6, DurationMs: 1200, LinesCovered: 2, ForwardRatio: 0.4, Coverage: 0.5}, LabelReading},
+        {"진행 비율 부족", VisitClassification{FixationCount: 6, DurationMs: 1200, LinesCovered: 3, LineReturns: 2, ForwardRatio: 0.39, Coverage: 1}, LabelSkimming},
+        {"커버리지 부족", VisitClassification{FixationCount: 6, DurationMs: 1200, LinesCovered: 3, LineReturns: 2, ForwardRatio: 0.8, Coverage: 0.49}, LabelSkimming},
+    }
+
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            if got := classify(c.visit, cfg); got != c.label {
+                t.Fatalf("분류 = %s, 기대값 %s", got, c.label)
+            }
+        })
+    }
+}
+
+// 섹션이나 페이지가 바뀌거나 섹션 밖을 보면 방문이 끊긴다
+func TestSplitVisits(t *testing.T) {
+    fixation := func(page, section string) Fixation {
+        return Fixation{SessionID: "s1", PageID: page, SectionID: section}
+    }
+    fixations := []Fixation{
+        fixation("productJoin", "risk-warning"),
+        fixation("productJoin", "risk-warning"),
+        fixation("productJoin", "fee-info"),
+        fixation("productJoin", ""),
+        fixation("productJoin", "fee-info"),
+        fixation("productDetail", "fee-info"),
+    }
+    want := []struct {
+        page, section string
+        count         int
+    }{
+        {"productJoin", "risk-warning", 2},
+        {"productJoin", "fee-info", 1},
+        {"productJoin", "fee-info", 1},
+        {"productDetail", "fee-info", 1},
+    }
+
+    visits := SplitVisits(fixations)
+    if len(visits) != len(want) {
+        t.Fatalf("방문 %d개, 기대값 %d개: %+v", len(visits), len(want), visits)
+    }
+    for i, w := range want {
+        v := visits[i]
+        if v.PageID != w.page || v.SectionID != w.section || len(v.Fixations) != w.count || v.SessionID != "s1" {
+            t.Fatalf("%d번째 방문 = %s/%s 고정 %d개, 기대값 %s/%s %d개", i, v.PageID, v.SectionID, len(v.Fixations), w.page, w.section, w.count)
+        }
+    }
+
+    if visits := SplitVisits([]Fixation{fixation("productJoin", "")}); len(visits) != 0 {
+        t.Fatalf("섹션 밖 고정만 있는데 방문이 생김: %+v", visits)
+    }
+}
+
+func TestReadingConfigValidate(t *testing.T) {
+    cases := []struct {
+        name   string
+        modify func(c *ReadingConfig)
+        ok     bool
+    }{
+        {"기본값", func(c *ReadingConfig) {}, true},
+        {"줄 높이 0", func(c *ReadingConfig) { c.LineHeight = 0 }, false},
+        {"음수 줄 높이", func(c *ReadingConfig) { c.LineHeight = -28 }, false},
+        {"NaN 줄 높이", func(c *ReadingConfig) { c.LineHeight = math.NaN() }, false},
+        {"무한대 줄 높이", func(c *ReadingConfig) { c.LineHeight = math.Inf(1) }, false},
+        {"1보다 큰 커버리지", func(c *ReadingConfig) { c.MinCoverage = 1.5 }, false},
+        {"음수 진행 비율", func(c *ReadingConfig) { c.MinForwardRatio = -0.1 }, false},
+        {"음수 응시 시간", func(c *ReadingConfig) { c.StareMinMs = -1 }, false},
+    }
+
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            cfg := DefaultReadingConfig()
+            c.modify(&cfg)
+            if err := cfg.Validate(); (err == nil) != c.ok {
+                t.Fatalf("Validate() = %v, 통과 기대 %v", err, c.ok)
+            }
+        })
+    }
+}
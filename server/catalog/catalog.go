@@ -0,0 +1,214 @@
+package catalog
+
+import (
+    _ "embed"
+    "encoding/json"
+    "fmt"
+    "os"
+)
+
+// 클라이언트 constant/content.ts 의 섹션 정의와 동일하게 유지해야 한다
+//
+//go:embed terms.json
+var defaultTerms []byte
+
+type Priority string
+
+const (
+    PriorityHigh   Priority = "high"
+    PriorityMedium Priority = "medium"
+    PriorityLow    Priority = "low"
+)
+
+type Section struct {
+    ID       string   `json:"id"`
+    Name     string   `json:"name"`
+    Required int      `json:"required"` // 필요한 시청 시간 (초)
+    Priority Priority `json:"priority"`
+    Items    []string `json:"items"` // 섹션 내 항목 라벨
+    Lines    int      `json:"lines"` // 키오스크 화면에서 섹션 본문의 높이 (READING_LINE_HEIGHT 줄 수). 읽기 분류의 세로 커버리지 분모
+    PageID   string   `json:"-"`
+}
+
+type Page struct {
+    ID       string    `json:"id"`
+    Title    string    `json:"title"`
+    Sections []Section `json:"sections"`
+}
+
+type Product struct {
+    ID    string   `json:"id"`
+    Name  string   `json:"name"`
+    Pages []string `json:"pages"`
+}
+
+// 약관 카탈로그. version은 세션과 증적에 함께 기록된다
+type Catalog struct {
+    Version  string    `json:"version"`
+    Products []Product `json:"products"`
+    Pages    []Page    `json:"pages"`
+
+    pages    map[string]*Page
+    sections map[string]*Section // 페이지ID/섹션ID -> 섹션
+    products map[string]*Product
+}
+
+// path가 비어 있으면 바이너리에 포함된 기본 카탈로그를 사용
+func Load(path string) (*Catalog, error) {
+    if path == "" {
+        return Parse(defaultTerms)
+    }
+
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return nil, fmt.Errorf("카탈로그 파일 읽기 실패: %w", err)
+    }
+    return Parse(data)
+}
+
+func Parse(data []byte) (*Catalog, error) {
+    var c Catalog
+    if err := json.Unmarshal(data, &c); err != nil {
+        return nil, fmt.Errorf("카탈로그 파싱 실패: %w", err)
+    }
+    if err := c.index(); err != nil {
+        return nil, err
+    }
+    return &c, nil
+}
+
+func (c *Catalog) index() error {
+    if c.Version == "" {
+        return fmt.Errorf("카탈로그 버전 누락")
+    }
+
+    c.pages = make(map[string]*Page)
+    c.sections = make(map[string]*Section)
+    c.products = make(map[string]*Product)
+
+    for i := range c.Pages {
+        page := &c.Pages[i]
+        if _, dup := c.pages[page.ID]; dup {
+            return fmt.Errorf("중복된 페이지: %s", page.ID)
+        }
+        c.pages[page.ID] = page
+
+        for j := range page.Sections {
+            section := &page.Sections[j]
+            section.PageID = page.ID
+
+            switch section.Priority {
+            case PriorityHigh, PriorityMedium, PriorityLow:
+            default:
+                return fmt.Errorf("잘못된 우선순위 %q: %s/%s", section.Priority, page.ID, section.ID)
+            }
+            if section.Required < 0 {
+                return fmt.Errorf("음수 필요 시간: %s/%s", page.ID, section.ID)
+            }
+            if section.Lines < 0 {
+                return fmt.Errorf("음수 줄 수: %s/%s", page.ID, section.ID)
+            }
+
+            key := sectionKey(page.ID, section.ID)
+            if _, dup := c.sections[key]; dup {
+                return fmt.Errorf("중복된 섹션: %s", key)
+            }
+            c.sections[key] = section
+        }
+    }
+
+    for i := range c.Products {
+        product := &c.Products[i]
+        if _, dup := c.products[product.ID]; dup {
+            return fmt.Errorf("중복된 상품: %s", product.ID)
+        }
+        for _, pageID := range product.Pages {
+            if _, ok := c.pages[pageID]; !ok {
+                return fmt.Errorf("상품 %s 에 정의되지 않은 페이지: %s", product.ID, pageID)
+            }
+        }
+        c.products[product.ID] = product
+    }
+
+    return nil
+}
+
+func (c *Catalog) Page(pageID string) (*Page, bool) {
+    page, ok := c.pages[pageID]
+    return page, ok
+}
+
+func (c *Catalog) Section(pageID, sectionID string) (*Section, bool) {
+    section, ok := c.sections[sectionKey(pageID, sectionID)]
+    return section, ok
+}
+
+func (c *Catalog) Product(productID string) (*Product, bool) {
+    product, ok := c.products[productID]
+    return product, ok
+}
+
+// 상품에 속한 모든 섹션 (페이지 순서대로)
+func (c *Catalog) ProductSections(productID string) []Section {
+    product, ok := c.products[productID]
+    if !ok {
+        return nil
+    }
+
+    var sections []Section
+    for _, pageID := range product.Pages {
+        sections = append(sections, c.pages[pageID].Sections...)
+    }
+    return sections
+}
+
+func (c *Catalog) ValidatePage(pageID string) error {
+    if _, ok := c.pages[pageID]; !ok {
+        return fmt.Errorf("알 수 없는 페이지: %s", pageID)
+    }
+    return nil
+}
+
+// 페이지가 상품의 약관에 속하는지 확인. productID가 비어 있으면 카탈로그 전체에서 찾는다
+func (c *Catalog) ValidateProductPage(productID, pageID string) error {
+    if err := c.ValidatePage(pageID); err != nil {
+        return err
+    }
+    if productID == "" {
+        return nil
+    }
+    product, ok := c.products[productID]
+    if !ok {
+        return fmt.Errorf("알 수 없는 상품: %s", productID)
+    }
+    for _, id := range product.Pages {
+        if id == pageID {
+            return nil
+        }
+    }
+    return fmt.Errorf("상품 %s 에 없는 페이지: %s", productID, pageID)
+}
+
+// 시선 데이터의 페이지/섹션이 상품의 약관에 존재하는지 확인. 섹션 밖을 본 경우(sectionId 없음)는 허용
+func (c *Catalog) ValidateGaze(productID string, currentPage, sectionID *string) error {
+    if currentPage == nil || *currentPage == "" {
+        if sectionID != nil && *sectionID != "" {
+            return fmt.Errorf("페이지 없이 섹션만 지정됨: %s", *sectionID)
+        }
+        return nil
+    }
+    if err := c.ValidateProductPage(productID, *currentPage); err != nil {
+        return err
+    }
+    if sectionID == nil || *sectionID == "" {
+        return nil
+    }
+    if _, ok := c.Section(*currentPage, *sectionID); !ok {
+        return fmt.Errorf("페이지 %s 에 없는 섹션: %s", *currentPage, *sectionID)
+    }
+    return nil
+}
+
+func sectionKey(pageID, sectionID string) string {
+    return pageID + "/" + sectionID
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+    "context"
+    "log"
+
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/dlq"
+    "shinhan-eyetracking/server/models"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// 저장 대기 중인 메시지와 해석한 시선 데이터
+type pendingGaze struct {
+    message kafka.Message
+    data    models.GazeData
+}
+
+// 메시지를 해석해 저장할 것과 DLQ로 보낼 것을 나눈다
+func decodeBatch(messages []kafka.Message) (valid []pendingGaze, dead []dlq.Entry) {
+    for _, m := range messages {
+        // 메인 서버와 같은 디코더 사용 (timestamp 문자열 처리 등)
+        data, err := models.DecodeGazeData(m.Value)
+        if err != nil {
+            log.Printf("⚠️ 해석할 수 없는 메시지 DLQ로 이동: offset=%d: %v", m.Offset, err)
+            dead = append(dead, dlq.NewEntry(m, dlq.ReasonMalformed, err))
+            continue
+        }
+
+        // 세션 없는 메시지는 체인에 넣을 수 없으므로 저장하지 않음
+        if data.SessionID == "" {
+            log.Printf("⚠️ 세션 ID 없는 메시지 DLQ로 이동: offset=%d", m.Offset)
+            dead = append(dead, dlq.NewEntry(m, dlq.ReasonMissingSession, nil))
+            continue
+        }
+        valid = append(valid, pendingGaze{message: m, data: data})
+    }
+    return valid, dead
+}
+
+func samplesOf(batch []pendingGaze) []models.GazeData {
+    samples := make([]models.GazeData, len(batch))
+    for i, p := range batch {
+        samples[i] = p.data
+    }
+    return samples
+}
+
+// 배치가 DB가 살아 있는데도 계속 실패하면 한 건씩 저장해 문제 메시지를 골라낸다.
+// 중간에 DB 연결이 끊기거나 ctx가 끝나면 아직 처리하지 않은 나머지를 돌려준다
+func isolatePoison(ctx context.Context, db database.Store, batch []pendingGaze) (remaining []pendingGaze, dead []dlq.Entry) {
+    for i, p := range batch {
+        if ctx.Err() != nil {
+            return batch[i:], dead
+        }
+        err := db.SaveGazeBatch(samplesOf(batch[i : i+1]))
+        if err == nil {
+            continue
+        }
+        if pingErr := db.Ping(); pingErr != nil {
+            return batch[i:], dead
+        }
+        log.Printf("⚠️ 저장할 수 없는 메시지 DLQ로 이동: offset=%d: %v", p.message.Offset, err)
+        dead = append(dead, dlq.NewEntry(p.message, dlq.ReasonStoreFailed, err))
+    }
+    return nil, dead
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/models"
+)
+
+// 종료 신호를 받으면 재시도 대기와 한 건씩 저장을 바로 멈춘다
+func TestRetryStopsWhenContextEnds(t *testing.T) {
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    start := time.Now()
+    if waitRetry(ctx, maxRetryDelay) {
+        t.Fatalf("끝난 ctx에서 재시도 대기가 끝까지 기다림")
+    }
+    if elapsed := time.Since(start); elapsed > time.Second {
+        t.Fatalf("재시도 대기가 %s 걸림", elapsed)
+    }
+
+    batch := []pendingGaze{
+        {data: models.GazeData{SessionID: "s1", Timestamp: 1}},
+        {data: models.GazeData{SessionID: "s1", Timestamp: 2}},
+    }
+    remaining, dead := isolatePoison(ctx, database.NewMemoryStore(), batch)
+    if len(remaining) != len(batch) || len(dead) != 0 {
+        t.Fatalf("끝난 ctx에서 저장을 계속함: 남은 %d건, DLQ %d건", len(remaining), len(dead))
+    }
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+    "encoding/json"
+    "reflect"
+    "testing"
+
+    "shinhan-eyetracking/server/dlq"
+    "shinhan-eyetracking/server/models"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// 메인 서버는 models.DecodeGazeData로, Consumer는 decodeBatch로 해석한다
+func decodeLikeConsumer(payload []byte) ([]pendingGaze, []dlq.Entry) {
+    valid, dead := decodeBatch([]kafka.Message{{Key: []byte("gaze"), Value: payload}})
+    return valid, dead
+}
+
+func TestServerAndConsumerDecodeGazeDataIdentically(t *testing.T) {
+    valid := map[string]string{
+        "숫자 timestamp":    `{"x":120.5,"y":300,"timestamp":1726000000000,"sectionId":"fee","currentPage":"productDetail","sessionId":"s1"}`,
+        "문자열 timestamp":   `{"x":1,"y":2,"timestamp":"1726000000123","sessionId":"s1"}`,
+        "지수 표기 timestamp": `{"x":1,"y":2,"timestamp":1.726e12,"sessionId":"s1"}`,
+        "섹션 없음":           `{"x":10,"y":20,"timestamp":1726000000000,"sectionId":null,"currentPage":"productJoin","sessionId":"s1"}`,
+        "뷰포트 포함":          `{"x":10,"y":20,"timestamp":1726000000000,"viewportWidth":1920,"viewportHeight":1080,"sessionId":"s1"}`,
+    }
+
+    for name, payload := range valid {
+        t.Run(name, func(t *testing.T) {
+            server, err := models.DecodeGazeData([]byte(payload))
+            if err != nil {
+                t.Fatalf("서버 디코딩 실패: %v", err)
+            }
+
+            // 같은 원본 payload를 Consumer가 받았을 때
+            got, dead := decodeLikeConsumer([]byte(payload))
+            if len(dead) != 0 || len(got) != 1 {
+                t.Fatalf("Consumer가 정상 payload를 거부: dead=%v", dead)
+            }
+            if !reflect.DeepEqual(got[0].data, server) {
+                t.Fatalf("디코딩 결과 불일치\n서버:     %+v\nConsumer: %+v", server, got[0].data)
+            }
+
+            // 서버가 Kafka로 보내는 직렬화 결과를 Consumer가 받았을 때
+            value, err := json.Marshal(server)
+            if err != nil {
+                t.Fatalf("Kafka 메시지 마샬링 실패: %v", err)
+            }
+            got, dead = decodeLikeConsumer(value)
+            if len(dead) != 0 || len(got) != 1 {
+                t.Fatalf("Consumer가 서버 메시지를 거부: dead=%v", dead)
+            }
+            if !reflect.DeepEqual(got[0].data, server) {
+                t.Fatalf("Kafka 왕복 후 불일치\n서버:     %+v\nConsumer: %+v", server, got[0].data)
+            }
+        })
+    }
+}
+
+func TestServerAndConsumerRejectTheSameGazeData(t *testing.T) {
+    invalid := map[string]string{
+        "해석 불가 timestamp":  `{"x":1,"y":2,"timestamp":"abc","sessionId":"s1"}`,
+        "timestamp 누락":     `{"x":1,"y":2,"sessionId":"s1"}`,
+        "소수 timestamp":     `{"x":1,"y":2,"timestamp":1726000000000.5,"sessionId":"s1"}`,
+        "음수 timestamp":     `{"x":1,"y":2,"timestamp":-1,"sessionId":"s1"}`,
+        "음수 문자열 timestamp": `{"x":1,"y":2,"timestamp":"-1","sessionId":"s1"}`,
+        "2^63 timestamp":   `{"x":1,"y":2,"timestamp":9223372036854775808,"sessionId":"s1"}`,
+        "잘못된 좌표 타입":        `{"x":"left","y":2,"timestamp":1726000000000,"sessionId":"s1"}`,
+    }
+
+    for name, payload := range invalid {
+        t.Run(name, func(t *testing.T) {
+            if _, err := models.DecodeGazeData([]byte(payload)); err == nil {
+                t.Fatalf("서버가 잘못된 payload를 받아들임")
+            }
+
+            got, dead := decodeLikeConsumer([]byte(payload))
+            if len(got) != 0 || len(dead) != 1 {
+                t.Fatalf("Consumer가 잘못된 payload를 받아들임: %+v", got)
+            }
+            if dead[0].Reason != dlq.ReasonMalformed {
+                t.Fatalf("DLQ 사유 = %s, 기대값 %s", dead[0].Reason, dlq.ReasonMalformed)
+            }
+        })
+    }
+}
@@ -2,78 +2,196 @@ package main
 
 import (
     "context"
-    "database/sql"
-    "encoding/json"
+    "errors"
     "log"
     "os"
+    "os/signal"
+    "syscall"
+    "time"
+
+    "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/dlq"
+    "shinhan-eyetracking/server/topics"
 
     "github.com/segmentio/kafka-go"
-    _ "github.com/lib/pq"
 )
 
-// 메인 서버와 동일한 구조로 수정
-type GazeData struct {
-    X           float64 `json:"x"`
-    Y           float64 `json:"y"`
-    Timestamp   int64   `json:"timestamp"`  
-    SectionID   *string `json:"sectionId,omitempty"`
-    CurrentPage *string `json:"currentPage,omitempty"`
-}
+const (
+    // DB 저장 실패 시 같은 배치를 다시 시도하는 간격 (지수 증가)
+    minRetryDelay = 500 * time.Millisecond
+    maxRetryDelay = 30 * time.Second
+    // 이 횟수만큼 실패하면 DB 상태를 확인하고 한 건씩 저장해 문제 메시지를 찾는다
+    isolateAfterAttempts = 3
+)
 
 func main() {
-    // DB 연결
-    dbHost := os.Getenv("DB_HOST")
-    if dbHost == "" {
-        dbHost = "localhost"
-    }
+    // 메인 서버와 같은 설정과 DB 패키지 사용
+    cfg := config.LoadConfig()
 
-    connectionString := "host=" + dbHost + " port=5432 user=admin password=1q2w3e4r dbname=eyetracking sslmode=disable"
-    db, err := sql.Open("postgres", connectionString)
+    db, err := database.NewStore(cfg)
     if err != nil {
         log.Fatal("❌ DB 연결 실패:", err)
     }
     defer db.Close()
-    log.Println("✅ Consumer PostgreSQL 연결 완료")
+    if cfg.StoreBackend == database.BackendMemory {
+        log.Println("⚠️ 메모리 저장소는 메인 서버와 공유되지 않음 (Consumer가 저장한 데이터는 이 프로세스 안에만 남음)")
+    }
+    log.Printf("✅ Consumer 저장소 연결 완료 (%s)", cfg.StoreBackend)
 
-    // Kafka Consumer 설정
-    kafkaBrokers := os.Getenv("KAFKA_BROKERS")
-    if kafkaBrokers == "" {
-        kafkaBrokers = "localhost:9092"
+    // 배치는 건수 또는 첫 메시지 이후 경과 시간 중 먼저 닿는 쪽에서 끊는다
+    batchSize := int(cfg.ConsumerBatchSize)
+    batchTimeout := time.Duration(cfg.ConsumerBatchTimeoutMs) * time.Millisecond
+
+    // 메인 서버보다 먼저 떠도 같은 설정으로 토픽을 만들고, 파티션이 설정보다 적거나 복제 계수가 다르면 시작하지 않는다
+    if err := topics.Ensure(context.Background(), cfg.KafkaBrokers, cfg.KafkaCreateTopics, topics.FromConfig(cfg)...); err != nil {
+        log.Fatal("❌ Kafka 토픽 확인 실패:", err)
     }
 
     r := kafka.NewReader(kafka.ReaderConfig{
-        Brokers:     []string{kafkaBrokers},
-        Topic:       "gaze-data",
+        Brokers:     []string{cfg.KafkaBrokers},
+        Topic:       cfg.GazeTopic,
         GroupID:     "gaze-consumer-group",
         StartOffset: kafka.FirstOffset, // 처음부터 읽기
+        // 오프셋은 DB 트랜잭션이 성공한 뒤에만 직접 커밋
+        CommitInterval: 0,
     })
     defer r.Close()
 
-    log.Println("📥 Kafka Consumer 시작 - DB 저장 담당")
+    deadLetters := dlq.NewWriter(cfg.KafkaBrokers, cfg.DLQTopic)
+    defer deadLetters.Close()
+
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+
+    log.Printf("📥 Kafka Consumer 시작 - DB 저장 담당 (배치 %d건 / %s)", batchSize, batchTimeout)
 
     for {
-        m, err := r.ReadMessage(context.Background())
+        messages, err := fetchBatch(ctx, r, batchSize, batchTimeout)
+        if len(messages) > 0 {
+            // 종료 신호를 받았어도 이미 가져온 배치는 한 번 저장을 시도한다. 재시도 대기 중에 받으면 커밋하지 않고 끝낸다
+            if !storeAndCommit(ctx, db, deadLetters, r, messages) {
+                return
+            }
+        }
         if err != nil {
+            if ctx.Err() != nil {
+                log.Println("🛑 Consumer 종료")
+                return
+            }
             log.Printf("❌ Kafka 메시지 수신 실패: %v", err)
-            continue
+            if !waitRetry(ctx, time.Second) {
+                log.Println("🛑 Consumer 종료")
+                return
+            }
         }
+    }
+}
 
-        var data GazeData
-        if err := json.Unmarshal(m.Value, &data); err != nil {
-            continue
-        }
+// 최대 size건, 첫 메시지 이후 최대 timeout 동안 메시지를 모은다
+func fetchBatch(ctx context.Context, r *kafka.Reader, size int, timeout time.Duration) ([]kafka.Message, error) {
+    // 첫 메시지는 기한 없이 기다린다
+    first, err := r.FetchMessage(ctx)
+    if err != nil {
+        return nil, err
+    }
+    messages := []kafka.Message{first}
+
+    batchCtx, cancel := context.WithTimeout(ctx, timeout)
+    defer cancel()
 
-        // 확장된 데이터 저장
-        _, err = db.Exec(`
-            INSERT INTO gaze_data (x, y, timestamp, section_id, current_page) 
-            VALUES ($1, $2, $3, $4, $5)`,
-            data.X, data.Y, data.Timestamp, data.SectionID, data.CurrentPage)
-        
+    for len(messages) < size {
+        m, err := r.FetchMessage(batchCtx)
         if err != nil {
-            log.Printf("💾 DB 저장 실패: %v", err)
-        } else {
-            log.Printf("💾 저장 완료: x=%.2f y=%.2f page=%v section=%v", 
-                data.X, data.Y, data.CurrentPage, data.SectionID)
+            // 배치 시간 초과는 정상적인 배치 마감
+            if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+                return messages, nil
+            }
+            return messages, err
         }
+        messages = append(messages, m)
     }
-}
\ No newline at end of file
+    return messages, nil
+}
+
+// 배치가 저장되고 처리할 수 없는 메시지가 DLQ에 기록될 때까지 재시도한 뒤 오프셋을 커밋.
+// 저장 전에는 절대 커밋하지 않는다. 저장 후 커밋 전에 죽으면 재시작 시 같은 메시지가 다시 저장될 수 있다 (at-least-once).
+// 재시도 중 ctx가 끝나면 커밋하지 않고 false를 반환한다
+func storeAndCommit(ctx context.Context, db database.Store, deadLetters *dlq.Writer, r *kafka.Reader, messages []kafka.Message) bool {
+    valid, dead := decodeBatch(messages)
+    total := len(valid)
+
+    delay := minRetryDelay
+    for attempt := 1; len(valid) > 0; attempt++ {
+        err := db.SaveGazeBatch(samplesOf(valid))
+        if err == nil {
+            break
+        }
+        log.Printf("💾 배치 저장 실패 (%d회): %v", attempt, err)
+
+        // DB는 살아 있는데 배치가 계속 실패하면 특정 메시지 문제로 보고 골라낸다
+        if attempt >= isolateAfterAttempts && db.Ping() == nil {
+            var poisoned []dlq.Entry
+            valid, poisoned = isolatePoison(ctx, db, valid)
+            dead = append(dead, poisoned...)
+            total -= len(poisoned)
+            if len(valid) == 0 || ctx.Err() == nil {
+                continue
+            }
+        }
+
+        log.Printf("💾 %s 후 재시도", delay)
+        if !waitRetry(ctx, delay) {
+            log.Printf("🛑 배치 저장 재시도 중 종료 (커밋하지 않음, offset %d~%d)",
+                messages[0].Offset, messages[len(messages)-1].Offset)
+            return false
+        }
+        delay = nextRetryDelay(delay)
+    }
+
+    delay = minRetryDelay
+    for len(dead) > 0 {
+        err := deadLetters.Write(context.Background(), dead...)
+        if err == nil {
+            log.Printf("☠️ DLQ 기록 완료: %d건", len(dead))
+            break
+        }
+        log.Printf("❌ %v, %s 후 재시도", err, delay)
+        if !waitRetry(ctx, delay) {
+            log.Printf("🛑 DLQ 기록 재시도 중 종료 (커밋하지 않음, offset %d~%d)",
+                messages[0].Offset, messages[len(messages)-1].Offset)
+            return false
+        }
+        delay = nextRetryDelay(delay)
+    }
+
+    log.Printf("💾 배치 처리 완료: %d건 저장, %d건 DLQ (offset %d~%d)",
+        total, len(dead), messages[0].Offset, messages[len(messages)-1].Offset)
+
+    if err := r.CommitMessages(context.Background(), messages...); err != nil {
+        log.Printf("❌ 오프셋 커밋 실패: %v", err)
+        return false
+    }
+    return true
+}
+
+// delay만큼 기다린다. 그 전에 ctx가 끝나면 false
+func waitRetry(ctx context.Context, delay time.Duration) bool {
+    timer := time.NewTimer(delay)
+    defer timer.Stop()
+
+    select {
+    case <-timer.C:
+        return true
+    case <-ctx.Done():
+        return false
+    }
+}
+
+func nextRetryDelay(delay time.Duration) time.Duration {
+    delay *= 2
+    if delay > maxRetryDelay {
+        return maxRetryDelay
+    }
+    return delay
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "flag"
+    "fmt"
+    "log"
+    "os"
+    "time"
+
+    "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/dlq"
+
+    "github.com/segmentio/kafka-go"
+)
+
+const usage = `DLQ 점검 및 재처리 도구
+
+사용법:
+  dlq inspect [-limit N]     DLQ 메시지를 처음부터 출력 (오프셋을 커밋하지 않음)
+  dlq replay  [-max N]       아직 재처리하지 않은 DLQ 메시지를 원본 토픽으로 다시 보냄
+
+환경 변수:
+  KAFKA_BROKERS  (기본 localhost:9092)
+  DLQ_TOPIC      (기본 gaze-data-dlq)
+  GAZE_TOPIC     (기본 gaze-data)
+`
+
+// 재처리 진행 위치를 기억하는 Consumer 그룹. 한 번 재처리한 메시지는 다시 보내지 않는다
+const replayGroupID = "gaze-dlq-replay"
+
+func main() {
+    if len(os.Args) < 2 {
+        fmt.Fprint(os.Stderr, usage)
+        os.Exit(2)
+    }
+
+    cfg := config.LoadConfig()
+    brokers := cfg.KafkaBrokers
+    dlqTopic := cfg.DLQTopic
+    mainTopic := cfg.GazeTopic
+
+    var err error
+    switch os.Args[1] {
+    case "inspect":
+        fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+        limit := fs.Int("limit", 100, "출력할 최대 건수 (0이면 전부)")
+        fs.Parse(os.Args[2:])
+        err = inspect(brokers, dlqTopic, *limit)
+    case "replay":
+        fs := flag.NewFlagSet("replay", flag.ExitOnError)
+        maxCount := fs.Int("max", 0, "재처리할 최대 건수 (0이면 전부)")
+        idle := fs.Duration("idle", 5*time.Second, "새 메시지가 이 시간 동안 없으면 종료")
+        fs.Parse(os.Args[2:])
+        err = replay(brokers, dlqTopic, mainTopic, *maxCount, *idle)
+    default:
+        fmt.Fprint(os.Stderr, usage)
+        os.Exit(2)
+    }
+
+    if err != nil {
+        log.Fatalf("❌ %v", err)
+    }
+}
+
+// 파티션별로 처음부터 마지막 오프셋까지 읽어 출력
+func inspect(brokers, topic string, limit int) error {
+    conn, err := kafka.Dial("tcp", brokers)
+    if err != nil {
+        return fmt.Errorf("Kafka 연결 실패: %w", err)
+    }
+    partitions, err := conn.ReadPartitions(topic)
+    conn.Close()
+    if err != nil {
+        return fmt.Errorf("파티션 조회 실패: %w", err)
+    }
+
+    printed := 0
+    counts := make(map[dlq.Reason]int)
+    for _, p := range partitions {
+        n, err := inspectPartition(brokers, topic, p.ID, limit-printed, limit == 0, counts)
+        printed += n
+        if err != nil {
+            return err
+        }
+        if limit > 0 && printed >= limit {
+            break
+        }
+    }
+
+    fmt.Printf("\n총 %d건 출력, 사유별: %v\n", printed, counts)
+    return nil
+}
+
+func inspectPartition(brokers, topic string, partition, remaining int, unlimited bool, counts map[dlq.Reason]int) (int, error) {
+    leader, err := kafka.DialLeader(context.Background(), "tcp", brokers, topic, partition)
+    if err != nil {
+        return 0, fmt.Errorf("파티션 %d 연결 실패: %w", partition, err)
+    }
+    first, last, err := leader.ReadOffsets()
+    leader.Close()
+    if err != nil {
+        return 0, fmt.Errorf("파티션 %d 오프셋 조회 실패: %w", partition, err)
+    }
+    if first >= last {
+        return 0, nil
+    }
+
+    r := kafka.NewReader(kafka.ReaderConfig{
+        Brokers:   []string{brokers},
+        Topic:     topic,
+        Partition: partition,
+    })
+    defer r.Close()
+    if err := r.SetOffset(first); err != nil {
+        return 0, err
+    }
+
+    printed := 0
+    for unlimited || printed < remaining {
+        m, err := r.ReadMessage(context.Background())
+        if err != nil {
+            return printed, fmt.Errorf("파티션 %d 읽기 실패: %w", partition, err)
+        }
+
+        entry, err := dlq.Decode(m)
+        if err != nil {
+            fmt.Printf("[%d/%d] %v\n", partition, m.Offset, err)
+        } else {
+            counts[entry.Reason]++
+            fmt.Printf("[%d/%d] %s %s  원본 %s[%d]@%d  %s\n    error:   %s\n    payload: %s\n",
+                partition, m.Offset, entry.FailedAt.Format(time.RFC3339), entry.Reason,
+                entry.Topic, entry.Partition, entry.Offset, entry.MessageTime.Format(time.RFC3339),
+                entry.Error, entry.Payload)
+        }
+        printed++
+        if m.Offset+1 >= last {
+            break
+        }
+    }
+    return printed, nil
+}
+
+// DLQ 메시지의 원본 payload를 원본 토픽으로 다시 보낸다. 원본 토픽에 쓴 뒤에만 DLQ 오프셋을 커밋
+func replay(brokers, dlqTopic, mainTopic string, maxCount int, idle time.Duration) error {
+    r := kafka.NewReader(kafka.ReaderConfig{
+        Brokers:     []string{brokers},
+        Topic:       dlqTopic,
+        GroupID:     replayGroupID,
+        StartOffset: kafka.FirstOffset,
+    })
+    defer r.Close()
+
+    w := newReplayWriter(brokers, mainTopic)
+    defer w.Close()
+
+    replayed := 0
+    for maxCount == 0 || replayed < maxCount {
+        ctx, cancel := context.WithTimeout(context.Background(), idle)
+        m, err := r.FetchMessage(ctx)
+        cancel()
+        if errors.Is(err, context.DeadlineExceeded) {
+            break
+        }
+        if err != nil {
+            return fmt.Errorf("DLQ 읽기 실패: %w", err)
+        }
+
+        sent, err := replayMessage(context.Background(), w, m)
+        if err != nil {
+            return err
+        }
+        if sent {
+            replayed++
+        }
+
+        if err := r.CommitMessages(context.Background(), m); err != nil {
+            return fmt.Errorf("DLQ 오프셋 커밋 실패: %w", err)
+        }
+    }
+
+    log.Printf("✅ 재처리 완료: %d건 -> %s", replayed, mainTopic)
+    return nil
+}
+
+// 원본과 같은 세션 키 해시로 보내야 재처리한 샘플이 원래 세션 파티션으로 간다
+func newReplayWriter(brokers, topic string) *kafka.Writer {
+    return &kafka.Writer{
+        Addr:         kafka.TCP(brokers),
+        Topic:        topic,
+        Balancer:     &kafka.Hash{},
+        RequiredAcks: kafka.RequireAll,
+    }
+}
+
+type messageWriter interface {
+    WriteMessages(ctx context.Context, messages ...kafka.Message) error
+}
+
+// DLQ 메시지 하나의 원본을 다시 보낸다. 해석할 수 없는 DLQ 메시지는 재처리 대상이 아니므로 건너뛰고 false
+func replayMessage(ctx context.Context, w messageWriter, m kafka.Message) (bool, error) {
+    entry, err := dlq.Decode(m)
+    if err != nil {
+        log.Printf("⚠️ %v", err)
+        return false, nil
+    }
+    if err := w.WriteMessages(ctx, entry.Original()); err != nil {
+        return false, fmt.Errorf("원본 토픽 전송 실패 (DLQ offset %d): %w", m.Offset, err)
+    }
+    log.Printf("🔁 재처리: DLQ offset %d (%s, 원본 %s[%d]@%d)",
+        m.Offset, entry.Reason, entry.Topic, entry.Partition, entry.Offset)
+    return true, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "testing"
+
+    "shinhan-eyetracking/server/dlq"
+
+    "github.com/segmentio/kafka-go"
+)
+
+type recordingWriter struct {
+    messages []kafka.Message
+    err      error
+}
+
+func (w *recordingWriter) WriteMessages(ctx context.Context, messages ...kafka.Message) error {
+    if w.err != nil {
+        return w.err
+    }
+    w.messages = append(w.messages, messages...)
+    return nil
+}
+
+// DLQ에 들어간 메시지를 재처리하면 원본 키·payload 그대로 원래 세션 파티션으로 다시 보낸다
+func TestReplaySendsOriginalToSessionPartition(t *testing.T) {
+    source := kafka.Message{Topic: "gaze-data", Partition: 2, Offset: 310, Key: []byte("session-1"),
+        Value: []byte(`[{"sessionId":"session-1","x":10,"y":20,"timestamp":1726000000000}]`)}
+    deadLetter, err := dlq.NewEntry(source, dlq.ReasonStoreFailed, errors.New("duplicate key")).Message()
+    if err != nil {
+        t.Fatalf("DLQ 메시지 만들기 실패: %v", err)
+    }
+
+    w := &recordingWriter{}
+    sent, err := replayMessage(context.Background(), w, deadLetter)
+    if err != nil || !sent {
+        t.Fatalf("재처리 실패: sent=%v, %v", sent, err)
+    }
+    if len(w.messages) != 1 {
+        t.Fatalf("보낸 메시지 %d건, 기대값 1건", len(w.messages))
+    }
+    replayed := w.messages[0]
+    if !bytes.Equal(replayed.Key, source.Key) || !bytes.Equal(replayed.Value, source.Value) {
+        t.Fatalf("재처리 메시지 = %q / %q, 기대값 %q / %q", replayed.Key, replayed.Value, source.Key, source.Value)
+    }
+
+    // 서버 Producer와 같은 키 해시를 쓰므로 파티션 수가 같으면 원래 세션 파티션으로 간다
+    partitions := []int{0, 1, 2, 3, 4, 5}
+    producer := &kafka.Hash{}
+    if got, want := newReplayWriter("localhost:9092", "gaze-data").Balancer.Balance(replayed, partitions...),
+        producer.Balance(source, partitions...); got != want {
+        t.Fatalf("재처리 파티션 = %d, 기대값 원래 파티션 %d", got, want)
+    }
+}
+
+// 해석할 수 없는 DLQ 메시지는 건너뛰고, 원본 토픽 전송 실패는 오류로 돌려 DLQ 오프셋을 커밋하지 않게 한다
+func TestReplaySkipsUndecodableAndReportsWriteFailure(t *testing.T) {
+    w := &recordingWriter{}
+    sent, err := replayMessage(context.Background(), w, kafka.Message{Offset: 4, Value: []byte("not json")})
+    if err != nil || sent || len(w.messages) != 0 {
+        t.Fatalf("해석할 수 없는 DLQ 메시지 처리 = sent %v, %v, 보낸 메시지 %d건 (건너뛰어야 함)", sent, err, len(w.messages))
+    }
+
+    deadLetter, err := dlq.NewEntry(kafka.Message{Key: []byte("session-1"), Value: []byte("{}")}, dlq.ReasonStoreFailed, nil).Message()
+    if err != nil {
+        t.Fatalf("DLQ 메시지 만들기 실패: %v", err)
+    }
+    w.err = errors.New("브로커에 연결할 수 없음")
+    if sent, err := replayMessage(context.Background(), w, deadLetter); err == nil || sent {
+        t.Fatalf("원본 토픽 전송 실패가 성공으로 처리됨: sent %v", sent)
+    }
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+    "flag"
+    "fmt"
+    "log"
+    "os"
+
+    "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/database"
+)
+
+const usage = `DB 스키마 마이그레이션 도구
+
+사용법:
+  migrate up     [-to N]      N번까지 적용 (생략하면 최신까지)
+  migrate down   [-steps N]   최근 적용한 마이그레이션부터 N개 되돌림 (기본 1)
+  migrate status              마이그레이션별 적용 여부 출력
+
+환경 변수:
+  DB_HOST, DB_USER, DB_PASSWORD, DB_NAME
+`
+
+func main() {
+    if len(os.Args) < 2 {
+        fmt.Fprint(os.Stderr, usage)
+        os.Exit(2)
+    }
+
+    // 서버와 Consumer가 떠 있어도 advisory lock으로 순서대로 적용된다
+    db, err := database.Open(config.LoadConfig())
+    if err != nil {
+        log.Fatalf("❌ %v", err)
+    }
+    defer db.Close()
+
+    switch os.Args[1] {
+    case "up":
+        fs := flag.NewFlagSet("up", flag.ExitOnError)
+        target := fs.Int("to", 0, "적용할 마지막 버전 (0이면 최신)")
+        fs.Parse(os.Args[2:])
+        var applied int
+        applied, err = db.MigrateUp(*target)
+        if err == nil {
+            log.Printf("✅ %d개 적용", applied)
+        }
+    case "down":
+        fs := flag.NewFlagSet("down", flag.ExitOnError)
+        steps := fs.Int("steps", 1, "되돌릴 마이그레이션 수")
+        fs.Parse(os.Args[2:])
+        var reverted int
+        reverted, err = db.MigrateDown(*steps)
+        if err == nil {
+            log.Printf("✅ %d개 되돌림", reverted)
+        }
+    case "status":
+        err = printStatus(db)
+    default:
+        fmt.Fprint(os.Stderr, usage)
+        os.Exit(2)
+    }
+
+    if err != nil {
+        log.Fatalf("❌ %v", err)
+    }
+}
+
+func printStatus(db *database.DB) error {
+    statuses, err := db.MigrationStatus()
+    if err != nil {
+        return err
+    }
+
+    pending := 0
+    for _, s := range statuses {
+        applied := "대기"
+        if s.AppliedAt != nil {
+            applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+        } else {
+            pending++
+        }
+        fmt.Printf("%04d  %-28s  %s\n", s.Version, s.Name, applied)
+    }
+    fmt.Printf("\n총 %d개, 미적용 %d개\n", len(statuses), pending)
+    return nil
+}
@@ -2,49 +2,158 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"shinhan-eyetracking/server/analysis"
+	"shinhan-eyetracking/server/catalog"
 	"shinhan-eyetracking/server/config"
 	"shinhan-eyetracking/server/database"
+	"shinhan-eyetracking/server/evaluation"
 	"shinhan-eyetracking/server/handlers"
+	"shinhan-eyetracking/server/models"
 	"shinhan-eyetracking/server/services"
+	"shinhan-eyetracking/server/validation"
+	"syscall"
+	"time"
 )
 
+// 종료 신호를 받은 뒤 처리 중인 HTTP 요청을 기다리는 시간
+const shutdownTimeout = 5 * time.Second
+
 func main() {
 	// 설정 로드
 	cfg := config.LoadConfig()
 
+	// 약관 카탈로그 로드
+	terms, err := catalog.Load(cfg.CatalogPath)
+	if err != nil {
+		log.Fatal("❌ 약관 카탈로그 로드 실패:", err)
+	}
+	log.Printf("📚 약관 카탈로그 로드 완료: 버전 %s", terms.Version)
+
+	// 시선 고정 검출 설정
+	analysisConfig := analysis.Config{
+		Algorithm:           analysis.Algorithm(cfg.FixationAlgorithm),
+		VelocityThreshold:   cfg.VelocityThreshold,
+		DispersionThreshold: cfg.DispersionThreshold,
+		MinFixationMs:       cfg.MinFixationMs,
+		MaxGapMs:            cfg.MaxSampleGapMs,
+	}
+	if err := analysisConfig.Validate(); err != nil {
+		log.Fatal("❌ 시선 고정 검출 설정 오류:", err)
+	}
+
 	// 데이터베이스 초기화
-	db, err := database.New(cfg)
+	db, err := database.NewStore(cfg)
 	if err != nil {
 		log.Fatal("❌ 데이터베이스 초기화 실패:", err)
 	}
-	defer db.Close()
 
 	// 서비스들 초기화
-	kafkaService := services.NewKafkaService(cfg.KafkaBrokers)
-	defer kafkaService.Close()
+	eventPublisher, err := services.NewEventPublisher(cfg, db)
+	if err != nil {
+		log.Fatal("❌ 이벤트 버스 초기화 실패:", err)
+	}
+	publisher, err := services.NewAsyncPublisher(eventPublisher, int(cfg.PublishQueueSamples), services.OverflowPolicy(cfg.PublishOverflowPolicy))
+	if err != nil {
+		log.Fatal("❌ 전송 대기열 설정 오류:", err)
+	}
 
 	websocketService := services.NewWebSocketService()
-	gazeService := services.NewGazeService(db, kafkaService, websocketService)
+	gazeService := services.NewGazeService(db, publisher, websocketService, analysisConfig)
+	sessionService := services.NewSessionService(db, terms)
+	authService := services.NewAuthService(cfg)
+	rules := evaluation.DefaultRules()
+	rules.RequireReading = cfg.RequireReading
+	rules.MaxCalibrationErrorPx = cfg.CalibrationMaxErrorPx
+	readingConfig := analysis.DefaultReadingConfig()
+	readingConfig.LineHeight = cfg.ReadingLineHeight
+	if err := readingConfig.Validate(); err != nil {
+		log.Fatal("❌ 읽기 분류 설정 오류:", err)
+	}
+	calibrationService := services.NewCalibrationService(db, cfg.CalibrationMaxErrorPx)
+	verdictService := services.NewVerdictService(db, terms, gazeService, calibrationService, rules, readingConfig)
+	reportService := services.NewReportService(db, verdictService)
+	replayService := services.NewReplayService(db, websocketService)
+	gazeValidator := validation.NewGazeValidator(terms, validation.Config{
+		MaxClockSkew: time.Duration(cfg.GazeMaxClockSkewMs) * time.Millisecond,
+		BoundsMargin: cfg.GazeBoundsMargin,
+	})
+	gazeValidator.RestoreFrom(db)
+	gazeService.OnSessionRelease(gazeValidator.Forget)
 
 	// 핸들러들 초기화
-	wsHandler := handlers.NewWebSocketHandler(gazeService, websocketService)
-	apiHandler := handlers.NewAPIHandler(db, gazeService, websocketService)
+	wsHandler := handlers.NewWebSocketHandler(terms, authService, gazeService, sessionService, verdictService, calibrationService, replayService, websocketService, gazeValidator)
+	apiHandler := handlers.NewAPIHandler(db, terms, gazeService, verdictService, calibrationService, reportService, websocketService, gazeValidator, cfg.ReportFont)
 
 	// 라우트 설정
+	// 고객 증적(시선 데이터, 세션 분석, 보고서)은 직원과 감독자만 조회할 수 있다
+	staff := []models.Role{models.RoleEmployee, models.RoleSupervisor}
 	http.HandleFunc("/ws", wsHandler.HandleWebSocket)
-	http.HandleFunc("/data", apiHandler.DataHandler)
-	http.HandleFunc("/clear", apiHandler.ClearDataHandler)
-	http.HandleFunc("/page-status", apiHandler.PageStatusHandler)
+	http.HandleFunc("/data", handlers.RequireRole(authService, apiHandler.DataHandler, staff...))
+	http.HandleFunc("/clear", handlers.RequireRole(authService, apiHandler.ClearDataHandler, models.RoleSupervisor))
+	http.HandleFunc("/page-status", handlers.RequireRole(authService, apiHandler.PageStatusHandler, staff...))
+	http.HandleFunc("/sessions", handlers.RequireRole(authService, apiHandler.SessionsHandler, staff...))
+	http.HandleFunc("/sessions/dwell", handlers.RequireRole(authService, apiHandler.SessionDwellHandler, staff...))
+	http.HandleFunc("/sessions/fixations", handlers.RequireRole(authService, apiHandler.SessionFixationsHandler, staff...))
+	http.HandleFunc("/sessions/reading", handlers.RequireRole(authService, apiHandler.SessionReadingHandler, staff...))
+	http.HandleFunc("/sessions/calibration", handlers.RequireRole(authService, apiHandler.SessionCalibrationHandler, staff...))
+	http.HandleFunc("/sessions/verdict", handlers.RequireRole(authService, apiHandler.SessionVerdictHandler, staff...))
+	http.HandleFunc("/sessions/verify", handlers.RequireRole(authService, apiHandler.SessionVerifyHandler, staff...))
+	http.HandleFunc("/sessions/report", handlers.RequireRole(authService, apiHandler.SessionReportHandler, staff...))
+	http.HandleFunc("/catalog", apiHandler.CatalogHandler)
+	http.HandleFunc("/heatmap.png", handlers.RequireRole(authService, apiHandler.HeatmapHandler, staff...))
 
 	certFile := "/etc/letsencrypt/live/www.shinhan-eyetracking.store/fullchain.pem"
 	keyFile := "/etc/letsencrypt/live/www.shinhan-eyetracking.store/privkey.pem"
 
-		// HTTPS 서버 실행 (443 포트)
-	log.Printf("✅ 서버 실행: https://www.shinhan-eyetracking.store:443")
-	log.Fatal(http.ListenAndServeTLS(":443", certFile, keyFile, nil))
+	// HTTPS 서버 실행 (443 포트)
+	server := &http.Server{Addr: ":443"}
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("✅ 서버 실행: https://www.shinhan-eyetracking.store:443")
+		serverErr <- server.ListenAndServeTLS(certFile, keyFile)
+
+		//로컬에서 실행 (Addr를 ":8080"으로)
+		// serverErr <- server.ListenAndServe()
+	}()
+
+	// 컨테이너 종료(SIGTERM)나 Ctrl+C를 받으면 새 요청을 막고, 남은 시선 샘플을 저장 파이프라인에 넘긴 뒤 순서대로 닫는다
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
-	//로컬에서 실행
-	// log.Fatal(http.ListenAndServe(":8080", nil))
+	failed := false
+	select {
+	case err := <-serverErr:
+		log.Printf("❌ 서버 실행 실패: %v", err)
+		failed = true
+	case sig := <-stop:
+		log.Printf("🛑 종료 신호 수신: %v", sig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("⚠️ HTTP 서버 종료 실패: %v", err)
+	}
+	// Shutdown은 WebSocket으로 넘어간 연결을 닫지 않는다
+	websocketService.CloseAll()
+	gazeService.Shutdown()
+
+	if err := publisher.Close(); err != nil {
+		log.Printf("❌ 저장 파이프라인 종료 실패: %v", err)
+		failed = true
+	}
+	if err := db.Close(); err != nil {
+		log.Printf("❌ 데이터베이스 종료 실패: %v", err)
+		failed = true
+	}
+	log.Printf("👋 서버 종료 완료")
+
+	if failed {
+		os.Exit(1)
+	}
 }
@@ -1,6 +1,9 @@
 package config
 
-import "os"
+import (
+    "os"
+    "strconv"
+)
 
 type Config struct {
     DBHost       string
@@ -9,6 +12,62 @@ type Config struct {
     DBUser       string
     DBPassword   string
     DBName       string
+    GazeTopic    string // 시선 샘플 토픽 (세션 ID를 키로 파티션 배치)
+    DLQTopic     string // Consumer가 처리하지 못한 메시지를 보내는 토픽
+    CatalogPath  string // 비어 있으면 내장 약관 카탈로그 사용
+    ReportFont   string // PDF 보고서용 UTF-8 TTF 폰트 (한글 출력)
+
+    // 저장소 종류: postgres(기본), sqlite, memory. sqlite/memory는 로컬 실행과 테스트용
+    StoreBackend string
+    SQLitePath   string // ":memory:"이면 파일 없이 프로세스 안에서만 유지
+
+    // 시작 시 스키마 마이그레이션 자동 적용 (끄면 cmd/migrate로 직접 적용)
+    DBAutoMigrate bool
+
+    // 시선 샘플 저장 경로: kafka(기본) 또는 local (브로커 없이 메인 서버가 직접 저장)
+    EventBus string
+
+    // 시작 시 토픽이 없으면 이 설정으로 만들고, 있으면 설정에 맞는지 확인.
+    // 파티션 수 0(기본)은 기존 토픽을 그대로 쓰고 새로 만들 때 1개
+    KafkaPartitions        int64
+    KafkaReplicationFactor int64
+    KafkaCreateTopics      bool // false면 확인만 하고 없으면 오류
+
+    // Kafka가 받지 못한 샘플을 적어 두는 디스크 스풀. 디렉터리를 비우면 사용하지 않음
+    SpoolDir          string
+    SpoolMaxBytes     int64
+    SpoolSegmentBytes int64
+
+    // 저장 파이프라인 앞 비동기 대기열 (샘플 수)과 가득 찼을 때의 정책: drop_newest(기본), drop_oldest, block(자리가 날 때까지 세션 스트림 전송 대기)
+    PublishQueueSamples   int64
+    PublishOverflowPolicy string
+
+    // 시선 고정 검출 (ivt 또는 idt)
+    FixationAlgorithm   string
+    VelocityThreshold   float64 // px/s
+    DispersionThreshold float64 // px
+    MinFixationMs       int64
+    MaxSampleGapMs      int64
+
+    // 읽기 패턴 분류 및 판정
+    ReadingLineHeight float64 // px
+    RequireReading    bool    // 높은 우선순위 섹션에 '읽기' 방문 필수
+
+    // 시선 샘플 검증
+    GazeMaxClockSkewMs int64   // 클라이언트 timestamp와 서버 시각 허용 차이
+    GazeBoundsMargin   float64 // 뷰포트 밖 허용 여유 (px)
+
+    // 시선 보정 평균 오차 허용치 (px, 0이면 확인하지 않음)
+    CalibrationMaxErrorPx float64
+
+    // Consumer 배치 (건수 또는 첫 메시지 이후 경과 시간 중 먼저 닿는 쪽에서 저장)
+    ConsumerBatchSize      int64
+    ConsumerBatchTimeoutMs int64
+
+    // 역할별 WebSocket 핸드셰이크 토큰. 설정하지 않은 역할은 접속할 수 없다
+    CustomerToken   string
+    EmployeeToken   string
+    SupervisorToken string
 }
 
 func LoadConfig() *Config {
@@ -19,6 +78,47 @@ func LoadConfig() *Config {
         DBUser:       getEnv("DB_USER", "admin"),
         DBPassword:   getEnv("DB_PASSWORD", "1q2w3e4r"),
         DBName:       getEnv("DB_NAME", "eyetracking"),
+        GazeTopic:    getEnv("GAZE_TOPIC", "gaze-data"),
+        DLQTopic:     getEnv("DLQ_TOPIC", "gaze-data-dlq"),
+        CatalogPath:  getEnv("CATALOG_PATH", ""),
+        ReportFont:   getEnv("REPORT_FONT_PATH", ""),
+
+        StoreBackend:  getEnv("STORE_BACKEND", "postgres"),
+        SQLitePath:    getEnv("SQLITE_PATH", "eyetracking.db"),
+        DBAutoMigrate: getEnvBool("DB_AUTO_MIGRATE", true),
+        EventBus:      getEnv("EVENT_BUS", "kafka"),
+
+        KafkaPartitions:        getEnvInt("KAFKA_PARTITIONS", 0),
+        KafkaReplicationFactor: getEnvInt("KAFKA_REPLICATION_FACTOR", 1),
+        KafkaCreateTopics:      getEnvBool("KAFKA_CREATE_TOPICS", true),
+
+        SpoolDir:          getEnv("SPOOL_DIR", "data/spool"),
+        SpoolMaxBytes:     getEnvInt("SPOOL_MAX_BYTES", 1<<30),
+        SpoolSegmentBytes: getEnvInt("SPOOL_SEGMENT_BYTES", 16<<20),
+
+        PublishQueueSamples:   getEnvInt("PUBLISH_QUEUE_SAMPLES", 100000),
+        PublishOverflowPolicy: getEnv("PUBLISH_OVERFLOW_POLICY", "drop_newest"),
+
+        FixationAlgorithm:   getEnv("FIXATION_ALGORITHM", "idt"),
+        VelocityThreshold:   getEnvFloat("FIXATION_VELOCITY_THRESHOLD", 1500),
+        DispersionThreshold: getEnvFloat("FIXATION_DISPERSION_THRESHOLD", 100),
+        MinFixationMs:       getEnvInt("FIXATION_MIN_DURATION_MS", 100),
+        MaxSampleGapMs:      getEnvInt("FIXATION_MAX_GAP_MS", 500),
+
+        ReadingLineHeight: getEnvFloat("READING_LINE_HEIGHT", 28),
+        RequireReading:    getEnvBool("VERDICT_REQUIRE_READING", false),
+
+        GazeMaxClockSkewMs: getEnvInt("GAZE_MAX_CLOCK_SKEW_MS", 60000),
+        GazeBoundsMargin:   getEnvFloat("GAZE_BOUNDS_MARGIN", 50),
+
+        CalibrationMaxErrorPx: getEnvFloat("CALIBRATION_MAX_ERROR_PX", 200),
+
+        ConsumerBatchSize:      getEnvInt("CONSUMER_BATCH_SIZE", 500),
+        ConsumerBatchTimeoutMs: getEnvInt("CONSUMER_BATCH_TIMEOUT_MS", 500),
+
+        CustomerToken:   getEnv("CUSTOMER_TOKEN", ""),
+        EmployeeToken:   getEnv("EMPLOYEE_TOKEN", ""),
+        SupervisorToken: getEnv("SUPERVISOR_TOKEN", ""),
     }
 }
 
@@ -27,4 +127,31 @@ func getEnv(key, defaultValue string) string {
         return value
     }
     return defaultValue
-}
\ No newline at end of file
+}
+
+func getEnvInt(key string, defaultValue int64) int64 {
+    if value := os.Getenv(key); value != "" {
+        if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+            return parsed
+        }
+    }
+    return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+    if value := os.Getenv(key); value != "" {
+        if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+            return parsed
+        }
+    }
+    return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+    if value := os.Getenv(key); value != "" {
+        if parsed, err := strconv.ParseBool(value); err == nil {
+            return parsed
+        }
+    }
+    return defaultValue
+}
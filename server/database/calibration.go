@@ -0,0 +1,79 @@
+package database
+
+import (
+    "database/sql"
+    "encoding/json"
+    "fmt"
+
+    "shinhan-eyetracking/server/models"
+)
+
+func (db *sqlStore) SaveCalibration(c *models.Calibration) error {
+    points, err := json.Marshal(c.Points)
+    if err != nil {
+        return fmt.Errorf("보정 점 마샬링 실패: %w", err)
+    }
+
+    return db.conn.QueryRow(`
+        INSERT INTO calibrations (session_id, points, reported_error_px, accuracy_px, precision_px, max_error_px, point_count, sample_count, screen_width, screen_height, timestamp)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+        RETURNING id, created_at`,
+        c.SessionID, points, c.ReportedErrorPx, c.AccuracyPx, c.PrecisionPx, c.MaxErrorPx,
+        c.PointCount, c.SampleCount, c.ScreenWidth, c.ScreenHeight, c.Timestamp).Scan(&c.ID, &c.CreatedAt)
+}
+
+// 세션의 보정 이력 (오래된 순)
+func (db *sqlStore) GetCalibrations(sessionID string) ([]models.Calibration, error) {
+    rows, err := db.conn.Query(`
+        SELECT id, session_id, points, COALESCE(reported_error_px, 0), accuracy_px, precision_px, max_error_px,
+               point_count, sample_count, COALESCE(screen_width, 0), COALESCE(screen_height, 0), COALESCE(timestamp, 0), created_at
+        FROM calibrations
+        WHERE session_id = $1
+        ORDER BY id`, sessionID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var results []models.Calibration
+    for rows.Next() {
+        c, err := scanCalibration(rows)
+        if err != nil {
+            return nil, err
+        }
+        results = append(results, *c)
+    }
+
+    return results, rows.Err()
+}
+
+// 판정에는 세션의 마지막 보정 결과를 쓴다. 없으면 nil
+func (db *sqlStore) GetLatestCalibration(sessionID string) (*models.Calibration, error) {
+    row := db.conn.QueryRow(`
+        SELECT id, session_id, points, COALESCE(reported_error_px, 0), accuracy_px, precision_px, max_error_px,
+               point_count, sample_count, COALESCE(screen_width, 0), COALESCE(screen_height, 0), COALESCE(timestamp, 0), created_at
+        FROM calibrations
+        WHERE session_id = $1
+        ORDER BY id DESC
+        LIMIT 1`, sessionID)
+
+    c, err := scanCalibration(row)
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    return c, err
+}
+
+func scanCalibration(row rowScanner) (*models.Calibration, error) {
+    var c models.Calibration
+    var points []byte
+    err := row.Scan(&c.ID, &c.SessionID, &points, &c.ReportedErrorPx, &c.AccuracyPx, &c.PrecisionPx, &c.MaxErrorPx,
+        &c.PointCount, &c.SampleCount, &c.ScreenWidth, &c.ScreenHeight, &c.Timestamp, &c.CreatedAt)
+    if err != nil {
+        return nil, err
+    }
+    if err := json.Unmarshal(points, &c.Points); err != nil {
+        return nil, fmt.Errorf("보정 점 언마샬링 실패: %w", err)
+    }
+    return &c, nil
+}
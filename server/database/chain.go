@@ -0,0 +1,120 @@
+package database
+
+import (
+    "database/sql"
+    "fmt"
+
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+)
+
+// 시선 샘플을 체인 레코드로 옮긴다 (seq와 해시는 Link에서 채운다)
+func gazeRecord(data models.GazeData) integrity.Record {
+    return integrity.Record{
+        Kind:           integrity.KindGaze,
+        SessionID:      data.SessionID,
+        Timestamp:      data.Timestamp,
+        X:              data.X,
+        Y:              data.Y,
+        SectionID:      stringValue(data.SectionID),
+        CurrentPage:    stringValue(data.CurrentPage),
+        ViewportWidth:  data.ViewportWidth,
+        ViewportHeight: data.ViewportHeight,
+    }
+}
+
+// 0은 값 없음(NULL)으로 저장
+func nullInt(n int) sql.NullInt64 {
+    return sql.NullInt64{Int64: int64(n), Valid: n != 0}
+}
+
+// 세션 체인 헤드를 잠그고 record를 그 다음 위치에 연결한 뒤 헤드를 전진시킨다.
+// 메인 서버와 Consumer가 같은 세션에 동시에 기록해도 행 잠금으로 순서가 보장된다
+func linkRecord(tx *sql.Tx, record *integrity.Record) error {
+    if record.SessionID == "" {
+        return fmt.Errorf("세션 ID 없는 레코드는 체인에 연결할 수 없음")
+    }
+
+    head, err := lockChainHead(tx, record.SessionID)
+    if err != nil {
+        return err
+    }
+
+    record.Link(head)
+    return advanceChainHead(tx, record.SessionID, integrity.Head{Seq: record.Seq, Hash: record.Hash})
+}
+
+// 헤드 행이 없으면 만들고 트랜잭션이 끝날 때까지 잠근다
+func lockChainHead(tx *sql.Tx, sessionID string) (integrity.Head, error) {
+    _, err := tx.Exec(`
+        INSERT INTO chain_heads (session_id, seq, head_hash) 
+        VALUES ($1, 0, $2) 
+        ON CONFLICT (session_id) DO NOTHING`,
+        sessionID, integrity.GenesisHash)
+    if err != nil {
+        return integrity.Head{}, fmt.Errorf("체인 헤드 생성 실패: %w", err)
+    }
+
+    var head integrity.Head
+    err = tx.QueryRow(`
+        SELECT seq, head_hash FROM chain_heads 
+        WHERE session_id = $1 
+        FOR UPDATE`, sessionID).Scan(&head.Seq, &head.Hash)
+    if err != nil {
+        return integrity.Head{}, fmt.Errorf("체인 헤드 잠금 실패: %w", err)
+    }
+    return head, nil
+}
+
+func advanceChainHead(tx *sql.Tx, sessionID string, head integrity.Head) error {
+    _, err := tx.Exec(`
+        UPDATE chain_heads SET seq = $2, head_hash = $3, updated_at = NOW() 
+        WHERE session_id = $1`,
+        sessionID, head.Seq, head.Hash)
+    if err != nil {
+        return fmt.Errorf("체인 헤드 갱신 실패: %w", err)
+    }
+    return nil
+}
+
+func (db *sqlStore) GetChainHead(sessionID string) (integrity.Head, error) {
+    var head integrity.Head
+    err := db.conn.QueryRow(`
+        SELECT seq, head_hash FROM chain_heads 
+        WHERE session_id = $1`, sessionID).Scan(&head.Seq, &head.Hash)
+    if err == sql.ErrNoRows {
+        return integrity.Head{}, nil
+    }
+    return head, err
+}
+
+// 세션의 시선 데이터와 페이지 변경을 체인 순서대로 조회
+func (db *sqlStore) GetChainRecords(sessionID string) ([]integrity.Record, error) {
+    rows, err := db.conn.Query(`
+        SELECT 'gaze', chain_seq, timestamp, x, y, COALESCE(section_id, ''), COALESCE(current_page, ''), 
+            COALESCE(viewport_width, 0), COALESCE(viewport_height, 0), prev_hash, record_hash 
+        FROM gaze_data 
+        WHERE session_id = $1 AND chain_seq IS NOT NULL 
+        UNION ALL 
+        SELECT 'page', chain_seq, timestamp, 0, 0, '', current_page, 0, 0, prev_hash, record_hash 
+        FROM page_changes 
+        WHERE session_id = $1 AND chain_seq IS NOT NULL 
+        ORDER BY 2`, sessionID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var records []integrity.Record
+    for rows.Next() {
+        r := integrity.Record{SessionID: sessionID}
+        err := rows.Scan(&r.Kind, &r.Seq, &r.Timestamp, &r.X, &r.Y, &r.SectionID, &r.CurrentPage,
+            &r.ViewportWidth, &r.ViewportHeight, &r.PrevHash, &r.Hash)
+        if err != nil {
+            return nil, err
+        }
+        records = append(records, r)
+    }
+
+    return records, rows.Err()
+}
@@ -0,0 +1,233 @@
+package database
+
+import (
+    "strings"
+    "testing"
+
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+)
+
+// 저장소마다 레코드를 직접 고쳐 변조를 흉내 낸다
+type chainBackend struct {
+    open       func(t *testing.T) Store
+    tamper     func(t *testing.T, s Store, seq int64)
+    delete     func(t *testing.T, s Store, seq int64)
+    deleteHead func(t *testing.T, s Store, sessionID string)
+}
+
+var chainBackends = map[string]chainBackend{
+    "메모리": {
+        open: func(t *testing.T) Store { return NewMemoryStore() },
+        tamper: func(t *testing.T, s Store, seq int64) {
+            m := s.(*MemoryStore)
+            for i := range m.gaze {
+                if m.gaze[i].record.Seq == seq {
+                    m.gaze[i].record.X = 999
+                    return
+                }
+            }
+            t.Fatalf("seq %d 레코드 없음", seq)
+        },
+        delete: func(t *testing.T, s Store, seq int64) {
+            m := s.(*MemoryStore)
+            for i := range m.gaze {
+                if m.gaze[i].record.Seq == seq {
+                    m.gaze = append(m.gaze[:i], m.gaze[i+1:]...)
+                    return
+                }
+            }
+            t.Fatalf("seq %d 레코드 없음", seq)
+        },
+        deleteHead: func(t *testing.T, s Store, sessionID string) {
+            delete(s.(*MemoryStore).heads, sessionID)
+        },
+    },
+    "SQLite": {
+        open: func(t *testing.T) Store {
+            s, err := NewSQLiteStore(":memory:")
+            if err != nil {
+                t.Fatalf("SQLite 열기 실패: %v", err)
+            }
+            t.Cleanup(func() { s.Close() })
+            return s
+        },
+        tamper: func(t *testing.T, s Store, seq int64) {
+            execOne(t, s.(*SQLiteStore), `UPDATE gaze_data SET x = 999 WHERE chain_seq = $1`, seq)
+        },
+        delete: func(t *testing.T, s Store, seq int64) {
+            execOne(t, s.(*SQLiteStore), `DELETE FROM gaze_data WHERE chain_seq = $1`, seq)
+        },
+        deleteHead: func(t *testing.T, s Store, sessionID string) {
+            execOne(t, s.(*SQLiteStore), `DELETE FROM chain_heads WHERE session_id = $1`, sessionID)
+        },
+    },
+}
+
+func execOne(t *testing.T, s *SQLiteStore, query string, arg interface{}) {
+    t.Helper()
+
+    result, err := s.conn.Exec(query, arg)
+    if err != nil {
+        t.Fatalf("레코드 수정 실패: %v", err)
+    }
+    if n, _ := result.RowsAffected(); n != 1 {
+        t.Fatalf("%v 레코드 %d건 수정됨", arg, n)
+    }
+}
+
+func saveTestChain(t *testing.T, s Store) {
+    t.Helper()
+
+    page := "productDetail"
+    batch := []models.GazeData{
+        {X: 10, Y: 20, Timestamp: 1000, CurrentPage: &page, SessionID: "s1"},
+        {X: 11, Y: 21, Timestamp: 1020, CurrentPage: &page, SessionID: "s1", ViewportWidth: 1920, ViewportHeight: 1080},
+        {X: 500, Y: 500, Timestamp: 1000, SessionID: "s2"},
+    }
+    if err := s.SaveGazeBatch(batch); err != nil {
+        t.Fatalf("배치 저장 실패: %v", err)
+    }
+    if err := s.SavePageChange(models.PageChangeData{CurrentPage: "productJoin", Timestamp: 1040, SessionID: "s1"}); err != nil {
+        t.Fatalf("페이지 변경 저장 실패: %v", err)
+    }
+    if err := s.SaveGazeData(models.GazeData{X: 12, Y: 22, Timestamp: 1060, SessionID: "s1"}); err != nil {
+        t.Fatalf("시선 데이터 저장 실패: %v", err)
+    }
+}
+
+func verifyChain(t *testing.T, s Store, sessionID string) integrity.Report {
+    t.Helper()
+
+    records, err := s.GetChainRecords(sessionID)
+    if err != nil {
+        t.Fatalf("체인 레코드 조회 실패: %v", err)
+    }
+    head, err := s.GetChainHead(sessionID)
+    if err != nil {
+        t.Fatalf("체인 헤드 조회 실패: %v", err)
+    }
+    return integrity.Verify(sessionID, records, head)
+}
+
+func TestChainAppendAndVerify(t *testing.T) {
+    for name, backend := range chainBackends {
+        t.Run(name, func(t *testing.T) {
+            s := backend.open(t)
+            saveTestChain(t, s)
+
+            report := verifyChain(t, s, "s1")
+            if !report.Valid || report.RecordCount != 4 || report.HeadSeq != 4 {
+                t.Fatalf("s1 체인 검증 결과 이상: %+v", report)
+            }
+            // 세션마다 체인이 따로 시작한다
+            if report := verifyChain(t, s, "s2"); !report.Valid || report.HeadSeq != 1 {
+                t.Fatalf("s2 체인 검증 결과 이상: %+v", report)
+            }
+        })
+    }
+}
+
+func TestChainDetectsTampering(t *testing.T) {
+    for name, backend := range chainBackends {
+        t.Run(name+" 내용 변조", func(t *testing.T) {
+            s := backend.open(t)
+            saveTestChain(t, s)
+            backend.tamper(t, s, 2)
+
+            report := verifyChain(t, s, "s1")
+            if report.Valid || !hasIssue(report, 2, "레코드 내용 변조") {
+                t.Fatalf("변조를 검출하지 못함: %+v", report)
+            }
+        })
+
+        t.Run(name+" 레코드 삭제", func(t *testing.T) {
+            s := backend.open(t)
+            saveTestChain(t, s)
+            backend.delete(t, s, 2)
+
+            report := verifyChain(t, s, "s1")
+            if report.Valid || !hasIssue(report, 2, "레코드 누락") {
+                t.Fatalf("삭제를 검출하지 못함: %+v", report)
+            }
+        })
+
+        t.Run(name+" 꼬리 삭제", func(t *testing.T) {
+            s := backend.open(t)
+            saveTestChain(t, s)
+            backend.delete(t, s, 4)
+
+            report := verifyChain(t, s, "s1")
+            if report.Valid || !hasIssue(report, 4, "레코드 누락") {
+                t.Fatalf("마지막 레코드 삭제를 검출하지 못함: %+v", report)
+            }
+        })
+
+        t.Run(name+" 꼬리와 헤드 삭제", func(t *testing.T) {
+            s := backend.open(t)
+            saveTestChain(t, s)
+            backend.delete(t, s, 4)
+            backend.deleteHead(t, s, "s1")
+
+            report := verifyChain(t, s, "s1")
+            if report.Valid || !hasIssue(report, 3, "체인 헤드 없음") {
+                t.Fatalf("헤드와 함께 지운 꼬리를 검출하지 못함: %+v", report)
+            }
+        })
+    }
+}
+
+// /clear는 세션 체인에 연결된 증적을 지우지 않는다
+func TestClearDataKeepsChainedRecords(t *testing.T) {
+    for name, backend := range chainBackends {
+        t.Run(name, func(t *testing.T) {
+            s := backend.open(t)
+            saveTestChain(t, s)
+
+            gazeRows, pageRows, err := s.ClearData()
+            if err != nil {
+                t.Fatalf("데이터 삭제 실패: %v", err)
+            }
+            if gazeRows != 0 || pageRows != 0 {
+                t.Fatalf("체인에 연결된 레코드를 지움: 시선 %d개, 페이지 변경 %d개", gazeRows, pageRows)
+            }
+            if report := verifyChain(t, s, "s1"); !report.Valid || report.RecordCount != 4 {
+                t.Fatalf("삭제 후 체인 검증 실패: %+v", report)
+            }
+        })
+    }
+}
+
+// 재전달된 샘플은 체인을 늘리지 않는다
+func TestChainSkipsRedeliveredGaze(t *testing.T) {
+    for name, backend := range chainBackends {
+        t.Run(name, func(t *testing.T) {
+            s := backend.open(t)
+            saveTestChain(t, s)
+
+            redelivered := []models.GazeData{
+                {X: 10, Y: 20, Timestamp: 1000, SessionID: "s1"},
+                {X: 12, Y: 22, Timestamp: 1060, SessionID: "s1"},
+                {X: 13, Y: 23, Timestamp: 1080, SessionID: "s1"},
+                {X: 13, Y: 23, Timestamp: 1080, SessionID: "s1"},
+            }
+            if err := s.SaveGazeBatch(redelivered); err != nil {
+                t.Fatalf("재전달 배치 저장 실패: %v", err)
+            }
+
+            report := verifyChain(t, s, "s1")
+            if !report.Valid || report.RecordCount != 5 || report.HeadSeq != 5 {
+                t.Fatalf("새 샘플 하나만 추가되어야 함: %+v", report)
+            }
+        })
+    }
+}
+
+func hasIssue(report integrity.Report, seq int64, problem string) bool {
+    for _, issue := range report.Issues {
+        if issue.Seq == seq && strings.HasPrefix(issue.Problem, problem) {
+            return true
+        }
+    }
+    return false
+}
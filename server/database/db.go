@@ -4,93 +4,184 @@ import (
     "database/sql"
     "fmt"
     "log"
+    "strings"
+    "time"
 
+    "shinhan-eyetracking/server/analysis"
     "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/integrity"
     "shinhan-eyetracking/server/models"
 
-    _ "github.com/lib/pq"
+    "github.com/lib/pq"
 )
 
-type DB struct {
+// PostgreSQL과 SQLite에서 같은 SQL로 동작하는 조회/저장. 방언이 다른 부분은 각 저장소가 구현한다
+type sqlStore struct {
     conn *sql.DB
 }
 
+// PostgreSQL 저장소 (운영)
+type DB struct {
+    sqlStore
+}
+
+// 연결 후 DB_AUTO_MIGRATE가 켜져 있으면 스키마를 최신 버전까지 올린다
 func New(cfg *config.Config) (*DB, error) {
+    db, err := Open(cfg)
+    if err != nil {
+        return nil, err
+    }
+
+    if cfg.DBAutoMigrate {
+        if _, err := db.MigrateUp(0); err != nil {
+            db.Close()
+            return nil, fmt.Errorf("마이그레이션 실패: %w", err)
+        }
+    }
+
+    log.Println("✅ PostgreSQL 연결 완료")
+    return db, nil
+}
+
+// 스키마는 건드리지 않고 연결만 연다 (마이그레이션 CLI용)
+func Open(cfg *config.Config) (*DB, error) {
     connectionString := fmt.Sprintf(
         "host=%s port=5432 user=%s password=%s dbname=%s sslmode=disable",
         cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName,
     )
-    
+
     conn, err := sql.Open("postgres", connectionString)
     if err != nil {
         return nil, fmt.Errorf("DB 연결 실패: %w", err)
     }
 
-    db := &DB{conn: conn}
-    
-    if err := db.createTables(); err != nil {
-        return nil, fmt.Errorf("테이블 생성 실패: %w", err)
-    }
-
-    log.Println("✅ PostgreSQL 연결 완료")
-    return db, nil
+    return &DB{sqlStore{conn: conn}}, nil
 }
 
-func (db *DB) Close() error {
+func (db *sqlStore) Close() error {
     return db.conn.Close()
 }
 
-func (db *DB) createTables() error {
-    // 시선 데이터 테이블
-    _, err := db.conn.Exec(`
-        CREATE TABLE IF NOT EXISTS gaze_data (
-            id SERIAL PRIMARY KEY,
-            x FLOAT NOT NULL,
-            y FLOAT NOT NULL,
-            timestamp BIGINT NOT NULL,
-            section_id VARCHAR(100),
-            current_page VARCHAR(100),
-            created_at TIMESTAMP DEFAULT NOW()
-        )
-    `)
+func (db *sqlStore) Ping() error {
+    return db.conn.Ping()
+}
+
+// 세션 해시 체인에 연결해서 저장 (이미 저장된 샘플 확인까지 배치 경로와 같다)
+func (db *DB) SaveGazeData(data models.GazeData) error {
+    return db.SaveGazeBatch([]models.GazeData{data})
+}
+
+// 세션 해시 체인에 연결해서 저장
+func (db *DB) SavePageChange(data models.PageChangeData) error {
+    tx, err := db.conn.Begin()
     if err != nil {
         return err
     }
+    defer tx.Rollback()
 
-    // 페이지 변경 이력 테이블
-    _, err = db.conn.Exec(`
-        CREATE TABLE IF NOT EXISTS page_changes (
-            id SERIAL PRIMARY KEY,
-            current_page VARCHAR(100) NOT NULL,
-            timestamp BIGINT NOT NULL,
-            created_at TIMESTAMP DEFAULT NOW()
-        )
-    `)
-    return err
+    record := integrity.Record{
+        Kind:        integrity.KindPage,
+        SessionID:   data.SessionID,
+        Timestamp:   data.Timestamp,
+        CurrentPage: data.CurrentPage,
+    }
+    if err := linkRecord(tx, &record); err != nil {
+        return err
+    }
+
+    _, err = tx.Exec(`
+        INSERT INTO page_changes (current_page, timestamp, session_id, chain_seq, prev_hash, record_hash) 
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+        data.CurrentPage, data.Timestamp, nullString(data.SessionID),
+        record.Seq, record.PrevHash, record.Hash)
+    if err != nil {
+        return err
+    }
+
+    return tx.Commit()
 }
 
-func (db *DB) SaveGazeData(data models.GazeData) error {
-    _, err := db.conn.Exec(`
-        INSERT INTO gaze_data (x, y, timestamp, section_id, current_page) 
-        VALUES ($1, $2, $3, $4, $5)`,
-        data.X, data.Y, data.Timestamp, data.SectionID, data.CurrentPage)
-    return err
+// 히트맵 등 분석용 시선 좌표 조회 조건
+type GazeFilter struct {
+    PageID     string
+    SessionIDs []string // 비어 있으면 전체 세션
+    From       int64    // 클라이언트 타임스탬프(ms), 0이면 제한 없음
+    To         int64
+    Limit      int
 }
 
-func (db *DB) SavePageChange(data models.PageChangeData) error {
-    _, err := db.conn.Exec(`
-        INSERT INTO page_changes (current_page, timestamp) 
-        VALUES ($1, $2)`,
-        data.CurrentPage, data.Timestamp)
-    return err
+func (db *DB) GetGazePoints(filter GazeFilter) ([]models.GazeData, error) {
+    query, args := gazePointsQuery(filter, true)
+    return db.queryGazePoints(query, args)
 }
 
-func (db *DB) GetRecentGazeData(limit int) ([]map[string]interface{}, error) {
+// 조건이 있는 경우에만 WHERE 절에 넣는다. "($n = 0 OR timestamp >= $n)"처럼 쓰면
+// PostgreSQL이 $n을 int4로 추론해 epoch ms 경계에서 범위 초과 오류가 난다.
+// arrayParams가 false면(SQLite) 세션 조건을 배열 대신 IN 목록으로 만든다
+func gazePointsQuery(filter GazeFilter, arrayParams bool) (string, []interface{}) {
+    var args []interface{}
+    param := func(value interface{}) string {
+        args = append(args, value)
+        return fmt.Sprintf("$%d", len(args))
+    }
+
+    conditions := []string{"current_page = " + param(filter.PageID)}
+    if len(filter.SessionIDs) > 0 {
+        if arrayParams {
+            conditions = append(conditions, "session_id = ANY("+param(pq.Array(filter.SessionIDs))+")")
+        } else {
+            placeholders := make([]string, len(filter.SessionIDs))
+            for i, id := range filter.SessionIDs {
+                placeholders[i] = param(id)
+            }
+            conditions = append(conditions, "session_id IN ("+strings.Join(placeholders, ", ")+")")
+        }
+    }
+    if filter.From != 0 {
+        conditions = append(conditions, "timestamp >= "+param(filter.From))
+    }
+    if filter.To != 0 {
+        conditions = append(conditions, "timestamp <= "+param(filter.To))
+    }
+
+    query := `
+        SELECT x, y, timestamp, session_id, COALESCE(viewport_width, 0), COALESCE(viewport_height, 0) 
+        FROM gaze_data 
+        WHERE ` + strings.Join(conditions, " AND ") + ` 
+        ORDER BY timestamp 
+        LIMIT ` + param(filter.Limit)
+    return query, args
+}
+
+func (db *sqlStore) queryGazePoints(query string, args []interface{}) ([]models.GazeData, error) {
+    rows, err := db.conn.Query(query, args...)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var results []models.GazeData
+    for rows.Next() {
+        var g models.GazeData
+        var sessionID sql.NullString
+        if err := rows.Scan(&g.X, &g.Y, &g.Timestamp, &sessionID, &g.ViewportWidth, &g.ViewportHeight); err != nil {
+            return nil, err
+        }
+        g.SessionID = sessionID.String
+        results = append(results, g)
+    }
+
+    return results, rows.Err()
+}
+
+// sessionID가 비어 있으면 전체 세션을 대상으로 조회
+func (db *sqlStore) GetRecentGazeData(limit int, sessionID string) ([]map[string]interface{}, error) {
     rows, err := db.conn.Query(`
-        SELECT id, x, y, timestamp, section_id, current_page, created_at 
+        SELECT id, x, y, timestamp, section_id, current_page, session_id, created_at 
         FROM gaze_data 
+        WHERE ($2 = '' OR session_id = $2)
         ORDER BY created_at DESC 
-        LIMIT $1`, limit)
+        LIMIT $1`, limit, sessionID)
     if err != nil {
         return nil, err
     }
@@ -101,10 +192,10 @@ func (db *DB) GetRecentGazeData(limit int) ([]map[string]interface{}, error) {
         var id int
         var x, y float64
         var timestamp int64
-        var sectionID, currentPage sql.NullString
+        var sectionID, currentPage, rowSessionID sql.NullString
         var createdAt sql.NullTime
 
-        err := rows.Scan(&id, &x, &y, &timestamp, &sectionID, &currentPage, &createdAt)
+        err := rows.Scan(&id, &x, &y, &timestamp, &sectionID, &currentPage, &rowSessionID, &createdAt)
         if err != nil {
             continue
         }
@@ -125,6 +216,9 @@ func (db *DB) GetRecentGazeData(limit int) ([]map[string]interface{}, error) {
         if currentPage.Valid {
             result["current_page"] = currentPage.String
         }
+        if rowSessionID.Valid {
+            result["session_id"] = rowSessionID.String
+        }
 
         results = append(results, result)
     }
@@ -132,15 +226,106 @@ func (db *DB) GetRecentGazeData(limit int) ([]map[string]interface{}, error) {
     return results, nil
 }
 
-func (db *DB) ClearData() (gazeRows, pageRows int64, err error) {
-    result1, err1 := db.conn.Exec("DELETE FROM gaze_data")
-    result2, err2 := db.conn.Exec("DELETE FROM page_changes")
+// 세션의 페이지 변경 이력 (시간순)
+func (db *sqlStore) GetPageChanges(sessionID string) ([]models.PageChangeData, error) {
+    rows, err := db.conn.Query(`
+        SELECT current_page, timestamp, session_id 
+        FROM page_changes 
+        WHERE session_id = $1 
+        ORDER BY timestamp, id`, sessionID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
 
-    if err1 != nil {
-        return 0, 0, err1
+    var results []models.PageChangeData
+    for rows.Next() {
+        var p models.PageChangeData
+        if err := rows.Scan(&p.CurrentPage, &p.Timestamp, &p.SessionID); err != nil {
+            return nil, err
+        }
+        results = append(results, p)
     }
-    if err2 != nil {
-        return 0, 0, err2
+
+    return results, rows.Err()
+}
+
+// 세션에 저장된 가장 늦은 시선 timestamp (없으면 0). 재연결한 세션의 timestamp 순서 검증을 여기서부터 이어 간다
+func (db *sqlStore) GetLastGazeTimestamp(sessionID string) (int64, error) {
+    var last int64
+    err := db.conn.QueryRow(`
+        SELECT COALESCE(MAX(timestamp), 0) FROM gaze_data 
+        WHERE session_id = $1`, sessionID).Scan(&last)
+    return last, err
+}
+
+// 세션의 시선 데이터와 페이지 변경을 기록 시각 순으로 병합 조회 (같은 시각이면 페이지 변경이 먼저)
+func (db *sqlStore) GetReplayEvents(sessionID string) ([]models.ReplayEvent, error) {
+    rows, err := db.conn.Query(`
+        SELECT 0 AS kind, id, timestamp, x, y, section_id, current_page 
+        FROM gaze_data 
+        WHERE session_id = $1 
+        UNION ALL 
+        SELECT -1 AS kind, id, timestamp, 0, 0, NULL, current_page 
+        FROM page_changes 
+        WHERE session_id = $1 
+        ORDER BY timestamp, kind, id`, sessionID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var results []models.ReplayEvent
+    for rows.Next() {
+        var kind, id int64
+        var g models.GazeData
+        var sectionID, currentPage sql.NullString
+        err := rows.Scan(&kind, &id, &g.Timestamp, &g.X, &g.Y, &sectionID, &currentPage)
+        if err != nil {
+            return nil, err
+        }
+
+        event := models.ReplayEvent{Timestamp: g.Timestamp}
+        if kind < 0 {
+            event.Page = &models.PageChangeData{
+                CurrentPage: currentPage.String,
+                Timestamp:   g.Timestamp,
+                SessionID:   sessionID,
+            }
+        } else {
+            if sectionID.Valid {
+                g.SectionID = &sectionID.String
+            }
+            if currentPage.Valid {
+                g.CurrentPage = &currentPage.String
+            }
+            g.SessionID = sessionID
+            event.Gaze = &g
+        }
+        results = append(results, event)
+    }
+
+    return results, rows.Err()
+}
+
+// 두 테이블을 한 트랜잭션으로 지운다. 세션 체인에 연결된 증적은 CleanOldData와 마찬가지로 남긴다
+func (db *sqlStore) ClearData() (gazeRows, pageRows int64, err error) {
+    tx, err := db.conn.Begin()
+    if err != nil {
+        return 0, 0, err
+    }
+    defer tx.Rollback()
+
+    result1, err := tx.Exec("DELETE FROM gaze_data WHERE chain_seq IS NULL")
+    if err != nil {
+        return 0, 0, err
+    }
+    result2, err := tx.Exec("DELETE FROM page_changes WHERE chain_seq IS NULL")
+    if err != nil {
+        return 0, 0, err
+    }
+    if err := tx.Commit(); err != nil {
+        return 0, 0, err
     }
 
     gazeRows, _ = result1.RowsAffected()
@@ -149,9 +334,11 @@ func (db *DB) ClearData() (gazeRows, pageRows int64, err error) {
     return gazeRows, pageRows, nil
 }
 
+// 세션 체인에 연결된 레코드는 보존 기간과 상관없이 남긴다 (지우면 검증에서 누락으로 드러나 증적이 깨진다).
+// 정리 대상은 세션 도입 전이나 세션 없이 저장된 레코드뿐
 func (db *DB) CleanOldData() (gazeRows, pageRows int64, err error) {
-    result1, err1 := db.conn.Exec("DELETE FROM gaze_data WHERE created_at < NOW() - INTERVAL '7 days'")
-    result2, err2 := db.conn.Exec("DELETE FROM page_changes WHERE created_at < NOW() - INTERVAL '7 days'")
+    result1, err1 := db.conn.Exec("DELETE FROM gaze_data WHERE created_at < NOW() - INTERVAL '7 days' AND chain_seq IS NULL")
+    result2, err2 := db.conn.Exec("DELETE FROM page_changes WHERE created_at < NOW() - INTERVAL '7 days' AND chain_seq IS NULL")
 
     if err1 != nil {
         return 0, 0, err1
@@ -164,4 +351,239 @@ func (db *DB) CleanOldData() (gazeRows, pageRows int64, err error) {
     pageRows, _ = result2.RowsAffected()
 
     return gazeRows, pageRows, nil
-}
\ No newline at end of file
+}
+
+func (db *sqlStore) CreateSession(session models.Session) error {
+    _, err := db.conn.Exec(`
+        INSERT INTO sessions (id, branch_id, employee_id, product_id, terms_version, started_at) 
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+        session.ID, session.BranchID, session.EmployeeID, session.ProductID, session.TermsVersion, session.StartedAt)
+    return err
+}
+
+// 이미 종료된 세션은 다시 종료하지 않음
+func (db *sqlStore) EndSession(sessionID string, endedAt time.Time) error {
+    result, err := db.conn.Exec(`
+        UPDATE sessions SET ended_at = $2 
+        WHERE id = $1 AND ended_at IS NULL`,
+        sessionID, endedAt)
+    if err != nil {
+        return err
+    }
+
+    affected, _ := result.RowsAffected()
+    if affected == 0 {
+        return fmt.Errorf("진행 중인 세션 없음: %s", sessionID)
+    }
+    return nil
+}
+
+func (db *sqlStore) GetSession(sessionID string) (*models.Session, error) {
+    row := db.conn.QueryRow(`
+        SELECT id, branch_id, employee_id, product_id, terms_version, started_at, ended_at 
+        FROM sessions 
+        WHERE id = $1`, sessionID)
+
+    session, err := scanSession(row)
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    return session, err
+}
+
+func (db *sqlStore) GetRecentSessions(limit int) ([]models.Session, error) {
+    rows, err := db.conn.Query(`
+        SELECT id, branch_id, employee_id, product_id, terms_version, started_at, ended_at 
+        FROM sessions 
+        ORDER BY started_at DESC 
+        LIMIT $1`, limit)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var sessions []models.Session
+    for rows.Next() {
+        session, err := scanSession(rows)
+        if err != nil {
+            continue
+        }
+        sessions = append(sessions, *session)
+    }
+
+    return sessions, nil
+}
+
+type rowScanner interface {
+    Scan(dest ...interface{}) error
+}
+
+func scanSession(row rowScanner) (*models.Session, error) {
+    var session models.Session
+    var branchID, employeeID, productID, termsVersion sql.NullString
+    var endedAt sql.NullTime
+
+    err := row.Scan(&session.ID, &branchID, &employeeID, &productID, &termsVersion, &session.StartedAt, &endedAt)
+    if err != nil {
+        return nil, err
+    }
+
+    session.BranchID = branchID.String
+    session.EmployeeID = employeeID.String
+    session.ProductID = productID.String
+    session.TermsVersion = termsVersion.String
+    if endedAt.Valid {
+        session.EndedAt = &endedAt.Time
+    }
+
+    return &session, nil
+}
+
+func stringValue(s *string) string {
+    if s == nil {
+        return ""
+    }
+    return *s
+}
+
+// 빈 문자열은 NULL로 저장
+func nullString(s string) sql.NullString {
+    return sql.NullString{String: s, Valid: s != ""}
+}
+
+// 누적값 전체를 덮어쓴다 (증분이 아니라 서버가 계산한 총합)
+func (db *sqlStore) SaveSectionDwell(dwell []models.SectionDwell) error {
+    tx, err := db.conn.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for _, d := range dwell {
+        _, err := tx.Exec(`
+            INSERT INTO section_dwell (session_id, page_id, section_id, dwell_ms, fixation_count, sample_count, updated_at) 
+            VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP) 
+            ON CONFLICT (session_id, page_id, section_id) 
+            DO UPDATE SET dwell_ms = EXCLUDED.dwell_ms, fixation_count = EXCLUDED.fixation_count, 
+                sample_count = EXCLUDED.sample_count, updated_at = CURRENT_TIMESTAMP`,
+            d.SessionID, d.PageID, d.SectionID, d.DwellMs, d.FixationCount, d.SampleCount)
+        if err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+func (db *sqlStore) GetSectionDwell(sessionID string) ([]models.SectionDwell, error) {
+    rows, err := db.conn.Query(`
+        SELECT session_id, page_id, section_id, dwell_ms, fixation_count, sample_count 
+        FROM section_dwell 
+        WHERE session_id = $1 
+        ORDER BY page_id, section_id`, sessionID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var results []models.SectionDwell
+    for rows.Next() {
+        var d models.SectionDwell
+        if err := rows.Scan(&d.SessionID, &d.PageID, &d.SectionID, &d.DwellMs, &d.FixationCount, &d.SampleCount); err != nil {
+            return nil, err
+        }
+        results = append(results, d)
+    }
+
+    return results, rows.Err()
+}
+
+func (db *sqlStore) SaveFixations(fixations []analysis.Fixation) error {
+    tx, err := db.conn.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for _, f := range fixations {
+        _, err := tx.Exec(`
+            INSERT INTO fixations (session_id, page_id, section_id, x, y, start_ts, duration_ms, sample_count) 
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+            f.SessionID, nullString(f.PageID), nullString(f.SectionID), f.X, f.Y, f.StartMs, f.DurationMs, f.SampleCount)
+        if err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+// 세션의 시선 고정 (시간순)
+func (db *sqlStore) GetFixations(sessionID string) ([]analysis.Fixation, error) {
+    rows, err := db.conn.Query(`
+        SELECT session_id, COALESCE(page_id, ''), COALESCE(section_id, ''), x, y, start_ts, duration_ms, sample_count 
+        FROM fixations 
+        WHERE session_id = $1 
+        ORDER BY start_ts`, sessionID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var results []analysis.Fixation
+    for rows.Next() {
+        var f analysis.Fixation
+        err := rows.Scan(&f.SessionID, &f.PageID, &f.SectionID, &f.X, &f.Y, &f.StartMs, &f.DurationMs, &f.SampleCount)
+        if err != nil {
+            return nil, err
+        }
+        results = append(results, f)
+    }
+
+    return results, rows.Err()
+}
+
+func (db *sqlStore) SaveSaccades(saccades []analysis.Saccade) error {
+    tx, err := db.conn.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    for _, s := range saccades {
+        _, err := tx.Exec(`
+            INSERT INTO saccades (session_id, page_id, from_x, from_y, to_x, to_y, start_ts, duration_ms, amplitude) 
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+            s.SessionID, nullString(s.PageID), s.FromX, s.FromY, s.ToX, s.ToY, s.StartMs, s.DurationMs, s.Amplitude)
+        if err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+// 세션의 도약 (시간순)
+func (db *sqlStore) GetSaccades(sessionID string) ([]analysis.Saccade, error) {
+    rows, err := db.conn.Query(`
+        SELECT session_id, COALESCE(page_id, ''), from_x, from_y, to_x, to_y, start_ts, duration_ms, amplitude 
+        FROM saccades 
+        WHERE session_id = $1 
+        ORDER BY start_ts`, sessionID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    var results []analysis.Saccade
+    for rows.Next() {
+        var s analysis.Saccade
+        err := rows.Scan(&s.SessionID, &s.PageID, &s.FromX, &s.FromY, &s.ToX, &s.ToY, &s.StartMs, &s.DurationMs, &s.Amplitude)
+        if err != nil {
+            return nil, err
+        }
+        results = append(results, s)
+    }
+
+    return results, rows.Err()
+}
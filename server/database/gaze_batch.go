@@ -0,0 +1,143 @@
+package database
+
+import (
+    "database/sql"
+    "fmt"
+    "log"
+    "sort"
+
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+
+    "github.com/lib/pq"
+)
+
+// 여러 시선 샘플을 한 트랜잭션으로 저장. 세션별로 체인 헤드를 한 번만 잠가 순서대로 연결한 뒤 COPY로 넣는다.
+// 샘플은 세션 안에서 받은 순서를 유지해야 한다. 이미 체인에 들어간 (세션, timestamp) 샘플은 건너뛴다
+func (db *DB) SaveGazeBatch(samples []models.GazeData) error {
+    bySession := make(map[string][]models.GazeData)
+    for _, data := range samples {
+        if data.SessionID == "" {
+            return fmt.Errorf("세션 ID 없는 시선 데이터는 체인에 연결할 수 없음")
+        }
+        bySession[data.SessionID] = append(bySession[data.SessionID], data)
+    }
+    if len(bySession) == 0 {
+        return nil
+    }
+
+    // 여러 프로세스가 동시에 잠가도 교착되지 않도록 세션 ID 순서로 잠근다
+    sessionIDs := make([]string, 0, len(bySession))
+    for sessionID := range bySession {
+        sessionIDs = append(sessionIDs, sessionID)
+    }
+    sort.Strings(sessionIDs)
+
+    tx, err := db.conn.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    // COPY 도중에는 다른 쿼리를 실행할 수 없으므로 헤드 잠금과 연결을 먼저 끝낸다
+    var records []integrity.Record
+    heads := make(map[string]integrity.Head, len(sessionIDs))
+    for _, sessionID := range sessionIDs {
+        head, err := lockChainHead(tx, sessionID)
+        if err != nil {
+            return err
+        }
+        fresh, err := skipStoredGaze(tx, sessionID, bySession[sessionID])
+        if err != nil {
+            return err
+        }
+        for _, data := range fresh {
+            record := gazeRecord(data)
+            record.Link(head)
+            head = integrity.Head{Seq: record.Seq, Hash: record.Hash}
+            records = append(records, record)
+        }
+        heads[sessionID] = head
+    }
+
+    if err := copyGazeRecords(tx, records); err != nil {
+        return err
+    }
+
+    for _, sessionID := range sessionIDs {
+        if err := advanceChainHead(tx, sessionID, heads[sessionID]); err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+// 헤드를 잠근 뒤에 호출해야 확인과 저장 사이에 다른 쓰기가 끼지 않는다.
+// 전달이 at-least-once라 Consumer 재전달, 스풀 재전송, DLQ 재처리로 같은 샘플이 다시 올 수 있고,
+// 세션 안의 timestamp는 검증 단계에서 엄격히 증가하므로 (세션, timestamp)로 같은 샘플을 가린다
+func skipStoredGaze(tx *sql.Tx, sessionID string, samples []models.GazeData) ([]models.GazeData, error) {
+    timestamps := make([]int64, len(samples))
+    for i, data := range samples {
+        timestamps[i] = data.Timestamp
+    }
+
+    rows, err := tx.Query(`
+        SELECT timestamp FROM gaze_data 
+        WHERE session_id = $1 AND timestamp = ANY($2)`,
+        sessionID, pq.Array(timestamps))
+    if err != nil {
+        return nil, fmt.Errorf("중복 시선 데이터 확인 실패: %w", err)
+    }
+    defer rows.Close()
+
+    stored := make(map[int64]bool)
+    for rows.Next() {
+        var ts int64
+        if err := rows.Scan(&ts); err != nil {
+            return nil, err
+        }
+        stored[ts] = true
+    }
+    if err := rows.Err(); err != nil {
+        return nil, err
+    }
+
+    fresh := make([]models.GazeData, 0, len(samples))
+    for _, data := range samples {
+        // 배치 안에서 겹친 샘플도 한 번만 넣는다
+        if stored[data.Timestamp] {
+            continue
+        }
+        stored[data.Timestamp] = true
+        fresh = append(fresh, data)
+    }
+    if skipped := len(samples) - len(fresh); skipped > 0 {
+        log.Printf("♻️ 이미 저장된 시선 샘플 %d건 건너뜀 [%s]", skipped, sessionID)
+    }
+    return fresh, nil
+}
+
+func copyGazeRecords(tx *sql.Tx, records []integrity.Record) error {
+    stmt, err := tx.Prepare(pq.CopyIn("gaze_data",
+        "x", "y", "timestamp", "section_id", "current_page", "session_id", "chain_seq", "prev_hash", "record_hash",
+        "viewport_width", "viewport_height"))
+    if err != nil {
+        return fmt.Errorf("COPY 준비 실패: %w", err)
+    }
+    defer stmt.Close()
+
+    for _, r := range records {
+        _, err := stmt.Exec(r.X, r.Y, r.Timestamp, nullString(r.SectionID), nullString(r.CurrentPage),
+            r.SessionID, r.Seq, r.PrevHash, r.Hash, nullInt(r.ViewportWidth), nullInt(r.ViewportHeight))
+        if err != nil {
+            return fmt.Errorf("COPY 행 추가 실패: %w", err)
+        }
+    }
+
+    // 인자 없는 Exec로 버퍼를 비워 COPY를 마친다
+    if _, err := stmt.Exec(); err != nil {
+        return fmt.Errorf("COPY 실패: %w", err)
+    }
+    return nil
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+    "os"
+    "strings"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/models"
+)
+
+// 실제 클라이언트 타임스탬프 (epoch ms, int4 범위 밖)
+const epochMs = int64(1726000000000)
+
+func gazePointsSamples(sessionID string) []models.GazeData {
+    page := "productDetail"
+    samples := make([]models.GazeData, 5)
+    for i := range samples {
+        samples[i] = models.GazeData{
+            X:           float64(100 + i),
+            Y:           200,
+            Timestamp:   epochMs + int64(i)*1000,
+            CurrentPage: &page,
+            SessionID:   sessionID,
+        }
+    }
+    return samples
+}
+
+func expectGazePoints(t *testing.T, s Store, sessionID string) {
+    t.Helper()
+
+    if err := s.SaveGazeBatch(gazePointsSamples(sessionID)); err != nil {
+        t.Fatalf("시선 데이터 저장 실패: %v", err)
+    }
+
+    cases := map[string]struct {
+        filter GazeFilter
+        want   []int64 // epochMs 기준 오프셋
+    }{
+        "경계 없음":  {GazeFilter{}, []int64{0, 1000, 2000, 3000, 4000}},
+        "시작 경계":  {GazeFilter{From: epochMs + 2000}, []int64{2000, 3000, 4000}},
+        "끝 경계":   {GazeFilter{To: epochMs + 1000}, []int64{0, 1000}},
+        "양쪽 경계":  {GazeFilter{From: epochMs + 1000, To: epochMs + 3000}, []int64{1000, 2000, 3000}},
+        "개수 제한":  {GazeFilter{From: epochMs + 1000, Limit: 2}, []int64{1000, 2000}},
+        "다른 페이지": {GazeFilter{PageID: "productJoin"}, nil},
+    }
+
+    for name, tc := range cases {
+        t.Run(name, func(t *testing.T) {
+            filter := tc.filter
+            if filter.PageID == "" {
+                filter.PageID = "productDetail"
+            }
+            if filter.Limit == 0 {
+                filter.Limit = 100
+            }
+            filter.SessionIDs = []string{sessionID}
+
+            points, err := s.GetGazePoints(filter)
+            if err != nil {
+                t.Fatalf("시선 좌표 조회 실패: %v", err)
+            }
+            if len(points) != len(tc.want) {
+                t.Fatalf("좌표 %d개, 기대값 %d개: %+v", len(points), len(tc.want), points)
+            }
+            for i, p := range points {
+                if p.Timestamp != epochMs+tc.want[i] {
+                    t.Fatalf("%d번째 좌표 timestamp = %d, 기대값 %d", i, p.Timestamp, epochMs+tc.want[i])
+                }
+            }
+        })
+    }
+}
+
+func TestGetGazePointsWithEpochBounds(t *testing.T) {
+    t.Run("메모리", func(t *testing.T) {
+        expectGazePoints(t, NewMemoryStore(), "s1")
+    })
+    t.Run("SQLite", func(t *testing.T) {
+        s, err := NewSQLiteStore(":memory:")
+        if err != nil {
+            t.Fatalf("SQLite 열기 실패: %v", err)
+        }
+        defer s.Close()
+        expectGazePoints(t, s, "s1")
+    })
+}
+
+// PostgreSQL 쿼리는 경계가 있을 때만 조건을 넣어 int4로 추론될 자리를 만들지 않는다
+func TestGazePointsQueryBindsOnlyGivenBounds(t *testing.T) {
+    query, args := gazePointsQuery(GazeFilter{PageID: "productDetail", From: epochMs, To: epochMs + 1000, Limit: 10}, true)
+    if strings.Contains(query, "= 0") {
+        t.Fatalf("0과 비교하는 조건이 남아 있음:\n%s", query)
+    }
+    if !strings.Contains(query, "timestamp >= $2") || !strings.Contains(query, "timestamp <= $3") {
+        t.Fatalf("시간 경계 조건 누락:\n%s", query)
+    }
+    if args[1] != epochMs || args[2] != epochMs+1000 {
+        t.Fatalf("시간 경계 인자 = %v, %v", args[1], args[2])
+    }
+
+    query, args = gazePointsQuery(GazeFilter{PageID: "productDetail", Limit: 10}, true)
+    if strings.Contains(query, "timestamp >=") || strings.Contains(query, "timestamp <=") || len(args) != 2 {
+        t.Fatalf("경계가 없는데 시간 조건이 들어감 (인자 %v):\n%s", args, query)
+    }
+}
+
+// 실제 PostgreSQL에서 확인 (TEST_POSTGRES=1과 DB_HOST 등 접속 설정이 있을 때만)
+func TestPostgresGetGazePointsWithEpochBounds(t *testing.T) {
+    if os.Getenv("TEST_POSTGRES") == "" {
+        t.Skip("TEST_POSTGRES가 설정되지 않아 PostgreSQL 테스트를 건너뜀")
+    }
+
+    cfg := config.LoadConfig()
+    cfg.DBAutoMigrate = true
+    db, err := New(cfg)
+    if err != nil {
+        t.Fatalf("PostgreSQL 연결 실패: %v", err)
+    }
+    defer db.Close()
+
+    expectGazePoints(t, db, "test-epoch-"+time.Now().Format("20060102150405.000000000"))
+}
@@ -0,0 +1,520 @@
+package database
+
+import (
+    "fmt"
+    "sort"
+    "sync"
+    "time"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+)
+
+// 프로세스 메모리에만 두는 저장소. 재시작하면 사라지므로 로컬 실행과 테스트용
+type MemoryStore struct {
+    mu sync.RWMutex
+
+    nextID       int64
+    gaze         []memoryGaze
+    pages        []memoryPage
+    sessions     map[string]models.Session
+    dwell        map[string]models.SectionDwell // session_id/page_id/section_id
+    fixations    []analysis.Fixation
+    saccades     []analysis.Saccade
+    calibrations []models.Calibration
+    heads        map[string]integrity.Head
+    gazeKeys     map[string]map[int64]bool // session_id -> 저장된 timestamp
+}
+
+// CleanOldData 보존 기간 (PostgreSQL/SQLite의 7일 기준과 같음)
+const retentionPeriod = 7 * 24 * time.Hour
+
+type memoryGaze struct {
+    id        int64
+    data      models.GazeData
+    record    integrity.Record
+    createdAt time.Time
+}
+
+type memoryPage struct {
+    id        int64
+    data      models.PageChangeData
+    record    integrity.Record
+    createdAt time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+    return &MemoryStore{
+        sessions: make(map[string]models.Session),
+        dwell:    make(map[string]models.SectionDwell),
+        heads:    make(map[string]integrity.Head),
+        gazeKeys: make(map[string]map[int64]bool),
+    }
+}
+
+func (m *MemoryStore) Close() error {
+    return nil
+}
+
+func (m *MemoryStore) Ping() error {
+    return nil
+}
+
+// m.mu를 잡은 상태에서 호출
+func (m *MemoryStore) link(record *integrity.Record) error {
+    if record.SessionID == "" {
+        return fmt.Errorf("세션 ID 없는 레코드는 체인에 연결할 수 없음")
+    }
+
+    head, exists := m.heads[record.SessionID]
+    if !exists {
+        head = integrity.Head{Hash: integrity.GenesisHash}
+    }
+    record.Link(head)
+    m.heads[record.SessionID] = integrity.Head{Seq: record.Seq, Hash: record.Hash}
+    return nil
+}
+
+// m.mu를 잡은 상태에서 호출. 이미 저장된 (세션, timestamp) 샘플은 건너뛴다 (DB.SaveGazeBatch와 같은 기준)
+func (m *MemoryStore) appendGaze(data models.GazeData) error {
+    if m.gazeKeys[data.SessionID][data.Timestamp] {
+        return nil
+    }
+
+    record := gazeRecord(data)
+    if err := m.link(&record); err != nil {
+        return err
+    }
+
+    m.nextID++
+    m.gaze = append(m.gaze, memoryGaze{id: m.nextID, data: data, record: record, createdAt: time.Now()})
+    if m.gazeKeys[data.SessionID] == nil {
+        m.gazeKeys[data.SessionID] = make(map[int64]bool)
+    }
+    m.gazeKeys[data.SessionID][data.Timestamp] = true
+    return nil
+}
+
+func (m *MemoryStore) SaveGazeData(data models.GazeData) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    return m.appendGaze(data)
+}
+
+// 하나라도 세션 ID가 없으면 아무것도 저장하지 않는다 (PostgreSQL 트랜잭션과 같은 동작)
+func (m *MemoryStore) SaveGazeBatch(samples []models.GazeData) error {
+    for _, data := range samples {
+        if data.SessionID == "" {
+            return fmt.Errorf("세션 ID 없는 시선 데이터는 체인에 연결할 수 없음")
+        }
+    }
+
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, data := range samples {
+        if err := m.appendGaze(data); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (m *MemoryStore) SavePageChange(data models.PageChangeData) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    record := integrity.Record{
+        Kind:        integrity.KindPage,
+        SessionID:   data.SessionID,
+        Timestamp:   data.Timestamp,
+        CurrentPage: data.CurrentPage,
+    }
+    if err := m.link(&record); err != nil {
+        return err
+    }
+
+    m.nextID++
+    m.pages = append(m.pages, memoryPage{id: m.nextID, data: data, record: record, createdAt: time.Now()})
+    return nil
+}
+
+func (m *MemoryStore) GetGazePoints(filter GazeFilter) ([]models.GazeData, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    sessions := make(map[string]bool, len(filter.SessionIDs))
+    for _, id := range filter.SessionIDs {
+        sessions[id] = true
+    }
+
+    var results []models.GazeData
+    for _, g := range m.gaze {
+        if stringValue(g.data.CurrentPage) != filter.PageID {
+            continue
+        }
+        if len(sessions) > 0 && !sessions[g.data.SessionID] {
+            continue
+        }
+        if (filter.From != 0 && g.data.Timestamp < filter.From) || (filter.To != 0 && g.data.Timestamp > filter.To) {
+            continue
+        }
+        results = append(results, models.GazeData{
+            X:              g.data.X,
+            Y:              g.data.Y,
+            Timestamp:      g.data.Timestamp,
+            SessionID:      g.data.SessionID,
+            ViewportWidth:  g.data.ViewportWidth,
+            ViewportHeight: g.data.ViewportHeight,
+        })
+    }
+
+    sort.SliceStable(results, func(i, j int) bool {
+        return results[i].Timestamp < results[j].Timestamp
+    })
+    if len(results) > filter.Limit {
+        results = results[:filter.Limit]
+    }
+    return results, nil
+}
+
+// sessionID가 비어 있으면 전체 세션을 대상으로 조회
+func (m *MemoryStore) GetRecentGazeData(limit int, sessionID string) ([]map[string]interface{}, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var results []map[string]interface{}
+    for i := len(m.gaze) - 1; i >= 0 && len(results) < limit; i-- {
+        g := m.gaze[i]
+        if sessionID != "" && g.data.SessionID != sessionID {
+            continue
+        }
+
+        result := map[string]interface{}{
+            "id":         int(g.id),
+            "x":          g.data.X,
+            "y":          g.data.Y,
+            "timestamp":  g.data.Timestamp,
+            "created_at": g.createdAt.Format("2006-01-02 15:04:05"),
+            "session_id": g.data.SessionID,
+        }
+        if g.data.SectionID != nil {
+            result["section_id"] = *g.data.SectionID
+        }
+        if g.data.CurrentPage != nil {
+            result["current_page"] = *g.data.CurrentPage
+        }
+        results = append(results, result)
+    }
+
+    return results, nil
+}
+
+// 세션의 페이지 변경 이력 (시간순)
+func (m *MemoryStore) GetPageChanges(sessionID string) ([]models.PageChangeData, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var results []models.PageChangeData
+    for _, p := range m.pages {
+        if p.data.SessionID == sessionID {
+            results = append(results, p.data)
+        }
+    }
+
+    sort.SliceStable(results, func(i, j int) bool {
+        return results[i].Timestamp < results[j].Timestamp
+    })
+    return results, nil
+}
+
+// 세션의 시선 데이터와 페이지 변경을 기록 시각 순으로 병합 조회 (같은 시각이면 페이지 변경이 먼저)
+func (m *MemoryStore) GetLastGazeTimestamp(sessionID string) (int64, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var last int64
+    for _, g := range m.gaze {
+        if g.data.SessionID == sessionID && g.data.Timestamp > last {
+            last = g.data.Timestamp
+        }
+    }
+    return last, nil
+}
+
+func (m *MemoryStore) GetReplayEvents(sessionID string) ([]models.ReplayEvent, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var results []models.ReplayEvent
+    for _, p := range m.pages {
+        if p.data.SessionID == sessionID {
+            page := p.data
+            results = append(results, models.ReplayEvent{Timestamp: page.Timestamp, Page: &page})
+        }
+    }
+    for _, g := range m.gaze {
+        if g.data.SessionID == sessionID {
+            gaze := g.data
+            results = append(results, models.ReplayEvent{Timestamp: gaze.Timestamp, Gaze: &gaze})
+        }
+    }
+
+    // 페이지 변경을 먼저 넣었으므로 안정 정렬이면 같은 시각에서 페이지 변경이 앞선다
+    sort.SliceStable(results, func(i, j int) bool {
+        return results[i].Timestamp < results[j].Timestamp
+    })
+    return results, nil
+}
+
+// 체인에 연결된 레코드는 남긴다 (sqlStore.ClearData와 같은 기준)
+func (m *MemoryStore) ClearData() (gazeRows, pageRows int64, err error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    gazeRows, pageRows = m.removeUnchained(func(time.Time) bool { return true })
+    return gazeRows, pageRows, nil
+}
+
+// 체인에 연결된 레코드는 남긴다 (DB.CleanOldData와 같은 기준)
+func (m *MemoryStore) CleanOldData() (gazeRows, pageRows int64, err error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    cutoff := time.Now().Add(-retentionPeriod)
+    gazeRows, pageRows = m.removeUnchained(func(createdAt time.Time) bool { return createdAt.Before(cutoff) })
+    return gazeRows, pageRows, nil
+}
+
+// 체인에 연결되지 않은 레코드 중 expired인 것을 지운다. m.mu를 잡은 상태에서 호출
+func (m *MemoryStore) removeUnchained(expired func(createdAt time.Time) bool) (gazeRows, pageRows int64) {
+    keptGaze := m.gaze[:0]
+    for _, g := range m.gaze {
+        if g.record.Seq == 0 && expired(g.createdAt) {
+            gazeRows++
+            continue
+        }
+        keptGaze = append(keptGaze, g)
+    }
+    m.gaze = keptGaze
+
+    keptPages := m.pages[:0]
+    for _, p := range m.pages {
+        if p.record.Seq == 0 && expired(p.createdAt) {
+            pageRows++
+            continue
+        }
+        keptPages = append(keptPages, p)
+    }
+    m.pages = keptPages
+
+    return gazeRows, pageRows
+}
+
+func (m *MemoryStore) CreateSession(session models.Session) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    if _, exists := m.sessions[session.ID]; exists {
+        return fmt.Errorf("이미 존재하는 세션: %s", session.ID)
+    }
+    m.sessions[session.ID] = session
+    return nil
+}
+
+// 이미 종료된 세션은 다시 종료하지 않음
+func (m *MemoryStore) EndSession(sessionID string, endedAt time.Time) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    session, exists := m.sessions[sessionID]
+    if !exists || session.EndedAt != nil {
+        return fmt.Errorf("진행 중인 세션 없음: %s", sessionID)
+    }
+    session.EndedAt = &endedAt
+    m.sessions[sessionID] = session
+    return nil
+}
+
+func (m *MemoryStore) GetSession(sessionID string) (*models.Session, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    session, exists := m.sessions[sessionID]
+    if !exists {
+        return nil, nil
+    }
+    return &session, nil
+}
+
+func (m *MemoryStore) GetRecentSessions(limit int) ([]models.Session, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    sessions := make([]models.Session, 0, len(m.sessions))
+    for _, session := range m.sessions {
+        sessions = append(sessions, session)
+    }
+
+    sort.Slice(sessions, func(i, j int) bool {
+        return sessions[i].StartedAt.After(sessions[j].StartedAt)
+    })
+    if len(sessions) > limit {
+        sessions = sessions[:limit]
+    }
+    return sessions, nil
+}
+
+// 누적값 전체를 덮어쓴다 (증분이 아니라 서버가 계산한 총합)
+func (m *MemoryStore) SaveSectionDwell(dwell []models.SectionDwell) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    for _, d := range dwell {
+        m.dwell[d.SessionID+"/"+d.PageID+"/"+d.SectionID] = d
+    }
+    return nil
+}
+
+func (m *MemoryStore) GetSectionDwell(sessionID string) ([]models.SectionDwell, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var results []models.SectionDwell
+    for _, d := range m.dwell {
+        if d.SessionID == sessionID {
+            results = append(results, d)
+        }
+    }
+
+    sort.Slice(results, func(i, j int) bool {
+        if results[i].PageID != results[j].PageID {
+            return results[i].PageID < results[j].PageID
+        }
+        return results[i].SectionID < results[j].SectionID
+    })
+    return results, nil
+}
+
+func (m *MemoryStore) SaveFixations(fixations []analysis.Fixation) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.fixations = append(m.fixations, fixations...)
+    return nil
+}
+
+// 세션의 시선 고정 (시간순)
+func (m *MemoryStore) GetFixations(sessionID string) ([]analysis.Fixation, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var results []analysis.Fixation
+    for _, f := range m.fixations {
+        if f.SessionID == sessionID {
+            results = append(results, f)
+        }
+    }
+
+    sort.SliceStable(results, func(i, j int) bool {
+        return results[i].StartMs < results[j].StartMs
+    })
+    return results, nil
+}
+
+func (m *MemoryStore) SaveSaccades(saccades []analysis.Saccade) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.saccades = append(m.saccades, saccades...)
+    return nil
+}
+
+// 세션의 도약 (시간순)
+func (m *MemoryStore) GetSaccades(sessionID string) ([]analysis.Saccade, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var results []analysis.Saccade
+    for _, s := range m.saccades {
+        if s.SessionID == sessionID {
+            results = append(results, s)
+        }
+    }
+
+    sort.SliceStable(results, func(i, j int) bool {
+        return results[i].StartMs < results[j].StartMs
+    })
+    return results, nil
+}
+
+func (m *MemoryStore) SaveCalibration(c *models.Calibration) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+
+    m.nextID++
+    c.ID = m.nextID
+    c.CreatedAt = time.Now()
+    m.calibrations = append(m.calibrations, *c)
+    return nil
+}
+
+// 세션의 보정 이력 (오래된 순)
+func (m *MemoryStore) GetCalibrations(sessionID string) ([]models.Calibration, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var results []models.Calibration
+    for _, c := range m.calibrations {
+        if c.SessionID == sessionID {
+            results = append(results, c)
+        }
+    }
+    return results, nil
+}
+
+// 판정에는 세션의 마지막 보정 결과를 쓴다. 없으면 nil
+func (m *MemoryStore) GetLatestCalibration(sessionID string) (*models.Calibration, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    for i := len(m.calibrations) - 1; i >= 0; i-- {
+        if m.calibrations[i].SessionID == sessionID {
+            c := m.calibrations[i]
+            return &c, nil
+        }
+    }
+    return nil, nil
+}
+
+func (m *MemoryStore) GetChainHead(sessionID string) (integrity.Head, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    return m.heads[sessionID], nil
+}
+
+// 세션의 시선 데이터와 페이지 변경을 체인 순서대로 조회
+func (m *MemoryStore) GetChainRecords(sessionID string) ([]integrity.Record, error) {
+    m.mu.RLock()
+    defer m.mu.RUnlock()
+
+    var records []integrity.Record
+    for _, g := range m.gaze {
+        if g.data.SessionID == sessionID {
+            records = append(records, g.record)
+        }
+    }
+    for _, p := range m.pages {
+        if p.data.SessionID == sessionID {
+            records = append(records, p.record)
+        }
+    }
+
+    sort.Slice(records, func(i, j int) bool {
+        return records[i].Seq < records[j].Seq
+    })
+    return records, nil
+}
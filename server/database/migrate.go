@@ -0,0 +1,285 @@
+package database
+
+import (
+    "context"
+    "database/sql"
+    "embed"
+    "fmt"
+    "log"
+    "path"
+    "regexp"
+    "sort"
+    "strconv"
+    "time"
+)
+
+// 번호 순서대로 적용하는 스키마 마이그레이션. 파일 이름은 NNNN_이름.up.sql / NNNN_이름.down.sql
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// 메인 서버, Consumer, 마이그레이션 CLI가 동시에 실행돼도 한 곳만 적용하도록 잡는 advisory lock 키
+const migrationLockKey = 7_240_915_001
+
+var migrationName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+    Version int
+    Name    string
+    Up      string
+    Down    string
+}
+
+// 마이그레이션별 적용 상태
+type MigrationStatus struct {
+    Version   int        `json:"version"`
+    Name      string     `json:"name"`
+    AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+func loadMigrations() ([]Migration, error) {
+    entries, err := migrationFiles.ReadDir("migrations")
+    if err != nil {
+        return nil, err
+    }
+
+    byVersion := make(map[int]*Migration)
+    for _, entry := range entries {
+        match := migrationName.FindStringSubmatch(entry.Name())
+        if match == nil {
+            return nil, fmt.Errorf("잘못된 마이그레이션 파일 이름: %s", entry.Name())
+        }
+        version, _ := strconv.Atoi(match[1])
+
+        content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+        if err != nil {
+            return nil, err
+        }
+
+        m, exists := byVersion[version]
+        if !exists {
+            m = &Migration{Version: version, Name: match[2]}
+            byVersion[version] = m
+        }
+        if m.Name != match[2] {
+            return nil, fmt.Errorf("마이그레이션 %d 이름 불일치: %s / %s", version, m.Name, match[2])
+        }
+        if match[3] == "up" {
+            m.Up = string(content)
+        } else {
+            m.Down = string(content)
+        }
+    }
+
+    migrations := make([]Migration, 0, len(byVersion))
+    for _, m := range byVersion {
+        if m.Up == "" || m.Down == "" {
+            return nil, fmt.Errorf("마이그레이션 %04d_%s 의 up/down 중 하나가 없음", m.Version, m.Name)
+        }
+        migrations = append(migrations, *m)
+    }
+    sort.Slice(migrations, func(i, j int) bool {
+        return migrations[i].Version < migrations[j].Version
+    })
+    return migrations, nil
+}
+
+// target 버전까지 적용 (0이면 최신까지). 새로 적용한 마이그레이션 수를 반환
+func (db *DB) MigrateUp(target int) (int, error) {
+    migrations, err := loadMigrations()
+    if err != nil {
+        return 0, err
+    }
+
+    applied := 0
+    err = db.withMigrationLock(func(conn *sql.Conn) error {
+        done, err := appliedVersions(conn)
+        if err != nil {
+            return err
+        }
+        for _, m := range planUp(migrations, done, target) {
+            if err := runMigration(conn, m.Up, func(tx *sql.Tx) error {
+                _, err := tx.Exec(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name)
+                return err
+            }); err != nil {
+                return fmt.Errorf("마이그레이션 %04d_%s 적용 실패: %w", m.Version, m.Name, err)
+            }
+            log.Printf("🗄️ 마이그레이션 적용: %04d_%s", m.Version, m.Name)
+            applied++
+        }
+        return nil
+    })
+    return applied, err
+}
+
+// 최근 적용한 마이그레이션부터 steps개 되돌린다. 되돌린 수를 반환
+func (db *DB) MigrateDown(steps int) (int, error) {
+    migrations, err := loadMigrations()
+    if err != nil {
+        return 0, err
+    }
+
+    reverted := 0
+    err = db.withMigrationLock(func(conn *sql.Conn) error {
+        done, err := appliedVersions(conn)
+        if err != nil {
+            return err
+        }
+        plan, err := planDown(migrations, done, steps)
+        if err != nil {
+            return err
+        }
+        for _, m := range plan {
+            if err := runMigration(conn, m.Down, func(tx *sql.Tx) error {
+                _, err := tx.Exec(`DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+                return err
+            }); err != nil {
+                return fmt.Errorf("마이그레이션 %04d_%s 되돌리기 실패: %w", m.Version, m.Name, err)
+            }
+            log.Printf("🗄️ 마이그레이션 되돌림: %04d_%s", m.Version, m.Name)
+            reverted++
+        }
+        return nil
+    })
+    return reverted, err
+}
+
+// 아직 적용하지 않은 마이그레이션을 버전 오름차순으로. target이 0보다 크면 그 버전까지만
+func planUp(migrations []Migration, done map[int]time.Time, target int) []Migration {
+    var plan []Migration
+    for _, m := range migrations {
+        if target > 0 && m.Version > target {
+            break
+        }
+        if _, ok := done[m.Version]; ok {
+            continue
+        }
+        plan = append(plan, m)
+    }
+    return plan
+}
+
+// 적용된 마이그레이션 중 최근 것부터 steps개를 버전 내림차순으로
+func planDown(migrations []Migration, done map[int]time.Time, steps int) ([]Migration, error) {
+    byVersion := make(map[int]Migration, len(migrations))
+    for _, m := range migrations {
+        byVersion[m.Version] = m
+    }
+
+    versions := make([]int, 0, len(done))
+    for version := range done {
+        versions = append(versions, version)
+    }
+    sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+    var plan []Migration
+    for _, version := range versions {
+        if len(plan) >= steps {
+            break
+        }
+        m, ok := byVersion[version]
+        if !ok {
+            return nil, fmt.Errorf("바이너리에 없는 마이그레이션 %d 은 되돌릴 수 없음", version)
+        }
+        plan = append(plan, m)
+    }
+    return plan, nil
+}
+
+// 바이너리에 포함된 마이그레이션과 DB에 적용된 마이그레이션을 합친 상태
+func (db *DB) MigrationStatus() ([]MigrationStatus, error) {
+    migrations, err := loadMigrations()
+    if err != nil {
+        return nil, err
+    }
+
+    var result []MigrationStatus
+    err = db.withMigrationLock(func(conn *sql.Conn) error {
+        done, err := appliedVersions(conn)
+        if err != nil {
+            return err
+        }
+        for _, m := range migrations {
+            status := MigrationStatus{Version: m.Version, Name: m.Name}
+            if appliedAt, ok := done[m.Version]; ok {
+                appliedAt := appliedAt
+                status.AppliedAt = &appliedAt
+                delete(done, m.Version)
+            }
+            result = append(result, status)
+        }
+        // DB에는 있지만 이 바이너리에 없는 (더 새 버전에서 적용된) 마이그레이션
+        for version, appliedAt := range done {
+            appliedAt := appliedAt
+            result = append(result, MigrationStatus{Version: version, Name: "(unknown)", AppliedAt: &appliedAt})
+        }
+        return nil
+    })
+    sort.Slice(result, func(i, j int) bool {
+        return result[i].Version < result[j].Version
+    })
+    return result, err
+}
+
+// 전용 연결에서 advisory lock을 잡고 fn을 실행. 세션 단위 잠금이라 같은 연결에서 풀어야 한다
+func (db *DB) withMigrationLock(fn func(conn *sql.Conn) error) error {
+    ctx := context.Background()
+    conn, err := db.conn.Conn(ctx)
+    if err != nil {
+        return fmt.Errorf("마이그레이션 연결 실패: %w", err)
+    }
+    defer conn.Close()
+
+    if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+        return fmt.Errorf("마이그레이션 잠금 실패: %w", err)
+    }
+    defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey)
+
+    _, err = conn.ExecContext(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version INT PRIMARY KEY,
+            name VARCHAR(200) NOT NULL,
+            applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+        )`)
+    if err != nil {
+        return fmt.Errorf("schema_migrations 생성 실패: %w", err)
+    }
+
+    return fn(conn)
+}
+
+func appliedVersions(conn *sql.Conn) (map[int]time.Time, error) {
+    rows, err := conn.QueryContext(context.Background(), `SELECT version, applied_at FROM schema_migrations`)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    done := make(map[int]time.Time)
+    for rows.Next() {
+        var version int
+        var appliedAt time.Time
+        if err := rows.Scan(&version, &appliedAt); err != nil {
+            return nil, err
+        }
+        done[version] = appliedAt
+    }
+    return done, rows.Err()
+}
+
+// 마이그레이션 SQL과 schema_migrations 기록을 한 트랜잭션으로 실행
+func runMigration(conn *sql.Conn, script string, record func(tx *sql.Tx) error) error {
+    tx, err := conn.BeginTx(context.Background(), nil)
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec(script); err != nil {
+        return err
+    }
+    if err := record(tx); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
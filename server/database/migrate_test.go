@@ -0,0 +1,132 @@
+package database
+
+import (
+    "reflect"
+    "regexp"
+    "strings"
+    "testing"
+    "time"
+)
+
+func versionsOf(migrations []Migration) []int {
+    versions := make([]int, len(migrations))
+    for i, m := range migrations {
+        versions[i] = m.Version
+    }
+    return versions
+}
+
+// 내장 마이그레이션은 1부터 빠짐없이 이어지고 버전 순서대로 읽힌다
+func TestLoadMigrationsInVersionOrder(t *testing.T) {
+    migrations, err := loadMigrations()
+    if err != nil {
+        t.Fatalf("마이그레이션 로드 실패: %v", err)
+    }
+    if len(migrations) == 0 {
+        t.Fatal("내장 마이그레이션 없음")
+    }
+    for i, m := range migrations {
+        if m.Version != i+1 {
+            t.Fatalf("%d번째 마이그레이션 버전 = %d, 기대값 %d (%v)", i, m.Version, i+1, versionsOf(migrations))
+        }
+        if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+            t.Fatalf("마이그레이션 %04d_%s 의 up/down이 비어 있음", m.Version, m.Name)
+        }
+    }
+}
+
+var (
+    createdTable = regexp.MustCompile(`CREATE TABLE IF NOT EXISTS (\w+)`)
+    addedColumn  = regexp.MustCompile(`ALTER TABLE (\w+) ADD COLUMN IF NOT EXISTS (\w+)`)
+    createdIndex = regexp.MustCompile(`CREATE INDEX IF NOT EXISTS (\w+) ON (\w+)`)
+)
+
+// down은 up이 만든 테이블·컬럼·인덱스를 모두 지운다 (지운 테이블에 딸린 것은 테이블과 함께 지워진 것으로 본다)
+func TestMigrationDownUndoesUp(t *testing.T) {
+    migrations, err := loadMigrations()
+    if err != nil {
+        t.Fatalf("마이그레이션 로드 실패: %v", err)
+    }
+
+    for _, m := range migrations {
+        dropsTable := func(table string) bool {
+            return strings.Contains(m.Down, "DROP TABLE IF EXISTS "+table+";")
+        }
+        for _, match := range createdTable.FindAllStringSubmatch(m.Up, -1) {
+            if !dropsTable(match[1]) {
+                t.Errorf("%04d_%s: down이 테이블 %s 를 지우지 않음", m.Version, m.Name, match[1])
+            }
+        }
+        for _, match := range addedColumn.FindAllStringSubmatch(m.Up, -1) {
+            dropsColumn := strings.Contains(m.Down, "ALTER TABLE "+match[1]+" DROP COLUMN IF EXISTS "+match[2]+";")
+            if !dropsColumn && !dropsTable(match[1]) {
+                t.Errorf("%04d_%s: down이 컬럼 %s.%s 를 지우지 않음", m.Version, m.Name, match[1], match[2])
+            }
+        }
+        for _, match := range createdIndex.FindAllStringSubmatch(m.Up, -1) {
+            dropsIndex := strings.Contains(m.Down, "DROP INDEX IF EXISTS "+match[1]+";")
+            if !dropsIndex && !dropsTable(match[2]) {
+                t.Errorf("%04d_%s: down이 인덱스 %s 를 지우지 않음", m.Version, m.Name, match[1])
+            }
+        }
+    }
+}
+
+func TestMigrationPlans(t *testing.T) {
+    migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}, {Version: 5}}
+    applied := func(versions ...int) map[int]time.Time {
+        done := make(map[int]time.Time)
+        for _, v := range versions {
+            done[v] = time.Now()
+        }
+        return done
+    }
+
+    upCases := []struct {
+        name   string
+        done   map[int]time.Time
+        target int
+        want   []int
+    }{
+        {"새 DB는 모두 오름차순", applied(), 0, []int{1, 2, 3, 4, 5}},
+        {"적용된 것은 건너뜀", applied(1, 2), 0, []int{3, 4, 5}},
+        {"중간에 빠진 버전도 채움", applied(1, 2, 4), 0, []int{3, 5}},
+        {"target 버전까지만", applied(1), 3, []int{2, 3}},
+        {"최신 상태", applied(1, 2, 3, 4, 5), 0, []int{}},
+    }
+    for _, c := range upCases {
+        t.Run("up "+c.name, func(t *testing.T) {
+            if got := versionsOf(planUp(migrations, c.done, c.target)); !reflect.DeepEqual(got, c.want) {
+                t.Fatalf("적용 순서 = %v, 기대값 %v", got, c.want)
+            }
+        })
+    }
+
+    downCases := []struct {
+        name  string
+        done  map[int]time.Time
+        steps int
+        want  []int
+    }{
+        {"최근 것부터 내림차순", applied(1, 2, 3, 4, 5), 2, []int{5, 4}},
+        {"적용된 것보다 많이 요청", applied(1, 2, 3), 10, []int{3, 2, 1}},
+        {"빠진 버전은 건너뜀", applied(1, 3, 5), 2, []int{5, 3}},
+        {"0단계", applied(1, 2), 0, []int{}},
+    }
+    for _, c := range downCases {
+        t.Run("down "+c.name, func(t *testing.T) {
+            plan, err := planDown(migrations, c.done, c.steps)
+            if err != nil {
+                t.Fatalf("되돌리기 계획 실패: %v", err)
+            }
+            if got := versionsOf(plan); !reflect.DeepEqual(got, c.want) {
+                t.Fatalf("되돌리기 순서 = %v, 기대값 %v", got, c.want)
+            }
+        })
+    }
+
+    // 이 바이너리보다 새 버전이 적용된 DB는 되돌리지 않는다
+    if _, err := planDown(migrations, applied(1, 2, 6), 1); err == nil {
+        t.Fatal("바이너리에 없는 마이그레이션을 되돌리려 함")
+    }
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS page_changes;
+DROP TABLE IF EXISTS gaze_data;
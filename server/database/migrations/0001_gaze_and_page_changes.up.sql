@@ -0,0 +1,17 @@
+-- 시선 데이터와 페이지 변경 이력 (기존 배포본에 이미 있을 수 있으므로 IF NOT EXISTS)
+CREATE TABLE IF NOT EXISTS gaze_data (
+    id SERIAL PRIMARY KEY,
+    x FLOAT NOT NULL,
+    y FLOAT NOT NULL,
+    timestamp BIGINT NOT NULL,
+    section_id VARCHAR(100),
+    current_page VARCHAR(100),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS page_changes (
+    id SERIAL PRIMARY KEY,
+    current_page VARCHAR(100) NOT NULL,
+    timestamp BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_page_changes_session;
+DROP INDEX IF EXISTS idx_gaze_data_session;
+ALTER TABLE page_changes DROP COLUMN IF EXISTS session_id;
+ALTER TABLE gaze_data DROP COLUMN IF EXISTS session_id;
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,16 @@
+-- 상담 세션과 시선/페이지 기록의 세션 ID
+CREATE TABLE IF NOT EXISTS sessions (
+    id VARCHAR(64) PRIMARY KEY,
+    branch_id VARCHAR(100),
+    employee_id VARCHAR(100),
+    product_id VARCHAR(100),
+    terms_version VARCHAR(50),
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMP
+);
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS terms_version VARCHAR(50);
+
+ALTER TABLE gaze_data ADD COLUMN IF NOT EXISTS session_id VARCHAR(64);
+ALTER TABLE page_changes ADD COLUMN IF NOT EXISTS session_id VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_gaze_data_session ON gaze_data (session_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_page_changes_session ON page_changes (session_id, timestamp);
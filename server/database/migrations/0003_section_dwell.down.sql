@@ -0,0 +1 @@
+DROP TABLE IF EXISTS section_dwell;
@@ -0,0 +1,10 @@
+-- 세션별 섹션 체류 시간 누적
+CREATE TABLE IF NOT EXISTS section_dwell (
+    session_id VARCHAR(64) NOT NULL,
+    page_id VARCHAR(100) NOT NULL,
+    section_id VARCHAR(100) NOT NULL,
+    dwell_ms BIGINT NOT NULL DEFAULT 0,
+    sample_count INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (session_id, page_id, section_id)
+);
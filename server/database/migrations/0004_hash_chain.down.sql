@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS chain_heads;
+ALTER TABLE page_changes DROP COLUMN IF EXISTS record_hash;
+ALTER TABLE page_changes DROP COLUMN IF EXISTS prev_hash;
+ALTER TABLE page_changes DROP COLUMN IF EXISTS chain_seq;
+ALTER TABLE gaze_data DROP COLUMN IF EXISTS record_hash;
+ALTER TABLE gaze_data DROP COLUMN IF EXISTS prev_hash;
+ALTER TABLE gaze_data DROP COLUMN IF EXISTS chain_seq;
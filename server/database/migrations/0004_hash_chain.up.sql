@@ -0,0 +1,14 @@
+-- 위변조 검증용 세션 해시 체인
+ALTER TABLE gaze_data ADD COLUMN IF NOT EXISTS chain_seq BIGINT;
+ALTER TABLE gaze_data ADD COLUMN IF NOT EXISTS prev_hash CHAR(64);
+ALTER TABLE gaze_data ADD COLUMN IF NOT EXISTS record_hash CHAR(64);
+ALTER TABLE page_changes ADD COLUMN IF NOT EXISTS chain_seq BIGINT;
+ALTER TABLE page_changes ADD COLUMN IF NOT EXISTS prev_hash CHAR(64);
+ALTER TABLE page_changes ADD COLUMN IF NOT EXISTS record_hash CHAR(64);
+
+CREATE TABLE IF NOT EXISTS chain_heads (
+    session_id VARCHAR(64) PRIMARY KEY,
+    seq BIGINT NOT NULL DEFAULT 0,
+    head_hash CHAR(64) NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS fixations;
+ALTER TABLE section_dwell DROP COLUMN IF EXISTS fixation_count;
@@ -0,0 +1,16 @@
+-- 시선 고정 (I-VT/I-DT 검출 결과)
+ALTER TABLE section_dwell ADD COLUMN IF NOT EXISTS fixation_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS fixations (
+    id SERIAL PRIMARY KEY,
+    session_id VARCHAR(64) NOT NULL,
+    page_id VARCHAR(100),
+    section_id VARCHAR(100),
+    x FLOAT NOT NULL,
+    y FLOAT NOT NULL,
+    start_ts BIGINT NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    sample_count INT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_fixations_session ON fixations (session_id, start_ts);
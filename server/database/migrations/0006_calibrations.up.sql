@@ -0,0 +1,17 @@
+-- 시선 보정 결과
+CREATE TABLE IF NOT EXISTS calibrations (
+    id SERIAL PRIMARY KEY,
+    session_id VARCHAR(64) NOT NULL,
+    points JSONB NOT NULL,
+    reported_error_px FLOAT,
+    accuracy_px FLOAT NOT NULL,
+    precision_px FLOAT NOT NULL,
+    max_error_px FLOAT NOT NULL,
+    point_count INT NOT NULL,
+    sample_count INT NOT NULL,
+    screen_width INT,
+    screen_height INT,
+    timestamp BIGINT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_calibrations_session ON calibrations (session_id, id);
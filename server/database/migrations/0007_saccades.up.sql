@@ -0,0 +1,15 @@
+-- 도약 (연속한 두 시선 고정 사이의 이동)
+CREATE TABLE IF NOT EXISTS saccades (
+    id SERIAL PRIMARY KEY,
+    session_id VARCHAR(64) NOT NULL,
+    page_id VARCHAR(100),
+    from_x FLOAT NOT NULL,
+    from_y FLOAT NOT NULL,
+    to_x FLOAT NOT NULL,
+    to_y FLOAT NOT NULL,
+    start_ts BIGINT NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    amplitude FLOAT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_saccades_session ON saccades (session_id, start_ts);
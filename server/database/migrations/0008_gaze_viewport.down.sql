@@ -0,0 +1,2 @@
+ALTER TABLE gaze_data DROP COLUMN IF EXISTS viewport_height;
+ALTER TABLE gaze_data DROP COLUMN IF EXISTS viewport_width;
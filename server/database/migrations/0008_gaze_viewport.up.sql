@@ -0,0 +1,3 @@
+-- 샘플을 잡은 시점의 클라이언트 뷰포트 (히트맵 좌표 정규화)
+ALTER TABLE gaze_data ADD COLUMN IF NOT EXISTS viewport_width INT;
+ALTER TABLE gaze_data ADD COLUMN IF NOT EXISTS viewport_height INT;
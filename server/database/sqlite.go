@@ -0,0 +1,310 @@
+package database
+
+import (
+    "database/sql"
+    "fmt"
+    "log"
+
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+
+    _ "modernc.org/sqlite"
+)
+
+// 파일 하나로 동작하는 내장 SQLite 저장소. Kafka/PostgreSQL 없이 로컬에서 돌려볼 때 쓴다.
+// 버전 마이그레이션 대신 최신 스키마를 그대로 만든다
+type SQLiteStore struct {
+    sqlStore
+}
+
+// PostgreSQL 마이그레이션을 모두 적용한 것과 같은 스키마
+var sqliteSchema = []string{
+    `CREATE TABLE IF NOT EXISTS gaze_data (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        x FLOAT NOT NULL,
+        y FLOAT NOT NULL,
+        timestamp BIGINT NOT NULL,
+        section_id VARCHAR(100),
+        current_page VARCHAR(100),
+        session_id VARCHAR(64),
+        chain_seq BIGINT,
+        prev_hash CHAR(64),
+        record_hash CHAR(64),
+        viewport_width INT,
+        viewport_height INT,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    )`,
+    `CREATE TABLE IF NOT EXISTS page_changes (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        current_page VARCHAR(100) NOT NULL,
+        timestamp BIGINT NOT NULL,
+        session_id VARCHAR(64),
+        chain_seq BIGINT,
+        prev_hash CHAR(64),
+        record_hash CHAR(64),
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    )`,
+    `CREATE INDEX IF NOT EXISTS idx_gaze_data_session ON gaze_data (session_id, timestamp)`,
+    `CREATE INDEX IF NOT EXISTS idx_page_changes_session ON page_changes (session_id, timestamp)`,
+    `CREATE TABLE IF NOT EXISTS sessions (
+        id VARCHAR(64) PRIMARY KEY,
+        branch_id VARCHAR(100),
+        employee_id VARCHAR(100),
+        product_id VARCHAR(100),
+        terms_version VARCHAR(50),
+        started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+        ended_at TIMESTAMP
+    )`,
+    `CREATE TABLE IF NOT EXISTS section_dwell (
+        session_id VARCHAR(64) NOT NULL,
+        page_id VARCHAR(100) NOT NULL,
+        section_id VARCHAR(100) NOT NULL,
+        dwell_ms BIGINT NOT NULL DEFAULT 0,
+        fixation_count INT NOT NULL DEFAULT 0,
+        sample_count INT NOT NULL DEFAULT 0,
+        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+        PRIMARY KEY (session_id, page_id, section_id)
+    )`,
+    `CREATE TABLE IF NOT EXISTS chain_heads (
+        session_id VARCHAR(64) PRIMARY KEY,
+        seq BIGINT NOT NULL DEFAULT 0,
+        head_hash CHAR(64) NOT NULL,
+        updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    )`,
+    `CREATE TABLE IF NOT EXISTS fixations (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        session_id VARCHAR(64) NOT NULL,
+        page_id VARCHAR(100),
+        section_id VARCHAR(100),
+        x FLOAT NOT NULL,
+        y FLOAT NOT NULL,
+        start_ts BIGINT NOT NULL,
+        duration_ms BIGINT NOT NULL,
+        sample_count INT NOT NULL,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    )`,
+    `CREATE INDEX IF NOT EXISTS idx_fixations_session ON fixations (session_id, start_ts)`,
+    `CREATE TABLE IF NOT EXISTS saccades (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        session_id VARCHAR(64) NOT NULL,
+        page_id VARCHAR(100),
+        from_x FLOAT NOT NULL,
+        from_y FLOAT NOT NULL,
+        to_x FLOAT NOT NULL,
+        to_y FLOAT NOT NULL,
+        start_ts BIGINT NOT NULL,
+        duration_ms BIGINT NOT NULL,
+        amplitude FLOAT NOT NULL,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    )`,
+    `CREATE INDEX IF NOT EXISTS idx_saccades_session ON saccades (session_id, start_ts)`,
+    `CREATE TABLE IF NOT EXISTS calibrations (
+        id INTEGER PRIMARY KEY AUTOINCREMENT,
+        session_id VARCHAR(64) NOT NULL,
+        points TEXT NOT NULL,
+        reported_error_px FLOAT,
+        accuracy_px FLOAT NOT NULL,
+        precision_px FLOAT NOT NULL,
+        max_error_px FLOAT NOT NULL,
+        point_count INT NOT NULL,
+        sample_count INT NOT NULL,
+        screen_width INT,
+        screen_height INT,
+        timestamp BIGINT,
+        created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    )`,
+    `CREATE INDEX IF NOT EXISTS idx_calibrations_session ON calibrations (session_id, id)`,
+}
+
+// path가 ":memory:"이면 프로세스 안에서만 유지되는 DB
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+    // 쓰기 트랜잭션을 시작할 때 바로 잠가야 체인 헤드를 읽고 갱신하는 사이에 다른 쓰기가 끼지 않는다
+    dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+    conn, err := sql.Open("sqlite", dsn)
+    if err != nil {
+        return nil, fmt.Errorf("SQLite 열기 실패: %w", err)
+    }
+    // SQLite는 쓰기가 한 번에 하나뿐이라 연결을 하나로 묶는다. ":memory:"는 연결마다 DB가 따로 생기기도 한다
+    conn.SetMaxOpenConns(1)
+
+    for _, stmt := range sqliteSchema {
+        if _, err := conn.Exec(stmt); err != nil {
+            conn.Close()
+            return nil, fmt.Errorf("SQLite 테이블 생성 실패: %w", err)
+        }
+    }
+
+    log.Printf("✅ SQLite 연결 완료: %s", path)
+    return &SQLiteStore{sqlStore{conn: conn}}, nil
+}
+
+// 헤드 행이 없으면 만든다. 트랜잭션이 시작할 때 이미 쓰기 잠금을 잡았으므로 별도 행 잠금은 필요 없다
+func sqliteChainHead(tx *sql.Tx, sessionID string) (integrity.Head, error) {
+    _, err := tx.Exec(`
+        INSERT INTO chain_heads (session_id, seq, head_hash)
+        VALUES ($1, 0, $2)
+        ON CONFLICT (session_id) DO NOTHING`,
+        sessionID, integrity.GenesisHash)
+    if err != nil {
+        return integrity.Head{}, fmt.Errorf("체인 헤드 생성 실패: %w", err)
+    }
+
+    var head integrity.Head
+    err = tx.QueryRow(`SELECT seq, head_hash FROM chain_heads WHERE session_id = $1`, sessionID).Scan(&head.Seq, &head.Hash)
+    if err != nil {
+        return integrity.Head{}, fmt.Errorf("체인 헤드 조회 실패: %w", err)
+    }
+    return head, nil
+}
+
+func sqliteAdvanceChainHead(tx *sql.Tx, sessionID string, head integrity.Head) error {
+    _, err := tx.Exec(`
+        UPDATE chain_heads SET seq = $2, head_hash = $3, updated_at = CURRENT_TIMESTAMP
+        WHERE session_id = $1`,
+        sessionID, head.Seq, head.Hash)
+    if err != nil {
+        return fmt.Errorf("체인 헤드 갱신 실패: %w", err)
+    }
+    return nil
+}
+
+// 이미 체인에 들어간 (세션, timestamp) 샘플이면 넣지 않고 false를 반환 (DB.SaveGazeBatch와 같은 기준)
+func sqliteInsertGaze(tx *sql.Tx, data models.GazeData, heads map[string]integrity.Head) (bool, error) {
+    if data.SessionID == "" {
+        return false, fmt.Errorf("세션 ID 없는 시선 데이터는 체인에 연결할 수 없음")
+    }
+
+    head, exists := heads[data.SessionID]
+    if !exists {
+        var err error
+        if head, err = sqliteChainHead(tx, data.SessionID); err != nil {
+            return false, err
+        }
+    }
+
+    // 같은 트랜잭션에서 넣은 행도 보이므로 배치 안의 중복도 걸러진다
+    var stored bool
+    err := tx.QueryRow(`
+        SELECT EXISTS (SELECT 1 FROM gaze_data WHERE session_id = $1 AND timestamp = $2)`,
+        data.SessionID, data.Timestamp).Scan(&stored)
+    if err != nil {
+        return false, fmt.Errorf("중복 시선 데이터 확인 실패: %w", err)
+    }
+    if stored {
+        heads[data.SessionID] = head
+        return false, nil
+    }
+
+    record := gazeRecord(data)
+    record.Link(head)
+    heads[data.SessionID] = integrity.Head{Seq: record.Seq, Hash: record.Hash}
+
+    _, err = tx.Exec(`
+        INSERT INTO gaze_data (x, y, timestamp, section_id, current_page, session_id, chain_seq, prev_hash, record_hash,
+            viewport_width, viewport_height)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+        data.X, data.Y, data.Timestamp, data.SectionID, data.CurrentPage, data.SessionID,
+        record.Seq, record.PrevHash, record.Hash, nullInt(record.ViewportWidth), nullInt(record.ViewportHeight))
+    return err == nil, err
+}
+
+func (s *SQLiteStore) SaveGazeData(data models.GazeData) error {
+    return s.SaveGazeBatch([]models.GazeData{data})
+}
+
+// 세션별 헤드를 트랜잭션 안에서 한 번만 읽고, 끝에서 한 번만 갱신한다
+func (s *SQLiteStore) SaveGazeBatch(samples []models.GazeData) error {
+    if len(samples) == 0 {
+        return nil
+    }
+
+    tx, err := s.conn.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    heads := make(map[string]integrity.Head)
+    skipped := 0
+    for _, data := range samples {
+        inserted, err := sqliteInsertGaze(tx, data, heads)
+        if err != nil {
+            return err
+        }
+        if !inserted {
+            skipped++
+        }
+    }
+    if skipped > 0 {
+        log.Printf("♻️ 이미 저장된 시선 샘플 %d건 건너뜀", skipped)
+    }
+    for sessionID, head := range heads {
+        if err := sqliteAdvanceChainHead(tx, sessionID, head); err != nil {
+            return err
+        }
+    }
+
+    return tx.Commit()
+}
+
+// 세션 해시 체인에 연결해서 저장
+func (s *SQLiteStore) SavePageChange(data models.PageChangeData) error {
+    if data.SessionID == "" {
+        return fmt.Errorf("세션 ID 없는 레코드는 체인에 연결할 수 없음")
+    }
+
+    tx, err := s.conn.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    head, err := sqliteChainHead(tx, data.SessionID)
+    if err != nil {
+        return err
+    }
+    record := integrity.Record{
+        Kind:        integrity.KindPage,
+        SessionID:   data.SessionID,
+        Timestamp:   data.Timestamp,
+        CurrentPage: data.CurrentPage,
+    }
+    record.Link(head)
+
+    _, err = tx.Exec(`
+        INSERT INTO page_changes (current_page, timestamp, session_id, chain_seq, prev_hash, record_hash)
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+        data.CurrentPage, data.Timestamp, data.SessionID, record.Seq, record.PrevHash, record.Hash)
+    if err != nil {
+        return err
+    }
+    if err := sqliteAdvanceChainHead(tx, data.SessionID, integrity.Head{Seq: record.Seq, Hash: record.Hash}); err != nil {
+        return err
+    }
+
+    return tx.Commit()
+}
+
+// 배열 파라미터가 없으므로 세션 조건은 IN 목록으로 만든다
+func (s *SQLiteStore) GetGazePoints(filter GazeFilter) ([]models.GazeData, error) {
+    query, args := gazePointsQuery(filter, false)
+    return s.queryGazePoints(query, args)
+}
+
+// 체인에 연결된 레코드는 남긴다 (DB.CleanOldData와 같은 기준)
+func (s *SQLiteStore) CleanOldData() (gazeRows, pageRows int64, err error) {
+    result1, err1 := s.conn.Exec("DELETE FROM gaze_data WHERE created_at < datetime('now', '-7 days') AND chain_seq IS NULL")
+    result2, err2 := s.conn.Exec("DELETE FROM page_changes WHERE created_at < datetime('now', '-7 days') AND chain_seq IS NULL")
+
+    if err1 != nil {
+        return 0, 0, err1
+    }
+    if err2 != nil {
+        return 0, 0, err2
+    }
+
+    gazeRows, _ = result1.RowsAffected()
+    pageRows, _ = result2.RowsAffected()
+
+    return gazeRows, pageRows, nil
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+    "fmt"
+    "time"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+)
+
+// 서비스와 핸들러가 쓰는 저장소. 운영은 PostgreSQL, 로컬 실행과 테스트는 SQLite 또는 메모리
+type Store interface {
+    Close() error
+    Ping() error
+
+    // 시선 데이터와 페이지 변경 (세션 해시 체인에 연결해서 저장)
+    SaveGazeData(data models.GazeData) error
+    SaveGazeBatch(samples []models.GazeData) error
+    SavePageChange(data models.PageChangeData) error
+    GetGazePoints(filter GazeFilter) ([]models.GazeData, error)
+    GetRecentGazeData(limit int, sessionID string) ([]map[string]interface{}, error)
+    GetPageChanges(sessionID string) ([]models.PageChangeData, error)
+    GetLastGazeTimestamp(sessionID string) (int64, error)
+    GetReplayEvents(sessionID string) ([]models.ReplayEvent, error)
+    ClearData() (gazeRows, pageRows int64, err error)
+    CleanOldData() (gazeRows, pageRows int64, err error)
+
+    // 상담 세션
+    CreateSession(session models.Session) error
+    EndSession(sessionID string, endedAt time.Time) error
+    GetSession(sessionID string) (*models.Session, error)
+    GetRecentSessions(limit int) ([]models.Session, error)
+
+    // 분석 결과
+    SaveSectionDwell(dwell []models.SectionDwell) error
+    GetSectionDwell(sessionID string) ([]models.SectionDwell, error)
+    SaveFixations(fixations []analysis.Fixation) error
+    GetFixations(sessionID string) ([]analysis.Fixation, error)
+    SaveSaccades(saccades []analysis.Saccade) error
+    GetSaccades(sessionID string) ([]analysis.Saccade, error)
+    SaveCalibration(c *models.Calibration) error
+    GetCalibrations(sessionID string) ([]models.Calibration, error)
+    GetLatestCalibration(sessionID string) (*models.Calibration, error)
+
+    // 위변조 검증
+    GetChainHead(sessionID string) (integrity.Head, error)
+    GetChainRecords(sessionID string) ([]integrity.Record, error)
+}
+
+var (
+    _ Store = (*DB)(nil)
+    _ Store = (*SQLiteStore)(nil)
+    _ Store = (*MemoryStore)(nil)
+)
+
+const (
+    BackendPostgres = "postgres"
+    BackendSQLite   = "sqlite"
+    BackendMemory   = "memory"
+)
+
+// STORE_BACKEND 설정에 맞는 저장소를 연다
+func NewStore(cfg *config.Config) (Store, error) {
+    switch cfg.StoreBackend {
+    case BackendPostgres, "":
+        return New(cfg)
+    case BackendSQLite:
+        return NewSQLiteStore(cfg.SQLitePath)
+    case BackendMemory:
+        return NewMemoryStore(), nil
+    default:
+        return nil, fmt.Errorf("알 수 없는 저장소 종류: %s", cfg.StoreBackend)
+    }
+}
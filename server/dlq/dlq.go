@@ -0,0 +1,108 @@
+package dlq
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// DLQ로 보낸 사유
+type Reason string
+
+const (
+    ReasonMalformed      Reason = "malformed"       // JSON 해석 실패
+    ReasonMissingSession Reason = "missing_session" // 세션 ID 없음 (체인에 넣을 수 없음)
+    ReasonStoreFailed    Reason = "store_failed"    // DB가 살아 있는데도 이 메시지만 저장 실패
+)
+
+// DLQ 메시지 본문. 원본 payload는 바이트 그대로 보존해 재처리 시 그대로 다시 보낸다
+type Entry struct {
+    Reason      Reason    `json:"reason"`
+    Error       string    `json:"error"`
+    Topic       string    `json:"topic"`
+    Partition   int       `json:"partition"`
+    Offset      int64     `json:"offset"`
+    Key         []byte    `json:"key,omitempty"`
+    Payload     []byte    `json:"payload"`
+    MessageTime time.Time `json:"messageTime"` // 원본 메시지의 Kafka 타임스탬프
+    FailedAt    time.Time `json:"failedAt"`
+}
+
+func NewEntry(m kafka.Message, reason Reason, err error) Entry {
+    entry := Entry{
+        Reason:      reason,
+        Topic:       m.Topic,
+        Partition:   m.Partition,
+        Offset:      m.Offset,
+        Key:         m.Key,
+        Payload:     m.Value,
+        MessageTime: m.Time,
+        FailedAt:    time.Now(),
+    }
+    if err != nil {
+        entry.Error = err.Error()
+    }
+    return entry
+}
+
+func Decode(m kafka.Message) (Entry, error) {
+    var entry Entry
+    if err := json.Unmarshal(m.Value, &entry); err != nil {
+        return Entry{}, fmt.Errorf("DLQ 메시지 해석 실패 (offset %d): %w", m.Offset, err)
+    }
+    return entry, nil
+}
+
+// DLQ 토픽에 쓸 메시지. Decode의 역
+func (e Entry) Message() (kafka.Message, error) {
+    value, err := json.Marshal(e)
+    if err != nil {
+        return kafka.Message{}, fmt.Errorf("DLQ 메시지 마샬링 실패: %w", err)
+    }
+    return kafka.Message{Key: e.Key, Value: value}, nil
+}
+
+// 재처리할 때 원본 토픽으로 보낼 메시지
+func (e Entry) Original() kafka.Message {
+    return kafka.Message{Key: e.Key, Value: e.Payload}
+}
+
+type Writer struct {
+    writer *kafka.Writer
+}
+
+// 유실되면 안 되므로 모든 복제본 확인 후 반환하는 동기 쓰기.
+// 원본 키(세션 ID)를 그대로 써서 같은 세션의 DLQ 메시지도 한 파티션에 순서대로 남긴다
+func NewWriter(brokers, topic string) *Writer {
+    return &Writer{
+        writer: &kafka.Writer{
+            Addr:         kafka.TCP(brokers),
+            Topic:        topic,
+            Balancer:     &kafka.Hash{},
+            RequiredAcks: kafka.RequireAll,
+        },
+    }
+}
+
+func (w *Writer) Write(ctx context.Context, entries ...Entry) error {
+    messages := make([]kafka.Message, 0, len(entries))
+    for _, e := range entries {
+        message, err := e.Message()
+        if err != nil {
+            return err
+        }
+        messages = append(messages, message)
+    }
+
+    if err := w.writer.WriteMessages(ctx, messages...); err != nil {
+        return fmt.Errorf("DLQ 전송 실패 (%d건): %w", len(messages), err)
+    }
+    return nil
+}
+
+func (w *Writer) Close() error {
+    return w.writer.Close()
+}
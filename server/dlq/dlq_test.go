@@ -0,0 +1,70 @@
+package dlq
+
+import (
+    "bytes"
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// DLQ에 쓴 메시지를 다시 읽으면 원본 키·payload가 바이트 그대로 돌아오고 원본 토픽·파티션·오프셋이 남는다
+func TestEntryRoundTrip(t *testing.T) {
+    messageTime := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+    cases := []struct {
+        name    string
+        message kafka.Message
+        reason  Reason
+        err     error
+    }{
+        {"저장 실패", kafka.Message{Topic: "gaze-data", Partition: 3, Offset: 1042, Key: []byte("session-1"),
+            Value: []byte(`[{"sessionId":"session-1","x":10,"y":20,"timestamp":1726000000000}]`), Time: messageTime},
+            ReasonStoreFailed, errors.New("duplicate key")},
+        {"해석할 수 없는 payload", kafka.Message{Topic: "gaze-data", Partition: 0, Offset: 7, Key: []byte("session-2"),
+            Value: []byte{0xff, 0xfe, '{', 0x00}, Time: messageTime},
+            ReasonMalformed, errors.New("invalid character")},
+        {"키 없는 메시지", kafka.Message{Topic: "gaze-data", Partition: 5, Offset: 0,
+            Value: []byte(`{"x":1}`), Time: messageTime},
+            ReasonMissingSession, nil},
+    }
+
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            written, err := NewEntry(c.message, c.reason, c.err).Message()
+            if err != nil {
+                t.Fatalf("DLQ 메시지 만들기 실패: %v", err)
+            }
+            if !bytes.Equal(written.Key, c.message.Key) {
+                t.Fatalf("DLQ 메시지 키 = %q, 기대값 원본 키 %q", written.Key, c.message.Key)
+            }
+
+            // DLQ 토픽에서 읽은 메시지로 재처리한다
+            written.Offset = 99
+            entry, err := Decode(written)
+            if err != nil {
+                t.Fatalf("DLQ 메시지 해석 실패: %v", err)
+            }
+            if entry.Reason != c.reason || entry.Topic != c.message.Topic ||
+                entry.Partition != c.message.Partition || entry.Offset != c.message.Offset {
+                t.Fatalf("원본 위치 = %s %s[%d]@%d, 기대값 %s %s[%d]@%d", entry.Reason, entry.Topic, entry.Partition, entry.Offset,
+                    c.reason, c.message.Topic, c.message.Partition, c.message.Offset)
+            }
+            if c.err != nil && entry.Error != c.err.Error() {
+                t.Fatalf("오류 = %q, 기대값 %q", entry.Error, c.err.Error())
+            }
+            if !entry.MessageTime.Equal(messageTime) {
+                t.Fatalf("원본 메시지 시각 = %v, 기대값 %v", entry.MessageTime, messageTime)
+            }
+
+            original := entry.Original()
+            if !bytes.Equal(original.Key, c.message.Key) || !bytes.Equal(original.Value, c.message.Value) {
+                t.Fatalf("재처리 메시지 = %q / %q, 기대값 %q / %q", original.Key, original.Value, c.message.Key, c.message.Value)
+            }
+        })
+    }
+
+    if _, err := Decode(kafka.Message{Offset: 5, Value: []byte("not json")}); err == nil {
+        t.Fatal("해석할 수 없는 DLQ 메시지가 통과함")
+    }
+}
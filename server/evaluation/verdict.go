@@ -0,0 +1,268 @@
+package evaluation
+
+import (
+    "fmt"
+    "strings"
+    "time"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/catalog"
+    "shinhan-eyetracking/server/models"
+)
+
+// 판정 기준
+type Rules struct {
+    // 낮은 우선순위 섹션 미달도 전체 불합격으로 볼지 여부 (기본: 경고만)
+    RequireLowPriority bool
+    // 섹션 합격에 필요한 최소 시선 고정 횟수 (0이면 확인하지 않음)
+    MinFixations int
+    // 높은 우선순위 섹션은 '읽기'로 분류된 방문이 한 번 이상 있어야 합격
+    RequireReading bool
+    // 세션 보정 평균 오차 허용치 (px). 초과하면 증적으로 쓸 수 없어 불합격 (0이면 확인하지 않음)
+    MaxCalibrationErrorPx float64
+}
+
+func DefaultRules() Rules {
+    return Rules{}
+}
+
+// 섹션별로 수집된 근거 자료
+type SectionEvidence struct {
+    DwellMs       int64
+    FixationCount int
+    // 방문별 읽기 패턴 분류 결과
+    Visits []analysis.VisitClassification
+}
+
+// 세션 판정 입력. 키는 페이지ID/섹션ID
+type Evidence struct {
+    Sections map[string]SectionEvidence
+    // 세션의 마지막 보정 결과 (없으면 nil)
+    Calibration *models.Calibration
+}
+
+func NewEvidence(dwell []models.SectionDwell) Evidence {
+    evidence := Evidence{Sections: make(map[string]SectionEvidence)}
+    for _, d := range dwell {
+        key := EvidenceKey(d.PageID, d.SectionID)
+        e := evidence.Sections[key]
+        e.DwellMs += d.DwellMs
+        e.FixationCount += d.FixationCount
+        evidence.Sections[key] = e
+    }
+    return evidence
+}
+
+func (e Evidence) AddVisits(visits []analysis.VisitClassification) {
+    for _, v := range visits {
+        key := EvidenceKey(v.PageID, v.SectionID)
+        s := e.Sections[key]
+        s.Visits = append(s.Visits, v)
+        e.Sections[key] = s
+    }
+}
+
+func EvidenceKey(pageID, sectionID string) string {
+    return pageID + "/" + sectionID
+}
+
+type SectionVerdict struct {
+    PageID        string                        `json:"pageId"`
+    SectionID     string                        `json:"sectionId"`
+    Name          string                        `json:"name"`
+    Priority      catalog.Priority              `json:"priority"`
+    RequiredSec   float64                       `json:"requiredSec"`
+    DwellSec      float64                       `json:"dwellSec"`
+    MissingSec    float64                       `json:"missingSec"`
+    FixationCount int                           `json:"fixationCount"`
+    Pattern       analysis.ReadingLabel         `json:"pattern,omitempty"`
+    PatternCounts map[analysis.ReadingLabel]int `json:"patternCounts,omitempty"`
+    Passed        bool                          `json:"passed"`
+    Reasons       []string                      `json:"reasons,omitempty"`
+    Codes         []string                      `json:"codes,omitempty"` // Reasons와 같은 순서의 사유 코드
+}
+
+// 판정 사유 코드. 한글 문구를 표시할 수 없는 곳(폰트 없는 PDF)에서 문구 대신 쓴다
+const (
+    CodeTermsVersion       = "terms_version_mismatch"
+    CodeCalibrationError   = "calibration_error"
+    CodeCalibrationMissing = "calibration_missing"
+    CodeSectionUnmet       = "section_unmet"
+    CodeDwellShort         = "dwell_short"
+    CodeFewFixations       = "few_fixations"
+    CodeReadingNotObserved = "reading_not_observed"
+)
+
+// Verdict.Reasons 한 줄에 대응하는 사유 코드와 대상 섹션
+type Finding struct {
+    Code      string   `json:"code"`
+    Warning   bool     `json:"warning,omitempty"`
+    PageID    string   `json:"pageId,omitempty"`
+    SectionID string   `json:"sectionId,omitempty"`
+    Checks    []string `json:"checks,omitempty"` // 섹션 미충족이면 섹션의 세부 사유 코드
+}
+
+// 보정 품질 확인 결과
+type CalibrationCheck struct {
+    Present     bool    `json:"present"`
+    AccuracyPx  float64 `json:"accuracyPx,omitempty"`
+    PrecisionPx float64 `json:"precisionPx,omitempty"`
+    LimitPx     float64 `json:"limitPx,omitempty"`
+    Flagged     bool    `json:"flagged"`
+}
+
+type Verdict struct {
+    SessionID    string           `json:"sessionId"`
+    ProductID    string           `json:"productId"`
+    TermsVersion string           `json:"termsVersion"`
+    Passed       bool             `json:"passed"`
+    Calibration  CalibrationCheck `json:"calibration"`
+    Sections     []SectionVerdict `json:"sections"`
+    Reasons      []string         `json:"reasons,omitempty"`
+    Findings     []Finding        `json:"findings,omitempty"` // Reasons와 같은 순서
+    EvaluatedAt  time.Time        `json:"evaluatedAt"`
+}
+
+func (v *Verdict) addReason(finding Finding, reason string) {
+    v.Reasons = append(v.Reasons, reason)
+    v.Findings = append(v.Findings, finding)
+}
+
+// 세션이 상품의 필수 열람 기준을 충족했는지 판정
+func Evaluate(session models.Session, terms *catalog.Catalog, evidence Evidence, rules Rules) (*Verdict, error) {
+    if _, ok := terms.Product(session.ProductID); !ok {
+        return nil, fmt.Errorf("카탈로그에 없는 상품: %q", session.ProductID)
+    }
+
+    verdict := &Verdict{
+        SessionID:    session.ID,
+        ProductID:    session.ProductID,
+        TermsVersion: terms.Version,
+        Passed:       true,
+        EvaluatedAt:  time.Now(),
+    }
+
+    if session.TermsVersion != "" && session.TermsVersion != terms.Version {
+        verdict.Passed = false
+        verdict.addReason(Finding{Code: CodeTermsVersion},
+            fmt.Sprintf("세션 약관 버전(%s)과 판정 기준 버전(%s)이 다름", session.TermsVersion, terms.Version))
+    }
+
+    verdict.Calibration = checkCalibration(evidence.Calibration, rules)
+    if verdict.Calibration.Flagged {
+        verdict.Passed = false
+        verdict.addReason(Finding{Code: CodeCalibrationError},
+            fmt.Sprintf("시선 보정 오차 %.1fpx가 허용치 %.1fpx 초과", verdict.Calibration.AccuracyPx, verdict.Calibration.LimitPx))
+    } else if !verdict.Calibration.Present && rules.MaxCalibrationErrorPx > 0 {
+        verdict.addReason(Finding{Code: CodeCalibrationMissing, Warning: true}, "[경고] 시선 보정 기록 없음")
+    }
+
+    for _, section := range terms.ProductSections(session.ProductID) {
+        sv := evaluateSection(section, evidence.Sections[EvidenceKey(section.PageID, section.ID)], rules)
+        verdict.Sections = append(verdict.Sections, sv)
+
+        if sv.Passed {
+            continue
+        }
+        finding := Finding{Code: CodeSectionUnmet, PageID: section.PageID, SectionID: section.ID, Checks: sv.Codes}
+        if section.Priority == catalog.PriorityLow && !rules.RequireLowPriority {
+            finding.Warning = true
+            verdict.addReason(finding, fmt.Sprintf("[경고] %s 미충족 (낮은 우선순위)", section.Name))
+            continue
+        }
+        verdict.Passed = false
+        verdict.addReason(finding, fmt.Sprintf("%s 미충족: %s", section.Name, strings.Join(sv.Reasons, ", ")))
+    }
+
+    return verdict, nil
+}
+
+func evaluateSection(section catalog.Section, evidence SectionEvidence, rules Rules) SectionVerdict {
+    requiredMs := int64(section.Required) * 1000
+    missingMs := requiredMs - evidence.DwellMs
+    if missingMs < 0 {
+        missingMs = 0
+    }
+
+    sv := SectionVerdict{
+        PageID:        section.PageID,
+        SectionID:     section.ID,
+        Name:          section.Name,
+        Priority:      section.Priority,
+        RequiredSec:   msToSec(requiredMs),
+        DwellSec:      msToSec(evidence.DwellMs),
+        MissingSec:    msToSec(missingMs),
+        FixationCount: evidence.FixationCount,
+        Passed:        true,
+    }
+    sv.Pattern, sv.PatternCounts = readingPattern(evidence.Visits)
+
+    if missingMs > 0 {
+        sv.Passed = false
+        sv.addReason(CodeDwellShort,
+            fmt.Sprintf("체류 시간 %.1f초 / 필요 %.1f초", sv.DwellSec, sv.RequiredSec))
+    }
+    if rules.MinFixations > 0 && evidence.FixationCount < rules.MinFixations {
+        sv.Passed = false
+        sv.addReason(CodeFewFixations,
+            fmt.Sprintf("시선 고정 %d회 / 필요 %d회", evidence.FixationCount, rules.MinFixations))
+    }
+
+    if section.Priority == catalog.PriorityHigh && sv.Pattern != analysis.LabelReading {
+        reason := fmt.Sprintf("읽기 패턴 미확인 (%s)", patternName(sv.Pattern))
+        if rules.RequireReading {
+            sv.Passed = false
+            sv.addReason(CodeReadingNotObserved, reason)
+        } else {
+            sv.addReason(CodeReadingNotObserved, "[경고] "+reason)
+        }
+    }
+
+    return sv
+}
+
+func (sv *SectionVerdict) addReason(code, reason string) {
+    sv.Reasons = append(sv.Reasons, reason)
+    sv.Codes = append(sv.Codes, code)
+}
+
+func checkCalibration(c *models.Calibration, rules Rules) CalibrationCheck {
+    check := CalibrationCheck{LimitPx: rules.MaxCalibrationErrorPx}
+    if c == nil {
+        return check
+    }
+    check.Present = true
+    check.AccuracyPx = c.AccuracyPx
+    check.PrecisionPx = c.PrecisionPx
+    check.Flagged = rules.MaxCalibrationErrorPx > 0 && c.AccuracyPx > rules.MaxCalibrationErrorPx
+    return check
+}
+
+// 한 번이라도 읽었으면 reading, 아니면 skimming, 응시만 했으면 staring
+func readingPattern(visits []analysis.VisitClassification) (analysis.ReadingLabel, map[analysis.ReadingLabel]int) {
+    if len(visits) == 0 {
+        return "", nil
+    }
+
+    counts := make(map[analysis.ReadingLabel]int)
+    for _, v := range visits {
+        counts[v.Label]++
+    }
+    for _, label := range []analysis.ReadingLabel{analysis.LabelReading, analysis.LabelSkimming, analysis.LabelStaring} {
+        if counts[label] > 0 {
+            return label, counts
+        }
+    }
+    return "", counts
+}
+
+func patternName(label analysis.ReadingLabel) string {
+    if label == "" {
+        return "방문 없음"
+    }
+    return string(label)
+}
+
+func msToSec(ms int64) float64 {
+    return float64(ms) / 1000
+}
@@ -0,0 +1,309 @@
+package evaluation
+
+import (
+    "fmt"
+    "testing"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/catalog"
+    "shinhan-eyetracking/server/models"
+)
+
+// 우선순위별 섹션 하나씩 있는 상품
+const testTerms = `{
+    "version": "v1",
+    "products": [{"id": "fund", "name": "펀드", "pages": ["join"]}],
+    "pages": [{
+        "id": "join",
+        "title": "가입",
+        "sections": [
+            {"id": "risk", "name": "투자 위험", "required": 10, "priority": "high", "items": ["원금 손실", "환율 변동"]},
+            {"id": "fee", "name": "수수료", "required": 5, "priority": "medium"},
+            {"id": "etc", "name": "기타 안내", "required": 2, "priority": "low"}
+        ]
+    }]
+}`
+
+func loadTestTerms(t *testing.T) *catalog.Catalog {
+    t.Helper()
+
+    terms, err := catalog.Parse([]byte(testTerms))
+    if err != nil {
+        t.Fatalf("카탈로그 파싱 실패: %v", err)
+    }
+    return terms
+}
+
+// 모든 섹션의 필요 시간을 채우고 투자 위험 섹션을 읽은 세션
+func passingEvidence() Evidence {
+    evidence := NewEvidence([]models.SectionDwell{
+        {PageID: "join", SectionID: "risk", DwellMs: 6000, FixationCount: 20},
+        {PageID: "join", SectionID: "risk", DwellMs: 4000, FixationCount: 10},
+        {PageID: "join", SectionID: "fee", DwellMs: 5000, FixationCount: 8},
+        {PageID: "join", SectionID: "etc", DwellMs: 2500, FixationCount: 3},
+    })
+    evidence.AddVisits([]analysis.VisitClassification{
+        {PageID: "join", SectionID: "risk", Label: analysis.LabelSkimming},
+        {PageID: "join", SectionID: "risk", Label: analysis.LabelReading},
+    })
+    return evidence
+}
+
+func setSection(evidence Evidence, sectionID string, modify func(e *SectionEvidence)) Evidence {
+    key := EvidenceKey("join", sectionID)
+    e := evidence.Sections[key]
+    modify(&e)
+    evidence.Sections[key] = e
+    return evidence
+}
+
+// 판정 사유를 "코드 페이지/섹션 (경고)" 형태로
+func findingStrings(findings []Finding) []string {
+    var result []string
+    for _, f := range findings {
+        s := f.Code
+        if f.SectionID != "" {
+            s += " " + EvidenceKey(f.PageID, f.SectionID)
+        }
+        if f.Warning {
+            s += " (경고)"
+        }
+        result = append(result, s)
+    }
+    return result
+}
+
+func TestEvaluate(t *testing.T) {
+    terms := loadTestTerms(t)
+    session := models.Session{ID: "s1", ProductID: "fund", TermsVersion: "v1"}
+
+    cases := []struct {
+        name     string
+        session  models.Session
+        evidence Evidence
+        rules    Rules
+        passed   bool
+        findings []string
+        // 섹션별 세부 사유 코드 (지정한 섹션만 확인)
+        sectionCodes map[string][]string
+    }{
+        {
+            name:     "모든 기준 충족",
+            evidence: passingEvidence(),
+            passed:   true,
+            sectionCodes: map[string][]string{
+                "risk": nil, "fee": nil, "etc": nil,
+            },
+        },
+        {
+            name: "높은 우선순위 체류 시간 1ms 부족",
+            evidence: setSection(passingEvidence(), "risk", func(e *SectionEvidence) {
+                e.DwellMs = 9999
+            }),
+            findings:     []string{"section_unmet join/risk"},
+            sectionCodes: map[string][]string{"risk": {CodeDwellShort}},
+        },
+        {
+            name: "중간 우선순위 체류 시간 부족",
+            evidence: setSection(passingEvidence(), "fee", func(e *SectionEvidence) {
+                e.DwellMs = 0
+            }),
+            findings:     []string{"section_unmet join/fee"},
+            sectionCodes: map[string][]string{"fee": {CodeDwellShort}},
+        },
+        {
+            name: "낮은 우선순위 미충족은 경고",
+            evidence: setSection(passingEvidence(), "etc", func(e *SectionEvidence) {
+                e.DwellMs = 1000
+            }),
+            passed:       true,
+            findings:     []string{"section_unmet join/etc (경고)"},
+            sectionCodes: map[string][]string{"etc": {CodeDwellShort}},
+        },
+        {
+            name: "낮은 우선순위까지 요구",
+            evidence: setSection(passingEvidence(), "etc", func(e *SectionEvidence) {
+                e.DwellMs = 1000
+            }),
+            rules:    Rules{RequireLowPriority: true},
+            findings: []string{"section_unmet join/etc"},
+        },
+        {
+            name: "시선 고정 횟수 부족",
+            evidence: setSection(passingEvidence(), "fee", func(e *SectionEvidence) {
+                e.FixationCount = 2
+            }),
+            rules:        Rules{MinFixations: 3},
+            findings:     []string{"section_unmet join/fee"},
+            sectionCodes: map[string][]string{"fee": {CodeFewFixations}, "etc": nil},
+        },
+        {
+            name: "읽기 미확인은 섹션 경고만",
+            evidence: setSection(passingEvidence(), "risk", func(e *SectionEvidence) {
+                e.Visits = []analysis.VisitClassification{{Label: analysis.LabelSkimming}}
+            }),
+            passed:       true,
+            sectionCodes: map[string][]string{"risk": {CodeReadingNotObserved}},
+        },
+        {
+            name: "읽기 요구",
+            evidence: setSection(passingEvidence(), "risk", func(e *SectionEvidence) {
+                e.Visits = []analysis.VisitClassification{{Label: analysis.LabelStaring}}
+            }),
+            rules:        Rules{RequireReading: true},
+            findings:     []string{"section_unmet join/risk"},
+            sectionCodes: map[string][]string{"risk": {CodeReadingNotObserved}},
+        },
+        {
+            name: "방문 없는 높은 우선순위 섹션",
+            evidence: setSection(passingEvidence(), "risk", func(e *SectionEvidence) {
+                e.Visits = nil
+            }),
+            rules:        Rules{RequireReading: true},
+            findings:     []string{"section_unmet join/risk"},
+            sectionCodes: map[string][]string{"risk": {CodeReadingNotObserved}},
+        },
+        {
+            name:     "약관 버전 불일치",
+            session:  models.Session{ID: "s1", ProductID: "fund", TermsVersion: "v0"},
+            evidence: passingEvidence(),
+            findings: []string{"terms_version_mismatch"},
+        },
+        {
+            name: "보정 오차 초과",
+            evidence: func() Evidence {
+                e := passingEvidence()
+                e.Calibration = &models.Calibration{AccuracyPx: 50.5}
+                return e
+            }(),
+            rules:    Rules{MaxCalibrationErrorPx: 50},
+            findings: []string{"calibration_error"},
+        },
+        {
+            name: "보정 오차 허용치 이내",
+            evidence: func() Evidence {
+                e := passingEvidence()
+                e.Calibration = &models.Calibration{AccuracyPx: 50}
+                return e
+            }(),
+            rules:  Rules{MaxCalibrationErrorPx: 50},
+            passed: true,
+        },
+        {
+            name:     "보정 기록 없음은 경고",
+            evidence: passingEvidence(),
+            rules:    Rules{MaxCalibrationErrorPx: 50},
+            passed:   true,
+            findings: []string{"calibration_missing (경고)"},
+        },
+        {
+            name:    "여러 사유는 판정 순서대로",
+            session: models.Session{ID: "s1", ProductID: "fund", TermsVersion: "v0"},
+            evidence: func() Evidence {
+                e := NewEvidence(nil)
+                e.Calibration = &models.Calibration{AccuracyPx: 80}
+                return e
+            }(),
+            rules: Rules{MaxCalibrationErrorPx: 50},
+            findings: []string{
+                "terms_version_mismatch",
+                "calibration_error",
+                "section_unmet join/risk",
+                "section_unmet join/fee",
+                "section_unmet join/etc (경고)",
+            },
+            sectionCodes: map[string][]string{"risk": {CodeDwellShort, CodeReadingNotObserved}},
+        },
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            s := session
+            if tc.session.ID != "" {
+                s = tc.session
+            }
+            verdict, err := Evaluate(s, terms, tc.evidence, tc.rules)
+            if err != nil {
+                t.Fatalf("판정 실패: %v", err)
+            }
+
+            if verdict.Passed != tc.passed {
+                t.Fatalf("Passed = %v, 기대값 %v: %v", verdict.Passed, tc.passed, verdict.Reasons)
+            }
+            if got := findingStrings(verdict.Findings); fmt.Sprint(got) != fmt.Sprint(tc.findings) {
+                t.Fatalf("판정 사유 %v, 기대값 %v", got, tc.findings)
+            }
+            if len(verdict.Reasons) != len(verdict.Findings) {
+                t.Fatalf("사유 문구 %d개, 사유 코드 %d개", len(verdict.Reasons), len(verdict.Findings))
+            }
+            if len(verdict.Sections) != 3 {
+                t.Fatalf("섹션 판정 %d개", len(verdict.Sections))
+            }
+            for _, sv := range verdict.Sections {
+                want, ok := tc.sectionCodes[sv.SectionID]
+                if !ok {
+                    continue
+                }
+                if fmt.Sprint(sv.Codes) != fmt.Sprint(want) {
+                    t.Fatalf("%s 섹션 사유 %v, 기대값 %v", sv.SectionID, sv.Codes, want)
+                }
+                if len(sv.Reasons) != len(sv.Codes) {
+                    t.Fatalf("%s 섹션 사유 문구 %d개, 사유 코드 %d개", sv.SectionID, len(sv.Reasons), len(sv.Codes))
+                }
+            }
+        })
+    }
+}
+
+func TestEvaluateSectionAmounts(t *testing.T) {
+    terms := loadTestTerms(t)
+    evidence := setSection(passingEvidence(), "risk", func(e *SectionEvidence) {
+        e.DwellMs = 7250
+    })
+
+    verdict, err := Evaluate(models.Session{ID: "s1", ProductID: "fund"}, terms, evidence, DefaultRules())
+    if err != nil {
+        t.Fatalf("판정 실패: %v", err)
+    }
+
+    risk := verdict.Sections[0]
+    if risk.SectionID != "risk" || risk.RequiredSec != 10 || risk.DwellSec != 7.25 || risk.MissingSec != 2.75 {
+        t.Fatalf("체류 시간 계산 이상: %+v", risk)
+    }
+    if risk.FixationCount != 30 || risk.Pattern != analysis.LabelReading ||
+        risk.PatternCounts[analysis.LabelReading] != 1 || risk.PatternCounts[analysis.LabelSkimming] != 1 {
+        t.Fatalf("근거 자료 집계 이상: %+v", risk)
+    }
+    // 필요 시간보다 오래 본 섹션은 부족분 0
+    if etc := verdict.Sections[2]; etc.MissingSec != 0 || !etc.Passed {
+        t.Fatalf("초과 체류 섹션 판정 이상: %+v", etc)
+    }
+}
+
+func TestEvaluateUnknownProduct(t *testing.T) {
+    if _, err := Evaluate(models.Session{ID: "s1", ProductID: "없는-상품"}, loadTestTerms(t), NewEvidence(nil), DefaultRules()); err == nil {
+        t.Fatal("카탈로그에 없는 상품을 판정함")
+    }
+}
+
+func TestReadingPattern(t *testing.T) {
+    cases := []struct {
+        labels []analysis.ReadingLabel
+        want   analysis.ReadingLabel
+    }{
+        {nil, ""},
+        {[]analysis.ReadingLabel{analysis.LabelStaring}, analysis.LabelStaring},
+        {[]analysis.ReadingLabel{analysis.LabelStaring, analysis.LabelSkimming}, analysis.LabelSkimming},
+        {[]analysis.ReadingLabel{analysis.LabelSkimming, analysis.LabelReading, analysis.LabelStaring}, analysis.LabelReading},
+    }
+
+    for _, tc := range cases {
+        var visits []analysis.VisitClassification
+        for _, label := range tc.labels {
+            visits = append(visits, analysis.VisitClassification{Label: label})
+        }
+        if got, _ := readingPattern(visits); got != tc.want {
+            t.Errorf("readingPattern(%v) = %q, 기대값 %q", tc.labels, got, tc.want)
+        }
+    }
+}
@@ -1,31 +1,67 @@
 package handlers
 
 import (
+    "bytes"
     "encoding/json"
+    "fmt"
+    "image/png"
     "log"
     "net/http"
+    "net/url"
+    "strconv"
+    "strings"
     "time"
 
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/catalog"
     "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+    "shinhan-eyetracking/server/report"
     "shinhan-eyetracking/server/services"
+    "shinhan-eyetracking/server/validation"
 )
 
 type APIHandler struct {
-    db               *database.DB
-    gazeService      *services.GazeService
-    websocketService *services.WebSocketService
+    db                 database.Store
+    catalog            *catalog.Catalog
+    gazeService        *services.GazeService
+    verdictService     *services.VerdictService
+    calibrationService *services.CalibrationService
+    reportService      *services.ReportService
+    websocketService   *services.WebSocketService
+    gazeValidator      *validation.GazeValidator
+    reportFont         string
 }
 
-func NewAPIHandler(db *database.DB, gazeService *services.GazeService, websocketService *services.WebSocketService) *APIHandler {
+func NewAPIHandler(db database.Store, terms *catalog.Catalog, gazeService *services.GazeService, verdictService *services.VerdictService, calibrationService *services.CalibrationService, reportService *services.ReportService, websocketService *services.WebSocketService, gazeValidator *validation.GazeValidator, reportFont string) *APIHandler {
     return &APIHandler{
-        db:               db,
-        gazeService:      gazeService,
-        websocketService: websocketService,
+        db:                 db,
+        catalog:            terms,
+        gazeService:        gazeService,
+        verdictService:     verdictService,
+        calibrationService: calibrationService,
+        reportService:      reportService,
+        websocketService:   websocketService,
+        gazeValidator:      gazeValidator,
+        reportFont:         reportFont,
+    }
+}
+
+// 역할 토큰(Authorization: Bearer)을 보내야 호출할 수 있는 API
+func RequireRole(auth *services.AuthService, next http.HandlerFunc, roles ...models.Role) http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        if _, err := auth.AuthenticateRequest(r, roles...); err != nil {
+            log.Printf("🚫 API 인증 실패 [%s %s]: %v", r.RemoteAddr, r.URL.Path, err)
+            http.Error(w, "인증 실패", http.StatusUnauthorized)
+            return
+        }
+        next(w, r)
     }
 }
 
 func (h *APIHandler) DataHandler(w http.ResponseWriter, r *http.Request) {
-    results, err := h.db.GetRecentGazeData(100)
+    results, err := h.db.GetRecentGazeData(100, r.URL.Query().Get("session_id"))
     if err != nil {
         http.Error(w, err.Error(), http.StatusInternalServerError)
         return
@@ -38,16 +74,343 @@ func (h *APIHandler) DataHandler(w http.ResponseWriter, r *http.Request) {
     })
 }
 
-func (h *APIHandler) PageStatusHandler(w http.ResponseWriter, r *http.Request) {
+// id 파라미터가 있으면 단일 세션, 없으면 최근 세션 목록
+func (h *APIHandler) SessionsHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+
+    if id := r.URL.Query().Get("id"); id != "" {
+        session, err := h.db.GetSession(id)
+        if err != nil {
+            http.Error(w, err.Error(), http.StatusInternalServerError)
+            return
+        }
+        if session == nil {
+            http.Error(w, "세션을 찾을 수 없습니다", http.StatusNotFound)
+            return
+        }
+        json.NewEncoder(w).Encode(session)
+        return
+    }
+
+    sessions, err := h.db.GetRecentSessions(50)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "count":    len(sessions),
+        "sessions": sessions,
+    })
+}
+
+// 세션의 섹션별 체류 시간 (서버 계산값)
+func (h *APIHandler) SessionDwellHandler(w http.ResponseWriter, r *http.Request) {
+    sessionID := r.URL.Query().Get("session_id")
+    if sessionID == "" {
+        http.Error(w, "session_id 파라미터가 필요합니다", http.StatusBadRequest)
+        return
+    }
+
+    dwell, err := h.gazeService.GetSectionDwell(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "session_id": sessionID,
+        "sections":   dwell,
+    })
+}
+
+// 세션에서 검출된 시선 고정과 도약 목록
+func (h *APIHandler) SessionFixationsHandler(w http.ResponseWriter, r *http.Request) {
+    sessionID := r.URL.Query().Get("session_id")
+    if sessionID == "" {
+        http.Error(w, "session_id 파라미터가 필요합니다", http.StatusBadRequest)
+        return
+    }
+
+    fixations, err := h.gazeService.GetFixations(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    saccades, err := h.gazeService.GetSaccades(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
     w.Header().Set("Content-Type", "application/json")
     json.NewEncoder(w).Encode(map[string]interface{}{
-        "current_page": h.gazeService.GetCurrentPage(),
-        "clients":      h.websocketService.GetClientCount(),
-        "timestamp":    time.Now().Unix(),
+        "session_id":    sessionID,
+        "count":         len(fixations),
+        "fixations":     fixations,
+        "saccade_count": len(saccades),
+        "saccades":      saccades,
     })
 }
 
+// 세션의 보정 이력과 마지막 보정 품질
+func (h *APIHandler) SessionCalibrationHandler(w http.ResponseWriter, r *http.Request) {
+    sessionID := r.URL.Query().Get("session_id")
+    if sessionID == "" {
+        http.Error(w, "session_id 파라미터가 필요합니다", http.StatusBadRequest)
+        return
+    }
+
+    calibrations, err := h.calibrationService.GetCalibrations(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    var latest *models.Calibration
+    if len(calibrations) > 0 {
+        latest = &calibrations[len(calibrations)-1]
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "session_id":   sessionID,
+        "limit_px":     h.calibrationService.LimitPx(),
+        "latest":       latest,
+        "count":        len(calibrations),
+        "calibrations": calibrations,
+    })
+}
+
+// 섹션 방문별 읽기/훑어보기/응시 분류
+func (h *APIHandler) SessionReadingHandler(w http.ResponseWriter, r *http.Request) {
+    sessionID := r.URL.Query().Get("session_id")
+    if sessionID == "" {
+        http.Error(w, "session_id 파라미터가 필요합니다", http.StatusBadRequest)
+        return
+    }
+
+    visits, err := h.verdictService.ClassifyReading(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{
+        "session_id": sessionID,
+        "visits":     visits,
+    })
+}
+
+// 세션의 필수 열람 기준 충족 여부 판정
+func (h *APIHandler) SessionVerdictHandler(w http.ResponseWriter, r *http.Request) {
+    sessionID := r.URL.Query().Get("session_id")
+    if sessionID == "" {
+        http.Error(w, "session_id 파라미터가 필요합니다", http.StatusBadRequest)
+        return
+    }
+
+    verdict, err := h.verdictService.EvaluateSession(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(verdict)
+}
+
+// 세션 해시 체인 검증 (변조·순서 변경·누락 검출)
+func (h *APIHandler) SessionVerifyHandler(w http.ResponseWriter, r *http.Request) {
+    sessionID := r.URL.Query().Get("session_id")
+    if sessionID == "" {
+        http.Error(w, "session_id 파라미터가 필요합니다", http.StatusBadRequest)
+        return
+    }
+
+    // 없는 세션은 404. 있는 세션의 기록이 모두 사라졌으면 검증 실패(체인 없음)로 보고한다
+    session, err := h.db.GetSession(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    if session == nil {
+        http.Error(w, "세션을 찾을 수 없습니다", http.StatusNotFound)
+        return
+    }
+
+    head, err := h.db.GetChainHead(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+    records, err := h.db.GetChainRecords(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    report := integrity.Verify(sessionID, records, head)
+    if !report.Valid {
+        log.Printf("🚨 해시 체인 검증 실패 [%s]: 문제 %d건", sessionID, len(report.Issues))
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(report)
+}
+
+// 상담별 증적 보고서. format=pdf 이면 PDF, 그 외에는 JSON
+func (h *APIHandler) SessionReportHandler(w http.ResponseWriter, r *http.Request) {
+    sessionID := r.URL.Query().Get("session_id")
+    if sessionID == "" {
+        http.Error(w, "session_id 파라미터가 필요합니다", http.StatusBadRequest)
+        return
+    }
+
+    rep, err := h.reportService.BuildReport(sessionID)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    switch r.URL.Query().Get("format") {
+    case "pdf":
+        var buf bytes.Buffer
+        if err := report.RenderPDF(rep, &buf, h.reportFont); err != nil {
+            log.Printf("❌ PDF 보고서 생성 실패 [%s]: %v", sessionID, err)
+            http.Error(w, "PDF 생성 실패", http.StatusInternalServerError)
+            return
+        }
+        w.Header().Set("Content-Type", "application/pdf")
+        w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="report-%s.pdf"`, sessionID))
+        w.Write(buf.Bytes())
+    default:
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(rep)
+    }
+
+    log.Printf("📑 증적 보고서 생성: %s", sessionID)
+}
+
+// 페이지별 시선 히트맵 PNG.
+// page(필수), session_id(쉼표로 여러 개), from/to(ms), width/height/sigma(px)
+func (h *APIHandler) HeatmapHandler(w http.ResponseWriter, r *http.Request) {
+    query := r.URL.Query()
+
+    pageID := query.Get("page")
+    if err := h.catalog.ValidatePage(pageID); err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    filter, opts, err := parseHeatmapQuery(pageID, query)
+    if err != nil {
+        http.Error(w, "잘못된 파라미터: "+err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    points, err := h.db.GetGazePoints(filter)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusInternalServerError)
+        return
+    }
+
+    img, err := analysis.RenderHeatmap(points, opts)
+    if err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+
+    w.Header().Set("Content-Type", "image/png")
+    w.Header().Set("X-Gaze-Points", strconv.Itoa(len(points)))
+    if err := png.Encode(w, img); err != nil {
+        log.Printf("❌ 히트맵 인코딩 실패: %v", err)
+    }
+}
+
+func parseHeatmapQuery(pageID string, query url.Values) (database.GazeFilter, analysis.HeatmapOptions, error) {
+    filter := database.GazeFilter{PageID: pageID, Limit: 500000}
+    opts := analysis.DefaultHeatmapOptions()
+
+    if sessions := query.Get("session_id"); sessions != "" {
+        for _, id := range strings.Split(sessions, ",") {
+            if id = strings.TrimSpace(id); id != "" {
+                filter.SessionIDs = append(filter.SessionIDs, id)
+            }
+        }
+    }
+
+    var err error
+    if filter.From, err = int64Param(query.Get("from"), 0); err != nil {
+        return filter, opts, err
+    }
+    if filter.To, err = int64Param(query.Get("to"), 0); err != nil {
+        return filter, opts, err
+    }
+
+    width, err := int64Param(query.Get("width"), int64(opts.Width))
+    if err != nil {
+        return filter, opts, err
+    }
+    height, err := int64Param(query.Get("height"), int64(opts.Height))
+    if err != nil {
+        return filter, opts, err
+    }
+    opts.Width, opts.Height = int(width), int(height)
+
+    if sigma := query.Get("sigma"); sigma != "" {
+        if opts.Sigma, err = strconv.ParseFloat(sigma, 64); err != nil {
+            return filter, opts, err
+        }
+    }
+
+    // DB 조회 전에 크기·반경을 확인한다
+    return filter, opts, opts.Validate()
+}
+
+func int64Param(value string, defaultValue int64) (int64, error) {
+    if value == "" {
+        return defaultValue, nil
+    }
+    return strconv.ParseInt(value, 10, 64)
+}
+
+// 서버가 사용 중인 약관 카탈로그
+func (h *APIHandler) CatalogHandler(w http.ResponseWriter, r *http.Request) {
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(h.catalog)
+}
+
+// 진행 중인 세션 ID와 현재 페이지가 들어 있어 라우트에서 직원·감독자 토큰을 요구한다
+func (h *APIHandler) PageStatusHandler(w http.ResponseWriter, r *http.Request) {
+    status := map[string]interface{}{
+        "current_pages": h.gazeService.GetCurrentPages(),
+        "clients":       h.websocketService.GetClientCount(),
+        "rejected":      h.gazeValidator.Totals(),
+        "publisher":     h.gazeService.PublisherStats(),
+        "timestamp":     time.Now().Unix(),
+    }
+    if sessionID := r.URL.Query().Get("session_id"); sessionID != "" {
+        status["session_id"] = sessionID
+        status["current_page"] = h.gazeService.GetCurrentPage(sessionID)
+        status["room_clients"] = h.websocketService.GetRoomCount(sessionID)
+        status["session_rejected"] = h.gazeValidator.Rejected(sessionID)
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(status)
+}
+
+// 세션 체인에 연결되지 않은 시선·페이지 데이터 삭제 (증적은 남는다). 라우트에서 감독자 토큰을 요구한다
 func (h *APIHandler) ClearDataHandler(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+        w.Header().Set("Allow", "POST, DELETE")
+        http.Error(w, "POST 또는 DELETE만 허용", http.StatusMethodNotAllowed)
+        return
+    }
+
     gazeRows, pageRows, err := h.db.ClearData()
     if err != nil {
         http.Error(w, "데이터 삭제 실패", http.StatusInternalServerError)
@@ -61,5 +424,5 @@ func (h *APIHandler) ClearDataHandler(w http.ResponseWriter, r *http.Request) {
         "deleted_page_rows": pageRows,
     })
 
-    log.Printf("🗑️ 삭제 완료: 시선 데이터 %d개, 페이지 변경 %d개", gazeRows, pageRows)
-}
\ No newline at end of file
+    log.Printf("🗑️ 삭제 완료 [%s]: 시선 데이터 %d개, 페이지 변경 %d개", r.RemoteAddr, gazeRows, pageRows)
+}
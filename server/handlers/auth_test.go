@@ -0,0 +1,45 @@
+package handlers
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "shinhan-eyetracking/server/models"
+)
+
+func TestRequireRole(t *testing.T) {
+    auth := newTestAuthService()
+    handler := RequireRole(auth, func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusNoContent)
+    }, models.RoleEmployee, models.RoleSupervisor)
+
+    cases := []struct {
+        name          string
+        authorization string
+        status        int
+    }{
+        {"직원 토큰", "Bearer " + testTokens[models.RoleEmployee], http.StatusNoContent},
+        {"감독자 토큰", "Bearer " + testTokens[models.RoleSupervisor], http.StatusNoContent},
+        {"고객 토큰", "Bearer " + testTokens[models.RoleCustomer], http.StatusUnauthorized},
+        {"틀린 토큰", "Bearer wrong", http.StatusUnauthorized},
+        {"Bearer 없음", testTokens[models.RoleEmployee], http.StatusUnauthorized},
+        {"빈 토큰", "Bearer ", http.StatusUnauthorized},
+        {"헤더 없음", "", http.StatusUnauthorized},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            req := httptest.NewRequest(http.MethodGet, "/sessions", nil)
+            if tc.authorization != "" {
+                req.Header.Set("Authorization", tc.authorization)
+            }
+            rec := httptest.NewRecorder()
+            handler(rec, req)
+
+            if rec.Code != tc.status {
+                t.Fatalf("상태 코드 = %d, 기대값 %d", rec.Code, tc.status)
+            }
+        })
+    }
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+    "net/url"
+    "testing"
+)
+
+// 범위를 벗어난 크기·반경은 DB를 조회하기 전에 400으로 거부한다
+func TestParseHeatmapQueryRejectsOutOfRangeOptions(t *testing.T) {
+    cases := map[string]struct {
+        query string
+        valid bool
+    }{
+        "기본값":      {"", true},
+        "정상 반경":    {"sigma=60", true},
+        "시간 경계":    {"from=1726000000000&to=1726000060000", true},
+        "아주 큰 반경":  {"sigma=20000", false},
+        "NaN 반경":   {"sigma=NaN", false},
+        "무한대 반경":   {"sigma=Inf", false},
+        "음수 반경":    {"sigma=-5", false},
+        "숫자 아닌 반경": {"sigma=wide", false},
+        "너무 큰 이미지": {"width=100000&height=100000", false},
+        "숫자 아닌 경계": {"from=yesterday", false},
+    }
+
+    for name, tc := range cases {
+        t.Run(name, func(t *testing.T) {
+            query, err := url.ParseQuery(tc.query)
+            if err != nil {
+                t.Fatalf("쿼리 해석 실패: %v", err)
+            }
+            _, _, err = parseHeatmapQuery("productDetail", query)
+            if tc.valid && err != nil {
+                t.Fatalf("정상 쿼리를 거부: %v", err)
+            }
+            if !tc.valid && err == nil {
+                t.Fatalf("잘못된 쿼리를 받아들임: %s", tc.query)
+            }
+        })
+    }
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+    "encoding/json"
+    "strconv"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/models"
+
+    "github.com/gorilla/websocket"
+)
+
+// 페이지 두 개에 걸친 세션 기록: 0ms productJoin, 20·40ms 시선, 60ms productDetail, 80·100ms 시선
+func saveReplaySession(t *testing.T, s *testServer) {
+    t.Helper()
+
+    if err := s.db.CreateSession(models.Session{ID: "s1", ProductID: "shinhan-global-multi-asset", StartedAt: time.Now()}); err != nil {
+        t.Fatalf("세션 생성 실패: %v", err)
+    }
+    for _, page := range []models.PageChangeData{
+        {SessionID: "s1", CurrentPage: "productJoin", Timestamp: 1000},
+        {SessionID: "s1", CurrentPage: "productDetail", Timestamp: 1060},
+    } {
+        if err := s.db.SavePageChange(page); err != nil {
+            t.Fatalf("페이지 변경 저장 실패: %v", err)
+        }
+    }
+    var samples []models.GazeData
+    for _, ts := range []int64{1020, 1040, 1080, 1100} {
+        samples = append(samples, models.GazeData{X: 10, Y: 20, Timestamp: ts, SessionID: "s1"})
+    }
+    if err := s.db.SaveGazeBatch(samples); err != nil {
+        t.Fatalf("시선 저장 실패: %v", err)
+    }
+}
+
+type replayMessage struct {
+    Type string
+    Data json.RawMessage
+}
+
+// 재생이 끝날 때까지 받은 메시지를 "타입:값" 형태로 모은다
+func readReplay(t *testing.T, conn *websocket.Conn) []string {
+    t.Helper()
+
+    var result []string
+    for {
+        var message replayMessage
+        if err := conn.ReadJSON(&message); err != nil {
+            t.Fatalf("재생 메시지 수신 실패 (받은 메시지 %v): %v", result, err)
+        }
+        switch message.Type {
+        case "replayState":
+            var state models.ReplayState
+            json.Unmarshal(message.Data, &state)
+            result = append(result, message.Type+":"+state.State)
+            if state.State == "ended" {
+                return result
+            }
+        case "pageChange":
+            var page models.PageChangeData
+            json.Unmarshal(message.Data, &page)
+            result = append(result, message.Type+":"+page.CurrentPage)
+        case "gazeData":
+            var gaze models.GazeData
+            json.Unmarshal(message.Data, &gaze)
+            result = append(result, message.Type+":"+strconv.FormatInt(gaze.Timestamp, 10))
+        }
+    }
+}
+
+func TestReplaySendsRecordedEventsInOrder(t *testing.T) {
+    cases := []struct {
+        name       string
+        positionMs int64
+        want       []string
+    }{
+        {
+            name:       "처음부터",
+            positionMs: 0,
+            want: []string{
+                "replayState:playing",
+                "pageChange:productJoin",
+                "gazeData:1020", "gazeData:1040",
+                "pageChange:productDetail",
+                "gazeData:1080", "gazeData:1100",
+                "replayState:ended",
+            },
+        },
+        {
+            // 중간부터 시작하면 직전 페이지를 먼저 보낸다
+            name:       "중간부터",
+            positionMs: 70,
+            want: []string{
+                "pageChange:productDetail",
+                "replayState:playing",
+                "gazeData:1080", "gazeData:1100",
+                "replayState:ended",
+            },
+        },
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            s := newTestServer(t)
+            saveReplaySession(t, s)
+            conn := s.connect(t, models.RoleSupervisor)
+
+            send(t, conn, "replayStart", models.ReplayStartData{SessionID: "s1", Speed: 16, PositionMs: tc.positionMs})
+            conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+            got := readReplay(t, conn)
+
+            if len(got) != len(tc.want) {
+                t.Fatalf("재생 메시지 %v, 기대값 %v", got, tc.want)
+            }
+            for i := range got {
+                if got[i] != tc.want[i] {
+                    t.Fatalf("재생 메시지 %v, 기대값 %v", got, tc.want)
+                }
+            }
+        })
+    }
+}
+
+func TestReplayRejectsInvalidRequests(t *testing.T) {
+    s := newTestServer(t)
+    saveReplaySession(t, s)
+    conn := s.connect(t, models.RoleSupervisor)
+
+    requests := []struct {
+        messageType string
+        data        interface{}
+    }{
+        {"replayStart", models.ReplayStartData{SessionID: "없는-세션"}},
+        {"replayStart", models.ReplayStartData{SessionID: "s1", Speed: 100}},
+        {"replayControl", models.ReplayControlData{Action: "pause"}}, // 재생 중이 아님
+        {"replayControl", models.ReplayControlData{Action: "rewind"}},
+    }
+    for _, req := range requests {
+        send(t, conn, req.messageType, req.data)
+        expectMessage(t, conn, "error")
+    }
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/catalog"
+    "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/evaluation"
+    "shinhan-eyetracking/server/models"
+    "shinhan-eyetracking/server/services"
+    "shinhan-eyetracking/server/validation"
+
+    "github.com/gorilla/websocket"
+)
+
+var testTokens = map[models.Role]string{
+    models.RoleCustomer:   "customer-token",
+    models.RoleEmployee:   "employee-token",
+    models.RoleSupervisor: "supervisor-token",
+}
+
+func newTestAuthService() *services.AuthService {
+    return services.NewAuthService(&config.Config{
+        CustomerToken:   testTokens[models.RoleCustomer],
+        EmployeeToken:   testTokens[models.RoleEmployee],
+        SupervisorToken: testTokens[models.RoleSupervisor],
+    })
+}
+
+// 메모리 저장소와 내장 버스로 main과 같은 구성의 WebSocket 핸들러를 띄운다
+type testServer struct {
+    db     *database.MemoryStore
+    server *httptest.Server
+}
+
+func newTestServer(t *testing.T) *testServer {
+    t.Helper()
+
+    terms, err := catalog.Load("")
+    if err != nil {
+        t.Fatalf("카탈로그 로드 실패: %v", err)
+    }
+
+    db := database.NewMemoryStore()
+    bus := services.NewLocalBus(db, 0)
+    websocketService := services.NewWebSocketService()
+    gazeService := services.NewGazeService(db, bus, websocketService, analysis.DefaultConfig())
+    sessionService := services.NewSessionService(db, terms)
+    calibrationService := services.NewCalibrationService(db, 50)
+    verdictService := services.NewVerdictService(db, terms, gazeService, calibrationService,
+        evaluation.DefaultRules(), analysis.DefaultReadingConfig())
+    replayService := services.NewReplayService(db, websocketService)
+    gazeValidator := validation.NewGazeValidator(terms, validation.Config{MaxClockSkew: time.Minute})
+    gazeValidator.RestoreFrom(db)
+    gazeService.OnSessionRelease(gazeValidator.Forget)
+
+    handler := NewWebSocketHandler(terms, newTestAuthService(), gazeService, sessionService, verdictService,
+        calibrationService, replayService, websocketService, gazeValidator)
+    server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+    t.Cleanup(func() {
+        server.Close()
+        gazeService.Shutdown()
+        bus.Close()
+    })
+    return &testServer{db: db, server: server}
+}
+
+func (s *testServer) dial(t *testing.T) *websocket.Conn {
+    t.Helper()
+
+    conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.server.URL, "http"), nil)
+    if err != nil {
+        t.Fatalf("WebSocket 연결 실패: %v", err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return conn
+}
+
+// hello를 보내고 helloAck를 확인한 연결
+func (s *testServer) connect(t *testing.T, role models.Role) *websocket.Conn {
+    t.Helper()
+
+    conn := s.dial(t)
+    send(t, conn, "hello", models.HelloData{Role: role, Token: testTokens[role]})
+    expectMessage(t, conn, "helloAck")
+    return conn
+}
+
+func send(t *testing.T, conn *websocket.Conn, messageType string, data interface{}) {
+    t.Helper()
+
+    if err := conn.WriteJSON(models.WebSocketMessage{Type: messageType, Data: data}); err != nil {
+        t.Fatalf("%s 전송 실패: %v", messageType, err)
+    }
+}
+
+// messageType 메시지가 올 때까지 읽는다 (사이에 온 방 인원 알림 등은 건너뛴다)
+func expectMessage(t *testing.T, conn *websocket.Conn, messageType string) models.WebSocketMessage {
+    t.Helper()
+
+    conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+    defer conn.SetReadDeadline(time.Time{})
+    for {
+        var message models.WebSocketMessage
+        if err := conn.ReadJSON(&message); err != nil {
+            t.Fatalf("%s 수신 실패: %v", messageType, err)
+        }
+        if message.Type == messageType {
+            return message
+        }
+    }
+}
@@ -2,11 +2,15 @@ package handlers
 
 import (
     "encoding/json"
+    "fmt"
     "log"
     "net/http"
+    "time"
 
+    "shinhan-eyetracking/server/catalog"
     "shinhan-eyetracking/server/models"
     "shinhan-eyetracking/server/services"
+    "shinhan-eyetracking/server/validation"
 
     "github.com/gorilla/websocket"
 )
@@ -15,15 +19,37 @@ var upgrader = websocket.Upgrader{
     CheckOrigin: func(r *http.Request) bool { return true },
 }
 
+const (
+    handshakeTimeout = 10 * time.Second
+    // 권한 없는 메시지를 이 횟수 이상 보내면 연결을 끊는다
+    maxViolations = 5
+    // gazeBatch 한 번에 받을 수 있는 최대 샘플 수
+    maxBatchSamples = 500
+)
+
 type WebSocketHandler struct {
-    gazeService      *services.GazeService
-    websocketService *services.WebSocketService
+    catalog            *catalog.Catalog
+    authService        *services.AuthService
+    gazeService        *services.GazeService
+    sessionService     *services.SessionService
+    verdictService     *services.VerdictService
+    calibrationService *services.CalibrationService
+    replayService      *services.ReplayService
+    websocketService   *services.WebSocketService
+    gazeValidator      *validation.GazeValidator
 }
 
-func NewWebSocketHandler(gazeService *services.GazeService, websocketService *services.WebSocketService) *WebSocketHandler {
+func NewWebSocketHandler(terms *catalog.Catalog, authService *services.AuthService, gazeService *services.GazeService, sessionService *services.SessionService, verdictService *services.VerdictService, calibrationService *services.CalibrationService, replayService *services.ReplayService, websocketService *services.WebSocketService, gazeValidator *validation.GazeValidator) *WebSocketHandler {
     return &WebSocketHandler{
-        gazeService:      gazeService,
-        websocketService: websocketService,
+        catalog:            terms,
+        authService:        authService,
+        gazeService:        gazeService,
+        sessionService:     sessionService,
+        verdictService:     verdictService,
+        calibrationService: calibrationService,
+        replayService:      replayService,
+        websocketService:   websocketService,
+        gazeValidator:      gazeValidator,
     }
 }
 
@@ -34,13 +60,35 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
         return
     }
     defer func() {
+        h.replayService.Stop(conn)
+        room, role := h.websocketService.GetRoom(conn), h.websocketService.GetRole(conn)
         h.websocketService.RemoveClient(conn)
         conn.Close()
+
+        // 시선을 보내던 키오스크가 모두 끊기면 세션 상태를 메모리에서 내린다 (세션 종료 없이 끊긴 경우)
+        if role == models.RoleCustomer && room != "" && !h.websocketService.RoomHasRole(room, models.RoleCustomer) {
+            h.gazeService.ReleaseSession(room)
+        }
     }()
 
     // 클라이언트 연결 등록
     h.websocketService.AddClient(conn)
 
+    // 첫 메시지로 역할을 선언하고 토큰으로 증명해야 한다
+    role, err := h.handshake(conn)
+    if err != nil {
+        log.Printf("🚫 핸드셰이크 실패 [%s]: %v", conn.RemoteAddr().String(), err)
+        h.websocketService.SendToClient(conn, "error", "인증 실패")
+        return
+    }
+    h.websocketService.SetRole(conn, role)
+    h.websocketService.SendToClient(conn, "helloAck", map[string]interface{}{
+        "role": role,
+    })
+    log.Printf("🔐 인증 완료: %s (%s)", conn.RemoteAddr().String(), role)
+
+    violations := 0
+
     for {
         var message models.WebSocketMessage
         err := conn.ReadJSON(&message)
@@ -49,35 +97,323 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
             break
         }
 
+        // 역할별 송신 권한 확인
+        if !services.CanSend(role, message.Type) {
+            violations++
+            log.Printf("🚫 권한 위반 [%s] 역할 %s가 %q 전송 시도 (%d/%d)",
+                conn.RemoteAddr().String(), role, message.Type, violations, maxViolations)
+            h.websocketService.SendToClient(conn, "error",
+                fmt.Sprintf("%s 역할은 %s 메시지를 보낼 수 없습니다", role, message.Type))
+            if violations >= maxViolations {
+                log.Printf("🚫 권한 위반 누적으로 연결 종료: %s", conn.RemoteAddr().String())
+                break
+            }
+            continue
+        }
+
+        // 연결이 참여 중인 세션 방이 곧 이 연결의 세션
+        sessionID := h.websocketService.GetRoom(conn)
+
         switch message.Type {
+        case "sessionStart":
+            h.handleSessionStart(conn, message.Data, sessionID)
+        case "sessionJoin":
+            h.handleSessionJoin(conn, message.Data)
+        case "sessionEnd":
+            h.handleSessionEnd(conn, message.Data, sessionID)
+        case "verdictRequest":
+            h.handleVerdictRequest(conn, sessionID)
+        case "replayStart":
+            h.handleReplayStart(conn, message.Data)
+        case "replayControl":
+            h.handleReplayControl(conn, message.Data)
+        case "replayStop":
+            h.replayService.Stop(conn)
+        case "calibration":
+            h.handleCalibration(conn, message.Data, sessionID)
         case "gazeData":
-            h.handleGazeData(message.Data)
+            h.handleGazeData(conn, message.Data, sessionID)
+        case "gazeBatch":
+            h.handleGazeBatch(conn, message.Data, sessionID)
         case "pageChange":
-            h.handlePageChange(message.Data)
+            h.handlePageChange(conn, message.Data, sessionID)
         default:
             log.Printf("알 수 없는 메시지 타입: %s", message.Type)
         }
     }
 }
 
-func (h *WebSocketHandler) handleGazeData(data interface{}) {
-    jsonData, err := json.Marshal(data)
+func (h *WebSocketHandler) handshake(conn *websocket.Conn) (models.Role, error) {
+    conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+    defer conn.SetReadDeadline(time.Time{})
+
+    var message models.WebSocketMessage
+    if err := conn.ReadJSON(&message); err != nil {
+        return "", fmt.Errorf("핸드셰이크 수신 실패: %w", err)
+    }
+    if message.Type != "hello" {
+        return "", fmt.Errorf("첫 메시지가 hello가 아님: %s", message.Type)
+    }
+
+    var hello models.HelloData
+    if err := decodeMessageData(message.Data, &hello); err != nil {
+        return "", fmt.Errorf("핸드셰이크 데이터 언마샬링 실패: %w", err)
+    }
+
+    if err := h.authService.Authenticate(hello); err != nil {
+        return "", err
+    }
+    return hello.Role, nil
+}
+
+// 새 세션을 시작하고 연결을 해당 세션 방에 참여시킨다
+func (h *WebSocketHandler) handleSessionStart(conn *websocket.Conn, data interface{}, current string) {
+    var req models.SessionStartData
+    if err := decodeMessageData(data, &req); err != nil {
+        log.Printf("세션 시작 데이터 언마샬링 실패: %v", err)
+        h.websocketService.SendToClient(conn, "error", "잘못된 세션 시작 요청")
+        return
+    }
+
+    if current != "" {
+        log.Printf("⚠️ 기존 세션 %s 진행 중에 새 세션 시작 요청", current)
+    }
+
+    session, err := h.sessionService.StartSession(req)
+    if err != nil {
+        log.Printf("❌ 세션 시작 실패: %v", err)
+        h.websocketService.SendToClient(conn, "error", "세션 시작 실패")
+        return
+    }
+
+    h.websocketService.JoinRoom(conn, session.ID)
+    h.websocketService.SendToClient(conn, "sessionStarted", session)
+}
+
+// 이미 시작된 세션 방에 참여 (고객 키오스크와 직원 대시보드가 같은 방을 공유)
+func (h *WebSocketHandler) handleSessionJoin(conn *websocket.Conn, data interface{}) {
+    var req models.SessionJoinData
+    if err := decodeMessageData(data, &req); err != nil || req.SessionID == "" {
+        h.websocketService.SendToClient(conn, "error", "잘못된 세션 참여 요청")
+        return
+    }
+
+    session, err := h.sessionService.GetSession(req.SessionID)
     if err != nil {
-        log.Printf("시선 데이터  실패: %v", err)
+        log.Printf("❌ 세션 조회 실패: %v", err)
+        h.websocketService.SendToClient(conn, "error", "세션 조회 실패")
+        return
+    }
+    if session == nil || session.EndedAt != nil {
+        h.websocketService.SendToClient(conn, "error", "진행 중인 세션이 아닙니다")
         return
     }
 
-    var gazeData models.GazeData
-    err = json.Unmarshal(jsonData, &gazeData)
+    // 실시간 세션에 합류하면 진행 중이던 재생은 멈춘다
+    h.replayService.Stop(conn)
+    h.websocketService.JoinRoom(conn, session.ID)
+    h.websocketService.SendToClient(conn, "sessionJoined", session)
+}
+
+func (h *WebSocketHandler) handleSessionEnd(conn *websocket.Conn, data interface{}, current string) {
+    var req models.SessionEndData
+    if err := decodeMessageData(data, &req); err != nil {
+        log.Printf("세션 종료 데이터 언마샬링 실패: %v", err)
+        h.websocketService.SendToClient(conn, "error", "잘못된 세션 종료 요청")
+        return
+    }
+
+    if req.SessionID == "" {
+        req.SessionID = current
+    }
+    if req.SessionID == "" {
+        h.websocketService.SendToClient(conn, "error", "종료할 세션이 없습니다")
+        return
+    }
+    // 참여 중인 세션 방의 세션만 끝낼 수 있다 (다른 지점·창구의 세션 ID를 알아도 종료하지 못하도록)
+    if req.SessionID != current {
+        log.Printf("🚫 참여하지 않은 세션 종료 시도 [%s]: %s (참여 중: %q)",
+            conn.RemoteAddr().String(), req.SessionID, current)
+        h.websocketService.SendToClient(conn, "error", "참여 중인 세션만 종료할 수 있습니다")
+        return
+    }
+
+    session, err := h.sessionService.EndSession(req.SessionID)
     if err != nil {
-        log.Printf("시선 데이터 언마샬링 실패: %v", err)
+        log.Printf("❌ 세션 종료 실패: %v", err)
+        h.websocketService.SendToClient(conn, "error", "세션 종료 실패")
         return
     }
 
+    h.gazeService.FinishSession(session.ID)
+
+    // 방 참여자 모두에게 알린 뒤 방을 닫는다
+    h.websocketService.BroadcastToRoom(session.ID, "sessionEnded", session)
+    h.websocketService.CloseRoom(session.ID)
+}
+
+// 현재 세션의 판정 결과를 요청한 연결에 응답
+func (h *WebSocketHandler) handleVerdictRequest(conn *websocket.Conn, sessionID string) {
+    if sessionID == "" {
+        h.websocketService.SendToClient(conn, "error", "참여 중인 세션이 없습니다")
+        return
+    }
+
+    verdict, err := h.verdictService.EvaluateSession(sessionID)
+    if err != nil {
+        log.Printf("❌ 세션 판정 실패 [%s]: %v", sessionID, err)
+        h.websocketService.SendToClient(conn, "error", "세션 판정 실패")
+        return
+    }
+
+    h.websocketService.SendToClient(conn, "verdict", verdict)
+}
+
+// 고객 키오스크의 보정 결과를 저장하고 방 전체에 품질을 알린다
+func (h *WebSocketHandler) handleCalibration(conn *websocket.Conn, data interface{}, sessionID string) {
+    if sessionID == "" {
+        h.websocketService.SendToClient(conn, "error", "참여 중인 세션이 없습니다")
+        return
+    }
+
+    var calibration models.CalibrationData
+    if err := decodeMessageData(data, &calibration); err != nil {
+        log.Printf("보정 데이터 언마샬링 실패: %v", err)
+        h.websocketService.SendToClient(conn, "error", "잘못된 보정 데이터")
+        return
+    }
+
+    result, err := h.calibrationService.RecordCalibration(sessionID, calibration)
+    if err != nil {
+        log.Printf("❌ 보정 결과 처리 실패 [%s]: %v", sessionID, err)
+        h.websocketService.SendToClient(conn, "error", "보정 결과 처리 실패")
+        return
+    }
+
+    // 보정 점 목록은 빼고 요약만 전송
+    summary := *result
+    summary.Points = nil
+    h.websocketService.BroadcastToRoom(sessionID, "calibrationResult", summary)
+}
+
+// 저장된 세션 재생. 실시간 방송과 섞이지 않도록 참여 중인 방에서 먼저 나간다
+func (h *WebSocketHandler) handleReplayStart(conn *websocket.Conn, data interface{}) {
+    var req models.ReplayStartData
+    if err := decodeMessageData(data, &req); err != nil || req.SessionID == "" {
+        h.websocketService.SendToClient(conn, "error", "잘못된 재생 요청")
+        return
+    }
+
+    h.websocketService.LeaveRoom(conn)
+    if err := h.replayService.Start(conn, req); err != nil {
+        log.Printf("❌ 세션 재생 시작 실패 [%s]: %v", req.SessionID, err)
+        h.websocketService.SendToClient(conn, "error", err.Error())
+    }
+}
+
+func (h *WebSocketHandler) handleReplayControl(conn *websocket.Conn, data interface{}) {
+    var cmd models.ReplayControlData
+    if err := decodeMessageData(data, &cmd); err != nil {
+        h.websocketService.SendToClient(conn, "error", "잘못된 재생 제어 요청")
+        return
+    }
+
+    if err := h.replayService.Control(conn, cmd); err != nil {
+        h.websocketService.SendToClient(conn, "error", err.Error())
+    }
+}
+
+func (h *WebSocketHandler) handleGazeData(conn *websocket.Conn, data interface{}, sessionID string) {
+    if sessionID == "" {
+        log.Printf("⚠️ 세션 없이 수신된 시선 데이터 무시")
+        return
+    }
+
+    now := time.Now()
+    raw, err := json.Marshal(data)
+    if err != nil {
+        h.rejectGaze(conn, sessionID, h.gazeValidator.RecordMalformed(sessionID, err), now)
+        return
+    }
+
+    // Consumer와 같은 디코더 사용
+    gazeData, err := models.DecodeGazeData(raw)
+    if err != nil {
+        h.rejectGaze(conn, sessionID, h.gazeValidator.RecordMalformed(sessionID, err), now)
+        return
+    }
+
+    // 좌표 범위, timestamp 순서/시각, 카탈로그 페이지·섹션 검증
+    if rejection := h.gazeValidator.Validate(sessionID, gazeData, now); rejection != nil {
+        h.rejectGaze(conn, sessionID, rejection, now)
+        return
+    }
+
+    // 세션 ID는 클라이언트가 보낸 값이 아니라 연결에 바인딩된 값을 사용
+    gazeData.SessionID = sessionID
     h.gazeService.HandleGazeData(gazeData)
 }
 
-func (h *WebSocketHandler) handlePageChange(data interface{}) {
+// 여러 샘플을 한 번에 받는다. 샘플마다 검증해 통과한 것만 처리
+func (h *WebSocketHandler) handleGazeBatch(conn *websocket.Conn, data interface{}, sessionID string) {
+    if sessionID == "" {
+        log.Printf("⚠️ 세션 없이 수신된 시선 배치 무시")
+        return
+    }
+
+    var batch models.GazeBatchData
+    if err := decodeMessageData(data, &batch); err != nil {
+        log.Printf("시선 배치 언마샬링 실패: %v", err)
+        h.websocketService.SendToClient(conn, "error", "잘못된 시선 배치")
+        return
+    }
+    if len(batch.Samples) > maxBatchSamples {
+        h.websocketService.SendToClient(conn, "error",
+            fmt.Sprintf("시선 배치는 최대 %d건까지 보낼 수 있습니다 (%d건)", maxBatchSamples, len(batch.Samples)))
+        return
+    }
+
+    now := time.Now()
+    accepted := make([]models.GazeData, 0, len(batch.Samples))
+    for _, raw := range batch.Samples {
+        gazeData, err := models.DecodeGazeData(raw)
+        if err != nil {
+            h.rejectGaze(conn, sessionID, h.gazeValidator.RecordMalformed(sessionID, err), now)
+            continue
+        }
+        if rejection := h.gazeValidator.Validate(sessionID, gazeData, now); rejection != nil {
+            h.rejectGaze(conn, sessionID, rejection, now)
+            continue
+        }
+        gazeData.SessionID = sessionID
+        accepted = append(accepted, gazeData)
+    }
+
+    h.gazeService.HandleGazeBatch(accepted)
+}
+
+// 거부 사유와 누적 건수를 보낸 쪽에 알린다 (초당 최대 1회)
+func (h *WebSocketHandler) rejectGaze(conn *websocket.Conn, sessionID string, rejection *validation.Rejection, now time.Time) {
+    if !h.gazeValidator.ReportDue(sessionID, now) {
+        return
+    }
+
+    log.Printf("⚠️ 시선 데이터 거부 [%s]: %v", sessionID, rejection)
+    h.websocketService.SendToClient(conn, "gazeRejected", map[string]interface{}{
+        "sessionId": sessionID,
+        "reason":    rejection.Reason,
+        "detail":    rejection.Detail,
+        "rejected":  h.gazeValidator.Rejected(sessionID),
+        "timestamp": now.UnixMilli(),
+    })
+}
+
+func (h *WebSocketHandler) handlePageChange(conn *websocket.Conn, data interface{}, sessionID string) {
+    if sessionID == "" {
+        log.Printf("⚠️ 세션 없이 수신된 페이지 변경 무시")
+        return
+    }
+
     jsonData, err := json.Marshal(data)
     if err != nil {
         log.Printf("페이지 변경 데이터 마샬링 실패: %v", err)
@@ -91,5 +427,21 @@ func (h *WebSocketHandler) handlePageChange(data interface{}) {
         return
     }
 
+    if err := h.gazeValidator.ValidatePage(sessionID, pageData.CurrentPage); err != nil {
+        log.Printf("⚠️ 페이지 변경 검증 실패 [%s]: %v", sessionID, err)
+        h.websocketService.SendToClient(conn, "error", err.Error())
+        return
+    }
+
+    pageData.SessionID = sessionID
     h.gazeService.HandlePageChange(pageData)
-}
\ No newline at end of file
+}
+
+// WebSocketMessage.Data(interface{})를 구체 타입으로 변환
+func decodeMessageData(data interface{}, v interface{}) error {
+    jsonData, err := json.Marshal(data)
+    if err != nil {
+        return err
+    }
+    return json.Unmarshal(jsonData, v)
+}
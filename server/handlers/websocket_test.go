@@ -0,0 +1,177 @@
+package handlers
+
+import (
+    "encoding/json"
+    "fmt"
+    "net"
+    "reflect"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/models"
+
+    "github.com/gorilla/websocket"
+)
+
+func TestHandshakeAcceptsEachRoleWithItsToken(t *testing.T) {
+    s := newTestServer(t)
+
+    for _, role := range []models.Role{models.RoleCustomer, models.RoleEmployee, models.RoleSupervisor} {
+        t.Run(string(role), func(t *testing.T) {
+            conn := s.connect(t, role)
+            // 인증된 연결은 계속 열려 있다
+            send(t, conn, "sessionJoin", models.SessionJoinData{SessionID: "없는-세션"})
+            expectMessage(t, conn, "error")
+        })
+    }
+}
+
+func TestHandshakeRejectsInvalidHello(t *testing.T) {
+    s := newTestServer(t)
+
+    cases := map[string]models.WebSocketMessage{
+        "hello 아닌 첫 메시지": {Type: "gazeData", Data: map[string]interface{}{"x": 1, "y": 2}},
+        "틀린 토큰":          {Type: "hello", Data: models.HelloData{Role: models.RoleEmployee, Token: "wrong"}},
+        "다른 역할의 토큰":      {Type: "hello", Data: models.HelloData{Role: models.RoleSupervisor, Token: testTokens[models.RoleEmployee]}},
+        "알 수 없는 역할":      {Type: "hello", Data: models.HelloData{Role: "admin", Token: testTokens[models.RoleSupervisor]}},
+        "토큰 없음":          {Type: "hello", Data: models.HelloData{Role: models.RoleCustomer}},
+    }
+
+    for name, hello := range cases {
+        t.Run(name, func(t *testing.T) {
+            conn := s.dial(t)
+            if err := conn.WriteJSON(hello); err != nil {
+                t.Fatalf("hello 전송 실패: %v", err)
+            }
+            expectMessage(t, conn, "error")
+
+            // 실패한 연결은 서버가 닫는다
+            conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+            var message models.WebSocketMessage
+            err := conn.ReadJSON(&message)
+            if timeout, ok := err.(net.Error); err == nil || ok && timeout.Timeout() {
+                t.Fatalf("인증 실패 후에도 연결이 열려 있음: %+v, %v", message, err)
+            }
+        })
+    }
+}
+
+// 역할에 허용되지 않은 메시지는 거부하고 처리하지 않는다
+func TestRoleCannotSendForbiddenMessages(t *testing.T) {
+    s := newTestServer(t)
+
+    customer := s.connect(t, models.RoleCustomer)
+    send(t, customer, "sessionStart", models.SessionStartData{BranchID: "b1", EmployeeID: "e1", ProductID: "shinhan-global-multi-asset"})
+    expectMessage(t, customer, "error")
+
+    sessions, err := s.db.GetRecentSessions(10)
+    if err != nil {
+        t.Fatalf("세션 목록 조회 실패: %v", err)
+    }
+    if len(sessions) != 0 {
+        t.Fatalf("고객 연결이 세션을 시작함: %+v", sessions)
+    }
+}
+
+// 참여 중인 세션 방의 세션만 끝낼 수 있다
+func TestSessionEndRequiresJoinedSession(t *testing.T) {
+    s := newTestServer(t)
+
+    start := func(conn *websocket.Conn) models.Session {
+        t.Helper()
+        send(t, conn, "sessionStart", models.SessionStartData{BranchID: "b1", EmployeeID: "e1", ProductID: "shinhan-global-multi-asset"})
+        var session models.Session
+        if err := decodeMessageData(expectMessage(t, conn, "sessionStarted").Data, &session); err != nil {
+            t.Fatalf("세션 시작 응답 해석 실패: %v", err)
+        }
+        return session
+    }
+    ended := func(sessionID string) bool {
+        t.Helper()
+        session, err := s.db.GetSession(sessionID)
+        if err != nil || session == nil {
+            t.Fatalf("세션 조회 실패: %v", err)
+        }
+        return session.EndedAt != nil
+    }
+
+    owner := s.connect(t, models.RoleEmployee)
+    other := s.connect(t, models.RoleEmployee)
+    outsider := s.connect(t, models.RoleSupervisor)
+    session := start(owner)
+    start(other)
+
+    // 다른 세션 방의 직원, 어느 방에도 없는 감독자
+    for _, conn := range []*websocket.Conn{other, outsider} {
+        send(t, conn, "sessionEnd", models.SessionEndData{SessionID: session.ID})
+        expectMessage(t, conn, "error")
+    }
+    if ended(session.ID) {
+        t.Fatal("참여하지 않은 연결이 세션을 종료함")
+    }
+
+    send(t, owner, "sessionEnd", models.SessionEndData{SessionID: session.ID})
+    expectMessage(t, owner, "sessionEnded")
+    if !ended(session.ID) {
+        t.Fatal("세션 방 참여자가 세션을 종료하지 못함")
+    }
+}
+
+// 시선 배치는 샘플마다 검증해 통과한 것을 모두 세션 샘플로 저장한다
+func TestGazeBatchForwardsEveryAcceptedSample(t *testing.T) {
+    s := newTestServer(t)
+
+    employee := s.connect(t, models.RoleEmployee)
+    send(t, employee, "sessionStart", models.SessionStartData{BranchID: "b1", EmployeeID: "e1", ProductID: "shinhan-global-multi-asset"})
+    var session models.Session
+    if err := decodeMessageData(expectMessage(t, employee, "sessionStarted").Data, &session); err != nil {
+        t.Fatalf("세션 시작 응답 해석 실패: %v", err)
+    }
+    customer := s.connect(t, models.RoleCustomer)
+    send(t, customer, "sessionJoin", models.SessionJoinData{SessionID: session.ID})
+    expectMessage(t, customer, "sessionJoined")
+
+    base := time.Now().UnixMilli()
+    sample := func(offset int64) json.RawMessage {
+        return json.RawMessage(fmt.Sprintf(`{"x":100,"y":200,"timestamp":%d,"currentPage":"productDetail"}`, base+offset))
+    }
+    send(t, customer, "gazeBatch", models.GazeBatchData{Samples: []json.RawMessage{
+        sample(0),
+        sample(20),
+        json.RawMessage(`{"x":1,"y":2,"timestamp":"abc"}`), // 해석 불가
+        sample(10),                                        // 직전 샘플보다 이전 시각
+        sample(40),
+    }})
+    expectMessage(t, customer, "gazeRejected")
+
+    // 한도를 넘는 배치는 샘플 하나도 처리하지 않는다
+    oversized := make([]json.RawMessage, maxBatchSamples+1)
+    for i := range oversized {
+        oversized[i] = sample(int64(100 + i))
+    }
+    send(t, customer, "gazeBatch", models.GazeBatchData{Samples: oversized})
+    expectMessage(t, customer, "error")
+
+    stored := func() []int64 {
+        points, err := s.db.GetGazePoints(database.GazeFilter{PageID: "productDetail", SessionIDs: []string{session.ID}, Limit: 1000})
+        if err != nil {
+            t.Fatalf("시선 데이터 조회 실패: %v", err)
+        }
+        timestamps := make([]int64, len(points))
+        for i, p := range points {
+            timestamps[i] = p.Timestamp - base
+        }
+        return timestamps
+    }
+    want := []int64{0, 20, 40}
+    deadline := time.Now().Add(2 * time.Second)
+    for len(stored()) < len(want) && time.Now().Before(deadline) {
+        time.Sleep(20 * time.Millisecond)
+    }
+    // 늦게 도착하는 샘플이 없는지 한 번 더 flush 주기를 기다린다
+    time.Sleep(200 * time.Millisecond)
+    if got := stored(); !reflect.DeepEqual(got, want) {
+        t.Fatalf("저장된 샘플 timestamp(기준 대비) = %v, 기대값 %v", got, want)
+    }
+}
@@ -0,0 +1,143 @@
+package integrity
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "strconv"
+    "strings"
+)
+
+// 세션 체인의 첫 레코드가 참조하는 이전 해시
+var GenesisHash = strings.Repeat("0", 64)
+
+const (
+    KindGaze = "gaze"
+    KindPage = "page"
+)
+
+// 체인에 들어가는 레코드. 시선 데이터와 페이지 변경이 하나의 세션 체인을 공유한다
+type Record struct {
+    Kind        string  `json:"kind"`
+    SessionID   string  `json:"sessionId"`
+    Seq         int64   `json:"seq"`
+    Timestamp   int64   `json:"timestamp"`
+    X           float64 `json:"x,omitempty"`
+    Y           float64 `json:"y,omitempty"`
+    SectionID   string  `json:"sectionId,omitempty"`
+    CurrentPage string  `json:"currentPage,omitempty"`
+    // 샘플을 잡은 시점의 클라이언트 뷰포트 (보고하지 않았으면 0)
+    ViewportWidth  int    `json:"viewportWidth,omitempty"`
+    ViewportHeight int    `json:"viewportHeight,omitempty"`
+    PrevHash       string `json:"prevHash"`
+    Hash           string `json:"hash"`
+}
+
+// 세션 체인의 마지막 위치
+type Head struct {
+    Seq  int64  `json:"seq"`
+    Hash string `json:"hash"`
+}
+
+// 레코드 내용과 이전 해시로 계산한 SHA-256 (Hash 필드 자체는 제외)
+func (r Record) ComputeHash() string {
+    fields := []string{
+        r.Kind,
+        r.SessionID,
+        strconv.FormatInt(r.Seq, 10),
+        strconv.FormatInt(r.Timestamp, 10),
+        strconv.FormatFloat(r.X, 'g', -1, 64),
+        strconv.FormatFloat(r.Y, 'g', -1, 64),
+        r.SectionID,
+        r.CurrentPage,
+        strconv.Itoa(r.ViewportWidth),
+        strconv.Itoa(r.ViewportHeight),
+        r.PrevHash,
+    }
+    sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+    return hex.EncodeToString(sum[:])
+}
+
+// head 다음에 올 레코드로 seq, 이전 해시, 해시를 채운다
+func (r *Record) Link(head Head) {
+    r.Seq = head.Seq + 1
+    r.PrevHash = head.Hash
+    if r.PrevHash == "" {
+        r.PrevHash = GenesisHash
+    }
+    r.Hash = r.ComputeHash()
+}
+
+type Issue struct {
+    Seq     int64  `json:"seq"`
+    Problem string `json:"problem"`
+}
+
+type Report struct {
+    SessionID   string  `json:"sessionId"`
+    RecordCount int     `json:"recordCount"`
+    HeadSeq     int64   `json:"headSeq"`
+    HeadHash    string  `json:"headHash"`
+    Valid       bool    `json:"valid"`
+    Issues      []Issue `json:"issues,omitempty"`
+}
+
+// seq 순으로 정렬된 레코드와 체인 헤드로 변조·순서 변경·누락을 검출.
+// 존재하는 세션에 대해 호출한다. 레코드와 헤드가 모두 없으면 세션 증적 전체가 삭제된 것과 구분할 수 없으므로 통과시키지 않는다
+func Verify(sessionID string, records []Record, head Head) Report {
+    report := Report{
+        SessionID:   sessionID,
+        RecordCount: len(records),
+        HeadSeq:     head.Seq,
+        HeadHash:    head.Hash,
+    }
+    addIssue := func(seq int64, format string, args ...interface{}) {
+        report.Issues = append(report.Issues, Issue{Seq: seq, Problem: fmt.Sprintf(format, args...)})
+    }
+
+    expectedSeq := int64(1)
+    prevHash := GenesisHash
+
+    for _, r := range records {
+        switch {
+        case r.Seq > expectedSeq:
+            addIssue(expectedSeq, "레코드 누락: seq %d ~ %d", expectedSeq, r.Seq-1)
+        case r.Seq < expectedSeq:
+            addIssue(r.Seq, "중복 또는 순서가 바뀐 레코드")
+        }
+
+        if r.PrevHash != prevHash {
+            addIssue(r.Seq, "이전 해시 불일치 (앞 레코드가 변조·삭제되었거나 순서가 바뀜)")
+        }
+        if r.ComputeHash() != r.Hash {
+            addIssue(r.Seq, "레코드 내용 변조")
+        }
+
+        prevHash = r.Hash
+        if r.Seq >= expectedSeq {
+            expectedSeq = r.Seq + 1
+        }
+    }
+
+    // 마지막 레코드를 헤드와 비교. 헤드 행까지 지우고 꼬리를 잘라내면 헤드가 없는 것으로 나타난다
+    if len(records) > 0 {
+        last := records[len(records)-1]
+        switch {
+        case head.Seq == 0:
+            addIssue(last.Seq, "체인 헤드 없음 (마지막 레코드 이후가 삭제되었을 수 있음)")
+        case head.Seq > last.Seq:
+            addIssue(last.Seq+1, "레코드 누락: seq %d ~ %d", last.Seq+1, head.Seq)
+        case head.Seq < last.Seq:
+            addIssue(head.Seq+1, "체인 헤드 이후에 추가된 레코드: seq %d ~ %d", head.Seq+1, last.Seq)
+        case head.Hash != last.Hash:
+            addIssue(head.Seq, "체인 헤드 해시 불일치")
+        }
+    } else if head.Seq > 0 {
+        addIssue(1, "레코드 누락: seq 1 ~ %d", head.Seq)
+    } else {
+        addIssue(0, "체인 없음 (기록과 체인 헤드가 모두 없음)")
+    }
+
+    report.Valid = len(report.Issues) == 0
+    return report
+}
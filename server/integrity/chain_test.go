@@ -0,0 +1,120 @@
+package integrity
+
+import (
+    "strings"
+    "testing"
+)
+
+func linkedRecords(sessionID string, n int) ([]Record, Head) {
+    var records []Record
+    head := Head{Hash: GenesisHash}
+    for i := 0; i < n; i++ {
+        r := Record{Kind: KindGaze, SessionID: sessionID, Timestamp: int64(1000 + i*20), X: float64(i), Y: 10}
+        r.Link(head)
+        head = Head{Seq: r.Seq, Hash: r.Hash}
+        records = append(records, r)
+    }
+    return records, head
+}
+
+func TestVerify(t *testing.T) {
+    cases := []struct {
+        name    string
+        modify  func(records []Record, head Head) ([]Record, Head)
+        valid   bool
+        seq     int64
+        problem string
+    }{
+        {
+            name:   "정상 체인",
+            modify: func(r []Record, h Head) ([]Record, Head) { return r, h },
+            valid:  true,
+        },
+        {
+            name: "레코드와 헤드 모두 삭제",
+            modify: func(r []Record, h Head) ([]Record, Head) {
+                return nil, Head{}
+            },
+            seq: 0, problem: "체인 없음",
+        },
+        {
+            name: "내용 변조",
+            modify: func(r []Record, h Head) ([]Record, Head) {
+                r[1].X = 999
+                return r, h
+            },
+            seq: 2, problem: "레코드 내용 변조",
+        },
+        {
+            name: "뷰포트 변조",
+            modify: func(r []Record, h Head) ([]Record, Head) {
+                r[1].ViewportWidth = 1280
+                return r, h
+            },
+            seq: 2, problem: "레코드 내용 변조",
+        },
+        {
+            name: "중간 삭제",
+            modify: func(r []Record, h Head) ([]Record, Head) {
+                return append(r[:1:1], r[2:]...), h
+            },
+            seq: 2, problem: "레코드 누락",
+        },
+        {
+            name: "꼬리 삭제",
+            modify: func(r []Record, h Head) ([]Record, Head) {
+                return r[:3], h
+            },
+            seq: 4, problem: "레코드 누락",
+        },
+        {
+            name: "꼬리와 헤드 삭제",
+            modify: func(r []Record, h Head) ([]Record, Head) {
+                return r[:3], Head{}
+            },
+            seq: 3, problem: "체인 헤드 없음",
+        },
+        {
+            name: "레코드 전부 삭제",
+            modify: func(r []Record, h Head) ([]Record, Head) {
+                return nil, h
+            },
+            seq: 1, problem: "레코드 누락",
+        },
+        {
+            name: "헤드 이후 레코드",
+            modify: func(r []Record, h Head) ([]Record, Head) {
+                return r, Head{Seq: r[2].Seq, Hash: r[2].Hash}
+            },
+            seq: 4, problem: "체인 헤드 이후에 추가된 레코드",
+        },
+        {
+            name: "헤드 해시 불일치",
+            modify: func(r []Record, h Head) ([]Record, Head) {
+                h.Hash = strings.Repeat("f", 64)
+                return r, h
+            },
+            seq: 4, problem: "체인 헤드 해시 불일치",
+        },
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            records, head := tc.modify(linkedRecords("s1", 4))
+            report := Verify("s1", records, head)
+
+            if report.Valid != tc.valid {
+                t.Fatalf("Valid = %v, 기대값 %v: %+v", report.Valid, tc.valid, report.Issues)
+            }
+            if tc.valid {
+                return
+            }
+            for _, issue := range report.Issues {
+                if issue.Seq == tc.seq && strings.HasPrefix(issue.Problem, tc.problem) {
+                    return
+                }
+            }
+            t.Fatalf("seq %d %q 문제를 찾지 못함: %+v", tc.seq, tc.problem, report.Issues)
+        })
+    }
+}
@@ -2,7 +2,10 @@ package models
 
 import (
     "encoding/json"
+    "fmt"
+    "math"
     "strconv"
+    "time"
 )
 
 // 확장된 GazeData 구조체
@@ -12,8 +15,22 @@ type GazeData struct {
     Timestamp   int64   `json:"timestamp"`
     SectionID   *string `json:"sectionId,omitempty"`
     CurrentPage *string `json:"currentPage,omitempty"`
+    SessionID   string  `json:"sessionId,omitempty"`
+    // 샘플을 잡은 시점의 클라이언트 뷰포트 (좌표 범위 검증용)
+    ViewportWidth  int `json:"viewportWidth,omitempty"`
+    ViewportHeight int `json:"viewportHeight,omitempty"`
 }
 
+// 메인 서버(WebSocket)와 Consumer(Kafka)가 같은 방식으로 해석하도록 시선 데이터 디코딩은 여기 한 곳에서만 한다
+func DecodeGazeData(data []byte) (GazeData, error) {
+    var g GazeData
+    if err := json.Unmarshal(data, &g); err != nil {
+        return GazeData{}, err
+    }
+    return g, nil
+}
+
+// timestamp는 숫자 또는 숫자 문자열을 받는다. 해석할 수 없으면 0으로 두지 않고 에러를 낸다
 func (g *GazeData) UnmarshalJSON(data []byte) error {
     type Alias GazeData
     aux := &struct {
@@ -30,26 +47,162 @@ func (g *GazeData) UnmarshalJSON(data []byte) error {
     // timestamp 타입에 따라 처리
     switch v := aux.Timestamp.(type) {
     case float64:
+        if v != math.Trunc(v) || v < 0 || v >= math.MaxInt64 {
+            return fmt.Errorf("잘못된 timestamp: %v", v)
+        }
         g.Timestamp = int64(v)
     case string:
-        if ts, err := strconv.ParseInt(v, 10, 64); err == nil {
-            g.Timestamp = ts
+        ts, err := strconv.ParseInt(v, 10, 64)
+        if err != nil {
+            return fmt.Errorf("잘못된 timestamp 문자열 %q: %w", v, err)
+        }
+        if ts < 0 {
+            return fmt.Errorf("잘못된 timestamp: %d", ts)
         }
-    case int64:
-        g.Timestamp = v
+        g.Timestamp = ts
+    case nil:
+        return fmt.Errorf("timestamp 누락")
+    default:
+        return fmt.Errorf("지원하지 않는 timestamp 타입: %T", v)
     }
 
     return nil
 }
 
+// 여러 시선 샘플을 한 번에 보내는 메시지. 샘플마다 따로 검증하도록 원본 JSON으로 받는다
+type GazeBatchData struct {
+    Samples []json.RawMessage `json:"samples"`
+}
+
 // 페이지 변경 데이터
 type PageChangeData struct {
     CurrentPage string `json:"currentPage"`
     Timestamp   int64  `json:"timestamp"`
+    SessionID   string `json:"sessionId,omitempty"`
+}
+
+// 세션별·섹션별 누적 시선 체류 시간 (서버 기준값, 시선 고정 시간의 합)
+type SectionDwell struct {
+    SessionID     string `json:"sessionId"`
+    PageID        string `json:"pageId"`
+    SectionID     string `json:"sectionId"`
+    DwellMs       int64  `json:"dwellMs"`
+    FixationCount int    `json:"fixationCount"`
+    SampleCount   int    `json:"sampleCount"`
+}
+
+// 상담 세션 (고객 1회 방문)
+type Session struct {
+    ID           string     `json:"sessionId"`
+    BranchID     string     `json:"branchId"`
+    EmployeeID   string     `json:"employeeId"`
+    ProductID    string     `json:"productId"`
+    TermsVersion string     `json:"termsVersion"` // 세션 시작 시점의 약관 카탈로그 버전
+    StartedAt    time.Time  `json:"startedAt"`
+    EndedAt      *time.Time `json:"endedAt,omitempty"`
+}
+
+// 세션 시작 요청
+type SessionStartData struct {
+    BranchID   string `json:"branchId"`
+    EmployeeID string `json:"employeeId"`
+    ProductID  string `json:"productId"`
+}
+
+// 세션 참여 요청
+type SessionJoinData struct {
+    SessionID string `json:"sessionId"`
+}
+
+// 세션 종료 요청
+type SessionEndData struct {
+    SessionID string `json:"sessionId"`
+}
+
+// 보정 점 1회 클릭의 목표 좌표와 그 순간의 예측 좌표
+type CalibrationPoint struct {
+    TargetX    float64 `json:"targetX"`
+    TargetY    float64 `json:"targetY"`
+    PredictedX float64 `json:"predictedX"`
+    PredictedY float64 `json:"predictedY"`
+}
+
+// 고객 키오스크가 보정을 마치고 보내는 결과
+type CalibrationData struct {
+    Points       []CalibrationPoint `json:"points"`
+    Error        float64            `json:"error"` // 클라이언트가 계산한 오차 (px, 참고용)
+    ScreenWidth  int                `json:"screenWidth,omitempty"`
+    ScreenHeight int                `json:"screenHeight,omitempty"`
+    Timestamp    int64              `json:"timestamp"`
+}
+
+// 저장된 보정 결과와 서버 계산 품질 지표. 세션 중 재보정하면 여러 건이 쌓인다
+type Calibration struct {
+    ID              int64              `json:"id"`
+    SessionID       string             `json:"sessionId"`
+    Points          []CalibrationPoint `json:"points,omitempty"`
+    ReportedErrorPx float64            `json:"reportedErrorPx"`
+    AccuracyPx      float64            `json:"accuracyPx"`
+    PrecisionPx     float64            `json:"precisionPx"`
+    MaxErrorPx      float64            `json:"maxErrorPx"`
+    PointCount      int                `json:"pointCount"`
+    SampleCount     int                `json:"sampleCount"`
+    ScreenWidth     int                `json:"screenWidth,omitempty"`
+    ScreenHeight    int                `json:"screenHeight,omitempty"`
+    Timestamp       int64              `json:"timestamp"`
+    CreatedAt       time.Time          `json:"createdAt"`
+    // 조회 시 설정된 허용 오차로 채운다 (저장하지 않음)
+    LimitPx      float64 `json:"limitPx,omitempty"`
+    ExceedsLimit bool    `json:"exceedsLimit"`
+}
+
+// 저장된 세션 재생 요청. 배속 0이면 1배속
+type ReplayStartData struct {
+    SessionID  string  `json:"sessionId"`
+    Speed      float64 `json:"speed,omitempty"`
+    PositionMs int64   `json:"positionMs,omitempty"` // 세션 첫 기록 기준 시작 위치
+}
+
+// 재생 제어: pause, resume, seek(positionMs), speed(speed)
+type ReplayControlData struct {
+    Action     string  `json:"action"`
+    PositionMs int64   `json:"positionMs,omitempty"`
+    Speed      float64 `json:"speed,omitempty"`
+}
+
+// 재생 상태 알림
+type ReplayState struct {
+    SessionID  string  `json:"sessionId"`
+    State      string  `json:"state"` // playing / paused / ended
+    PositionMs int64   `json:"positionMs"`
+    DurationMs int64   `json:"durationMs"`
+    Speed      float64 `json:"speed"`
+}
+
+// 재생용으로 시간순 병합한 기록. Gaze와 Page 중 하나만 채워진다
+type ReplayEvent struct {
+    Timestamp int64
+    Gaze      *GazeData
+    Page      *PageChangeData
+}
+
+// WebSocket 연결 역할
+type Role string
+
+const (
+    RoleCustomer   Role = "customer"   // 고객 키오스크
+    RoleEmployee   Role = "employee"   // 창구 직원 대시보드
+    RoleSupervisor Role = "supervisor" // 관리자
+)
+
+// 연결 직후 첫 메시지로 보내는 핸드셰이크
+type HelloData struct {
+    Role  Role   `json:"role"`
+    Token string `json:"token"`
 }
 
 // WebSocket 메시지 구조체
 type WebSocketMessage struct {
     Type string      `json:"type"`
     Data interface{} `json:"data"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,214 @@
+package report
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "strings"
+    "time"
+
+    "shinhan-eyetracking/server/evaluation"
+
+    "github.com/go-pdf/fpdf"
+)
+
+const reportFont = "report"
+
+// 한글을 표시하려면 UTF-8 TTF 폰트 경로가 필요하다. 없으면 코어 폰트로 ID와 사유 코드만 출력
+func RenderPDF(r *Report, w io.Writer, fontPath string) error {
+    pdf := fpdf.New("P", "mm", "A4", "")
+    pdf.SetMargins(15, 15, 15)
+    pdf.SetAutoPageBreak(true, 15)
+    family := "Helvetica"
+    unicode := false
+    if fontPath != "" {
+        fontBytes, err := os.ReadFile(fontPath)
+        if err != nil {
+            return fmt.Errorf("보고서 폰트 읽기 실패: %w", err)
+        }
+        pdf.AddUTF8FontFromBytes(reportFont, "", fontBytes)
+        if pdf.Err() {
+            return fmt.Errorf("보고서 폰트 등록 실패 (%s): %w", fontPath, pdf.Error())
+        }
+        family = reportFont
+        unicode = true
+    }
+    // UTF-8 폰트가 없으면 비ASCII 문자열은 대체 문자열로 출력. 세션 데이터에서 온 값은 모두 이 함수를 거친다
+    text := func(s, fallback string) string {
+        if unicode || isASCII(s) {
+            return s
+        }
+        return fallback
+    }
+    sessionID := text(r.Session.ID, "-")
+    pdf.SetTitle("Evidence Report "+sessionID, true)
+
+    pdf.SetFooterFunc(func() {
+        pdf.SetY(-12)
+        pdf.SetFont(family, "", 8)
+        pdf.CellFormat(0, 5, fmt.Sprintf("session %s  |  page %d", sessionID, pdf.PageNo()), "", 0, "C", false, 0, "")
+    })
+    pdf.AddPage()
+
+    pdf.SetFont(family, "", 16)
+    pdf.CellFormat(0, 10, text("설명의무 이행 증적 보고서", "Evidence Report"), "", 1, "L", false, 0, "")
+    pdf.SetFont(family, "", 9)
+    pdf.CellFormat(0, 5, "Generated at "+r.GeneratedAt.Format(time.RFC3339), "", 1, "L", false, 0, "")
+    pdf.Ln(4)
+
+    // 세션 정보
+    heading(pdf, family, "Session")
+    endedAt := "-"
+    if r.Session.EndedAt != nil {
+        endedAt = r.Session.EndedAt.Format(time.RFC3339)
+    }
+    rows := [][2]string{
+        {"Session ID", sessionID},
+        {"Branch", text(r.Session.BranchID, "-")},
+        {"Employee", text(r.Session.EmployeeID, "-")},
+        {"Product", text(r.Session.ProductID, "-")},
+        {"Terms version", text(r.TermsVersion, "-")},
+        {"Started at", r.Session.StartedAt.Format(time.RFC3339)},
+        {"Ended at", endedAt},
+    }
+    for _, row := range rows {
+        keyValue(pdf, row[0], row[1])
+    }
+    pdf.Ln(3)
+
+    // 판정
+    heading(pdf, family, "Verdict")
+    result := "FAIL"
+    if r.Verdict.Passed {
+        result = "PASS"
+    }
+    keyValue(pdf, "Overall", result)
+    keyValue(pdf, "Evaluated at", r.Verdict.EvaluatedAt.Format(time.RFC3339))
+    keyValue(pdf, "Calibration", calibrationSummary(r.Verdict.Calibration))
+    if unicode {
+        for _, reason := range r.Verdict.Reasons {
+            pdf.MultiCell(0, 5, "- "+reason, "", "L", false)
+        }
+    } else {
+        // 한글 문구 대신 사유 코드와 섹션 ID로 출력
+        for _, f := range r.Verdict.Findings {
+            pdf.MultiCell(0, 5, "- "+text(findingLine(f), f.Code), "", "L", false)
+        }
+    }
+    pdf.Ln(3)
+
+    // 섹션별 열람 시간
+    heading(pdf, family, "Sections")
+    widths := []float64{32, 42, 16, 12, 20, 18, 22, 18}
+    headers := []string{"Page", "Section", "Priority", "Shown", "Required(s)", "Dwell(s)", "Pattern", "Result"}
+    pdf.SetFillColor(230, 230, 230)
+    for i, h := range headers {
+        pdf.CellFormat(widths[i], 6, h, "1", 0, "C", true, 0, "")
+    }
+    pdf.Ln(-1)
+    for _, s := range r.Sections {
+        shown := "no"
+        if s.Shown {
+            shown = "yes"
+        }
+        passed := "FAIL"
+        if s.Passed {
+            passed = "PASS"
+        }
+        cells := []string{
+            text(s.PageID, "-"),
+            text(s.Name, text(s.SectionID, "-")),
+            text(s.Priority, "-"),
+            shown,
+            fmt.Sprintf("%.1f", s.RequiredSec),
+            fmt.Sprintf("%.1f", s.DwellSec),
+            text(s.Pattern, "-"),
+            passed,
+        }
+        for i, c := range cells {
+            align := "L"
+            if i >= 3 && i <= 5 {
+                align = "R"
+            }
+            pdf.CellFormat(widths[i], 6, c, "1", 0, align, false, 0, "")
+        }
+        pdf.Ln(-1)
+    }
+    pdf.Ln(3)
+
+    // 페이지 표시 이력
+    heading(pdf, family, "Pages shown")
+    for _, p := range r.Pages {
+        shownAt := time.UnixMilli(p.ShownAt).Format(time.RFC3339)
+        keyValue(pdf, shownAt, text(p.PageID, "-"))
+    }
+    pdf.Ln(3)
+
+    // 무결성
+    heading(pdf, family, "Integrity")
+    valid := "INVALID"
+    if r.Integrity.Valid {
+        valid = "VALID"
+    }
+    keyValue(pdf, "Hash chain", valid)
+    keyValue(pdf, "Records", fmt.Sprintf("%d (head seq %d)", r.Integrity.RecordCount, r.Integrity.HeadSeq))
+    keyValue(pdf, "Head hash", text(r.Integrity.HeadHash, "-"))
+    for _, issue := range r.Integrity.Issues {
+        pdf.MultiCell(0, 5, fmt.Sprintf("- seq %d: %s", issue.Seq, text(issue.Problem, "integrity issue")), "", "L", false)
+    }
+
+    if pdf.Err() {
+        return pdf.Error()
+    }
+    return pdf.Output(w)
+}
+
+func heading(pdf *fpdf.Fpdf, family, title string) {
+    pdf.SetFont(family, "", 12)
+    pdf.CellFormat(0, 8, title, "B", 1, "L", false, 0, "")
+    pdf.SetFont(family, "", 9)
+    pdf.Ln(1)
+}
+
+func keyValue(pdf *fpdf.Fpdf, key, value string) {
+    pdf.CellFormat(40, 5, key, "", 0, "L", false, 0, "")
+    pdf.CellFormat(0, 5, value, "", 1, "L", false, 0, "")
+}
+
+func isASCII(s string) bool {
+    for i := 0; i < len(s); i++ {
+        if s[i] >= 0x80 {
+            return false
+        }
+    }
+    return true
+}
+
+func findingLine(f evaluation.Finding) string {
+    line := "FAIL "
+    if f.Warning {
+        line = "WARN "
+    }
+    line += f.Code
+    if f.SectionID != "" {
+        line += " " + f.PageID + "/" + f.SectionID
+    }
+    if len(f.Checks) > 0 {
+        line += ": " + strings.Join(f.Checks, ", ")
+    }
+    return line
+}
+
+func calibrationSummary(c evaluation.CalibrationCheck) string {
+    if !c.Present {
+        return "not recorded"
+    }
+    summary := fmt.Sprintf("accuracy %.1fpx, precision %.1fpx", c.AccuracyPx, c.PrecisionPx)
+    if c.LimitPx > 0 {
+        summary += fmt.Sprintf(" (limit %.1fpx)", c.LimitPx)
+    }
+    if c.Flagged {
+        summary += " - EXCEEDS LIMIT"
+    }
+    return summary
+}
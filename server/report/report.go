@@ -0,0 +1,71 @@
+package report
+
+import (
+    "time"
+
+    "shinhan-eyetracking/server/evaluation"
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+)
+
+// 고객에게 표시된 페이지
+type PageView struct {
+    PageID  string `json:"pageId"`
+    ShownAt int64  `json:"shownAt"` // 클라이언트 타임스탬프 (ms)
+}
+
+// 섹션별 열람 기록
+type SectionEntry struct {
+    PageID      string  `json:"pageId"`
+    SectionID   string  `json:"sectionId"`
+    Name        string  `json:"name"`
+    Priority    string  `json:"priority"`
+    Shown       bool    `json:"shown"`
+    RequiredSec float64 `json:"requiredSec"`
+    DwellSec    float64 `json:"dwellSec"`
+    Pattern     string  `json:"pattern,omitempty"` // reading / skimming / staring
+    Passed      bool    `json:"passed"`
+}
+
+// 상담 1건에 대한 설명의무 이행 증적 보고서
+type Report struct {
+    GeneratedAt  time.Time           `json:"generatedAt"`
+    Session      models.Session      `json:"session"`
+    TermsVersion string              `json:"termsVersion"`
+    Pages        []PageView          `json:"pages"`
+    Sections     []SectionEntry      `json:"sections"`
+    Verdict      *evaluation.Verdict `json:"verdict"`
+    Integrity    integrity.Report    `json:"integrity"`
+}
+
+func Build(session models.Session, pageChanges []models.PageChangeData, verdict *evaluation.Verdict, chain integrity.Report) *Report {
+    r := &Report{
+        GeneratedAt:  time.Now(),
+        Session:      session,
+        TermsVersion: session.TermsVersion,
+        Verdict:      verdict,
+        Integrity:    chain,
+    }
+
+    shown := make(map[string]bool)
+    for _, change := range pageChanges {
+        r.Pages = append(r.Pages, PageView{PageID: change.CurrentPage, ShownAt: change.Timestamp})
+        shown[change.CurrentPage] = true
+    }
+
+    for _, sv := range verdict.Sections {
+        r.Sections = append(r.Sections, SectionEntry{
+            PageID:      sv.PageID,
+            SectionID:   sv.SectionID,
+            Name:        sv.Name,
+            Priority:    string(sv.Priority),
+            Shown:       shown[sv.PageID],
+            RequiredSec: sv.RequiredSec,
+            DwellSec:    sv.DwellSec,
+            Pattern:     string(sv.Pattern),
+            Passed:      sv.Passed,
+        })
+    }
+
+    return r
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+    "errors"
+    "fmt"
+    "log"
+    "sync"
+
+    "shinhan-eyetracking/server/models"
+)
+
+// 대기열이 가득 찼을 때 기다릴지, 어떤 샘플을 버릴지
+type OverflowPolicy string
+
+const (
+    // 자리가 날 때까지 Publish를 기다리게 한다. 세션 스트림의 전송 주기가 밀리고, 스트림 대기 샘플이 상한에 닿으면
+    // 스트림이 새 샘플을 버린다 (수신은 막지 않는다)
+    OverflowBlock      OverflowPolicy = "block"
+    OverflowDropOldest OverflowPolicy = "drop_oldest" // 가장 오래 기다린 배치를 버리고 새 배치를 넣는다
+    OverflowDropNewest OverflowPolicy = "drop_newest" // 새 배치를 버린다
+)
+
+// 워커가 한 번에 합쳐 보내는 최대 샘플 수
+const asyncMaxSendSamples = 500
+
+// 대기열 초과로 버려진 배치의 전달 결과
+var ErrDropped = errors.New("전송 대기열 초과로 버려짐")
+
+// 배치 하나의 전달 결과. nil이면 하위 발행자(Kafka/스풀/내장 버스)가 받았다는 뜻.
+// 전송 워커에서 호출되므로 오래 걸리는 일을 하면 안 된다
+type DeliveryCallback func(samples []models.GazeData, err error)
+
+type AsyncStats struct {
+    Policy        OverflowPolicy `json:"policy"`
+    Capacity      int            `json:"capacity"` // 샘플 수
+    QueuedSamples int            `json:"queuedSamples"`
+    QueuedBatches int            `json:"queuedBatches"`
+    Enqueued      int64          `json:"enqueued"`
+    Delivered     int64          `json:"delivered"`
+    Failed        int64          `json:"failed"`
+    Dropped       int64          `json:"dropped"`
+}
+
+type asyncBatch struct {
+    samples    []models.GazeData
+    onDelivery DeliveryCallback
+}
+
+// 하위 발행자 앞에 두는 크기 제한 비동기 대기열. Publish는 block 정책에서만 자리가 날 때까지 기다리고,
+// 워커 하나가 쌓인 배치를 순서대로 꺼내 세션별로 보내므로 세션 안의 순서가 유지된다
+type AsyncPublisher struct {
+    next     EventPublisher
+    policy   OverflowPolicy
+    capacity int
+    maxSend  int
+
+    queue   []asyncBatch
+    queued  int // 대기 중인 샘플 수
+    closed  bool
+    mu      sync.Mutex
+    space   *sync.Cond // block 정책: 워커가 배치를 꺼내거나 대기열이 닫히면 깨운다
+    wake    chan struct{}
+    stopped chan struct{}
+
+    enqueued  int64
+    delivered int64
+    failed    int64
+    dropped   int64
+}
+
+func NewAsyncPublisher(next EventPublisher, capacity int, policy OverflowPolicy) (*AsyncPublisher, error) {
+    switch policy {
+    case OverflowBlock, OverflowDropOldest, OverflowDropNewest:
+    default:
+        return nil, fmt.Errorf("알 수 없는 대기열 초과 정책: %s", policy)
+    }
+    if capacity <= 0 {
+        return nil, fmt.Errorf("전송 대기열 크기는 0보다 커야 함: %d", capacity)
+    }
+
+    p := &AsyncPublisher{
+        next:     next,
+        policy:   policy,
+        capacity: capacity,
+        maxSend:  asyncMaxSendSamples,
+        wake:     make(chan struct{}, 1),
+        stopped:  make(chan struct{}),
+    }
+    p.space = sync.NewCond(&p.mu)
+    go p.run()
+
+    log.Printf("📤 비동기 전송 대기열: 샘플 %d건, 초과 시 %s", capacity, policy)
+    return p, nil
+}
+
+// 대기열에 넣고 반환한다 (block 정책이면 자리가 날 때까지 기다린다).
+// 버려진 배치(새 배치든 밀려난 배치든, 닫힌 뒤 들어온 배치든)는 onDelivery로 ErrDropped를 받는다
+func (p *AsyncPublisher) Publish(samples []models.GazeData, onDelivery DeliveryCallback) {
+    if len(samples) == 0 {
+        return
+    }
+
+    batch := asyncBatch{samples, onDelivery}
+    var dropped []asyncBatch
+    p.mu.Lock()
+    if p.policy == OverflowBlock {
+        // 대기열이 비어 있으면 상한보다 큰 배치도 받는다 (영원히 기다리지 않도록)
+        for !p.closed && p.queued > 0 && p.queued+len(samples) > p.capacity {
+            p.space.Wait()
+        }
+    }
+    closed := p.closed
+    switch {
+    case closed:
+        dropped = append(dropped, batch)
+    case p.policy == OverflowBlock:
+        p.enqueue(batch)
+    case p.policy == OverflowDropNewest && p.queued+len(samples) > p.capacity,
+        len(samples) > p.capacity:
+        dropped = append(dropped, batch)
+    default:
+        for p.queued+len(samples) > p.capacity {
+            oldest := p.queue[0]
+            p.queue = p.queue[1:]
+            p.queued -= len(oldest.samples)
+            dropped = append(dropped, oldest)
+        }
+        p.enqueue(batch)
+    }
+    for _, b := range dropped {
+        p.dropped += int64(len(b.samples))
+    }
+    p.mu.Unlock()
+
+    select {
+    case p.wake <- struct{}{}:
+    default:
+    }
+
+    err := ErrDropped
+    if closed {
+        err = fmt.Errorf("전송 대기열 종료됨: %w", ErrDropped)
+    }
+    for _, b := range dropped {
+        if b.onDelivery != nil {
+            b.onDelivery(b.samples, err)
+        }
+    }
+}
+
+// p.mu를 잡은 상태에서 호출
+func (p *AsyncPublisher) enqueue(b asyncBatch) {
+    p.queue = append(p.queue, b)
+    p.queued += len(b.samples)
+    p.enqueued += int64(len(b.samples))
+}
+
+// EventPublisher 구현. 결과를 받을 필요가 없는 호출자용
+func (p *AsyncPublisher) SendGazeBatch(samples []models.GazeData) error {
+    p.Publish(samples, nil)
+    return nil
+}
+
+func (p *AsyncPublisher) run() {
+    defer close(p.stopped)
+
+    for {
+        batches, closed := p.take()
+        if len(batches) == 0 {
+            if closed {
+                return
+            }
+            <-p.wake
+            continue
+        }
+        p.deliver(batches)
+    }
+}
+
+// 맨 앞부터 maxSend 샘플까지 배치를 꺼낸다 (첫 배치는 크기와 상관없이 꺼낸다)
+func (p *AsyncPublisher) take() ([]asyncBatch, bool) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    n, samples := 0, 0
+    for n < len(p.queue) && (n == 0 || samples+len(p.queue[n].samples) <= p.maxSend) {
+        samples += len(p.queue[n].samples)
+        n++
+    }
+    batches := p.queue[:n:n]
+    p.queue = p.queue[n:]
+    p.queued -= samples
+    if n > 0 {
+        p.space.Broadcast()
+    }
+    return batches, p.closed
+}
+
+// 꺼낸 배치를 세션별로 모아 세션마다 한 번씩 보낸다 (EventPublisher는 호출 하나에 세션 스트림 하나를 받는다).
+// 세션 안에서는 꺼낸 순서를 그대로 유지한다
+func (p *AsyncPublisher) deliver(batches []asyncBatch) {
+    var sessionIDs []string
+    bySession := make(map[string][]models.GazeData)
+    for _, b := range batches {
+        for _, data := range b.samples {
+            if _, exists := bySession[data.SessionID]; !exists {
+                sessionIDs = append(sessionIDs, data.SessionID)
+            }
+            bySession[data.SessionID] = append(bySession[data.SessionID], data)
+        }
+    }
+
+    errs := make(map[string]error)
+    var delivered, failed int64
+    for _, sessionID := range sessionIDs {
+        samples := bySession[sessionID]
+        if err := p.next.SendGazeBatch(samples); err != nil {
+            errs[sessionID] = err
+            failed += int64(len(samples))
+        } else {
+            delivered += int64(len(samples))
+        }
+    }
+
+    p.mu.Lock()
+    p.delivered += delivered
+    p.failed += failed
+    p.mu.Unlock()
+
+    for _, b := range batches {
+        if b.onDelivery == nil {
+            continue
+        }
+        // 배치에 섞인 세션 중 하나라도 실패했으면 실패로 알린다
+        var err error
+        for _, data := range b.samples {
+            if err = errs[data.SessionID]; err != nil {
+                break
+            }
+        }
+        b.onDelivery(b.samples, err)
+    }
+}
+
+// 새 배치는 더 받지 않고, 이미 받은 배치를 모두 보낸 뒤 하위 발행자를 닫는다.
+// 자리를 기다리던 Publish는 ErrDropped를 받고 돌아간다
+func (p *AsyncPublisher) Close() error {
+    p.mu.Lock()
+    if p.closed {
+        p.mu.Unlock()
+        return nil
+    }
+    p.closed = true
+    p.space.Broadcast()
+    p.mu.Unlock()
+
+    select {
+    case p.wake <- struct{}{}:
+    default:
+    }
+    <-p.stopped
+
+    return p.next.Close()
+}
+
+func (p *AsyncPublisher) Stats() PublisherStats {
+    stats := p.next.Stats()
+
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    stats.Async = &AsyncStats{
+        Policy:        p.policy,
+        Capacity:      p.capacity,
+        QueuedSamples: p.queued,
+        QueuedBatches: len(p.queue),
+        Enqueued:      p.enqueued,
+        Delivered:     p.delivered,
+        Failed:        p.failed,
+        Dropped:       p.dropped,
+    }
+    return stats
+}
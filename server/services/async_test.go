@@ -0,0 +1,175 @@
+package services
+
+import (
+    "errors"
+    "sync"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/models"
+)
+
+// 첫 전송에서 release가 닫힐 때까지 멈추는 발행자. 워커를 붙잡아 두고 대기열을 채우는 데 쓴다
+type gatedPublisher struct {
+    recordingPublisher
+    entered chan struct{} // 첫 전송이 시작되면 닫힌다
+    release chan struct{}
+    once    sync.Once
+}
+
+func newGatedPublisher() *gatedPublisher {
+    return &gatedPublisher{entered: make(chan struct{}), release: make(chan struct{})}
+}
+
+func (p *gatedPublisher) SendGazeBatch(samples []models.GazeData) error {
+    p.once.Do(func() { close(p.entered) })
+    <-p.release
+    return p.recordingPublisher.SendGazeBatch(samples)
+}
+
+// 받은 순서대로의 세션 ID
+func (p *recordingPublisher) sessions() []string {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    var result []string
+    for _, batch := range p.batches {
+        result = append(result, batch[0].SessionID)
+    }
+    return result
+}
+
+// 배치별 전달 결과
+type deliveryResults struct {
+    mu   sync.Mutex
+    errs map[string]error
+}
+
+func (r *deliveryResults) callback(samples []models.GazeData, err error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    r.errs[samples[0].SessionID] = err
+}
+
+func (r *deliveryResults) get(sessionID string) (error, bool) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    err, ok := r.errs[sessionID]
+    return err, ok
+}
+
+// 워커가 배치 a를 보내는 동안 b, c로 대기열(10건)을 채우고 d를 넣는다
+func TestAsyncPublisherOverflowPolicies(t *testing.T) {
+    cases := []struct {
+        policy    OverflowPolicy
+        waits     bool // d의 Publish가 자리가 날 때까지 기다리는지
+        dropped   string
+        delivered []string
+    }{
+        {OverflowBlock, true, "", []string{"a", "b", "c", "d"}},
+        {OverflowDropOldest, false, "b", []string{"a", "c", "d"}},
+        {OverflowDropNewest, false, "d", []string{"a", "b", "c"}},
+    }
+
+    for _, tc := range cases {
+        t.Run(string(tc.policy), func(t *testing.T) {
+            next := newGatedPublisher()
+            p, err := NewAsyncPublisher(next, 10, tc.policy)
+            if err != nil {
+                t.Fatalf("전송 대기열 생성 실패: %v", err)
+            }
+            results := &deliveryResults{errs: make(map[string]error)}
+
+            p.Publish(gazeSamples("a", 0, 5), results.callback)
+            <-next.entered
+            p.Publish(gazeSamples("b", 0, 5), results.callback)
+            p.Publish(gazeSamples("c", 0, 5), results.callback)
+
+            published := make(chan struct{})
+            go func() {
+                p.Publish(gazeSamples("d", 0, 5), results.callback)
+                close(published)
+            }()
+
+            select {
+            case <-published:
+                if tc.waits {
+                    t.Fatal("가득 찬 대기열에서 Publish가 기다리지 않음")
+                }
+            case <-time.After(100 * time.Millisecond):
+                if !tc.waits {
+                    t.Fatal("Publish가 대기열 자리를 기다림")
+                }
+            }
+
+            close(next.release)
+            <-published
+            if err := p.Close(); err != nil {
+                t.Fatalf("전송 대기열 종료 실패: %v", err)
+            }
+
+            sessions := next.sessions()
+            if len(sessions) != len(tc.delivered) {
+                t.Fatalf("전달된 세션 %v, 기대값 %v", sessions, tc.delivered)
+            }
+            for i, sessionID := range tc.delivered {
+                if sessions[i] != sessionID {
+                    t.Fatalf("전달된 세션 %v, 기대값 %v", sessions, tc.delivered)
+                }
+            }
+
+            for _, sessionID := range []string{"a", "b", "c", "d"} {
+                err, ok := results.get(sessionID)
+                if !ok {
+                    t.Fatalf("%s 배치의 전달 결과를 받지 못함", sessionID)
+                }
+                if wantDropped := sessionID == tc.dropped; errors.Is(err, ErrDropped) != wantDropped || !wantDropped && err != nil {
+                    t.Fatalf("%s 배치의 전달 결과: %v", sessionID, err)
+                }
+            }
+
+            stats := p.Stats().Async
+            wantDropped := int64(0)
+            if tc.dropped != "" {
+                wantDropped = 5
+            }
+            if stats.Dropped != wantDropped || stats.Delivered != int64(5*len(tc.delivered)) || stats.QueuedSamples != 0 {
+                t.Fatalf("통계 이상: %+v", stats)
+            }
+        })
+    }
+}
+
+// 닫힌 대기열에 넣은 배치는 ErrDropped로 알린다
+func TestAsyncPublisherRejectsAfterClose(t *testing.T) {
+    next := &recordingPublisher{}
+    p, err := NewAsyncPublisher(next, 10, OverflowBlock)
+    if err != nil {
+        t.Fatalf("전송 대기열 생성 실패: %v", err)
+    }
+    p.Publish(gazeSamples("a", 0, 5), nil)
+    if err := p.Close(); err != nil {
+        t.Fatalf("전송 대기열 종료 실패: %v", err)
+    }
+
+    results := &deliveryResults{errs: make(map[string]error)}
+    p.Publish(gazeSamples("b", 0, 5), results.callback)
+    if err, _ := results.get("b"); !errors.Is(err, ErrDropped) {
+        t.Fatalf("닫힌 뒤 넣은 배치의 전달 결과: %v", err)
+    }
+    // 닫기 전에 받은 배치는 모두 보낸다
+    if sessions := next.sessions(); len(sessions) != 1 || sessions[0] != "a" {
+        t.Fatalf("전달된 세션 %v", sessions)
+    }
+}
+
+func TestNewAsyncPublisherRejectsInvalidOptions(t *testing.T) {
+    if _, err := NewAsyncPublisher(&recordingPublisher{}, 10, "drop_random"); err == nil {
+        t.Fatal("알 수 없는 정책을 받아들임")
+    }
+    if _, err := NewAsyncPublisher(&recordingPublisher{}, 0, OverflowBlock); err == nil {
+        t.Fatal("크기 0인 대기열을 받아들임")
+    }
+}
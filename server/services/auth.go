@@ -0,0 +1,133 @@
+package services
+
+import (
+    "crypto/subtle"
+    "fmt"
+    "log"
+    "net/http"
+    "strings"
+
+    "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/models"
+)
+
+// 역할별로 보낼 수 있는 메시지 타입
+var sendPermissions = map[models.Role]map[string]bool{
+    models.RoleCustomer: {
+        "sessionJoin": true,
+        "calibration": true,
+        "gazeData":    true,
+        "gazeBatch":   true,
+        "pageChange":  true,
+    },
+    models.RoleEmployee: {
+        "sessionStart":   true,
+        "sessionJoin":    true,
+        "sessionEnd":     true,
+        "verdictRequest": true,
+    },
+    models.RoleSupervisor: {
+        "sessionJoin":    true,
+        "sessionEnd":     true,
+        "verdictRequest": true,
+        "replayStart":    true,
+        "replayControl":  true,
+        "replayStop":     true,
+    },
+}
+
+// 역할별로 받을 수 있는 메시지 타입. 고객 키오스크는 시선/페이지 브로드캐스트를 받지 않는다
+var receivePermissions = map[models.Role]map[string]bool{
+    models.RoleCustomer: {
+        "sessionJoined":     true,
+        "sessionEnded":      true,
+        "calibrationResult": true, // 허용 오차 초과 시 재보정 안내
+        "gazeRejected":      true, // 보낸 시선 샘플의 거부 사유
+    },
+    models.RoleEmployee: {
+        "sessionStarted":    true,
+        "sessionJoined":     true,
+        "sessionEnded":      true,
+        "calibrationResult": true,
+        "gazeData":          true,
+        "pageChange":        true,
+        "dwellUpdate":       true,
+        "verdict":           true,
+        "clientCount":       true,
+    },
+    models.RoleSupervisor: {
+        "sessionJoined":     true,
+        "sessionEnded":      true,
+        "calibrationResult": true,
+        "gazeData":          true,
+        "pageChange":        true,
+        "dwellUpdate":       true,
+        "verdict":           true,
+        "clientCount":       true,
+        "replayState":       true,
+    },
+}
+
+// 역할과 무관하게 항상 주고받는 메시지
+var controlMessages = map[string]bool{
+    "hello":    true,
+    "helloAck": true,
+    "error":    true,
+}
+
+type AuthService struct {
+    tokens map[models.Role]string
+}
+
+func NewAuthService(cfg *config.Config) *AuthService {
+    a := &AuthService{
+        tokens: map[models.Role]string{
+            models.RoleCustomer:   cfg.CustomerToken,
+            models.RoleEmployee:   cfg.EmployeeToken,
+            models.RoleSupervisor: cfg.SupervisorToken,
+        },
+    }
+    for _, role := range []models.Role{models.RoleCustomer, models.RoleEmployee, models.RoleSupervisor} {
+        if a.tokens[role] == "" {
+            log.Printf("⚠️ %s 토큰이 설정되지 않아 해당 역할 접속 비활성화", role)
+        }
+    }
+    return a
+}
+
+// 핸드셰이크에서 선언한 역할과 토큰을 검증
+func (a *AuthService) Authenticate(hello models.HelloData) error {
+    expected, ok := a.tokens[hello.Role]
+    if !ok {
+        return fmt.Errorf("알 수 없는 역할: %q", hello.Role)
+    }
+    if expected == "" {
+        return fmt.Errorf("비활성화된 역할: %s", hello.Role)
+    }
+    if subtle.ConstantTimeCompare([]byte(expected), []byte(hello.Token)) != 1 {
+        return fmt.Errorf("토큰 불일치: %s", hello.Role)
+    }
+    return nil
+}
+
+// HTTP API 요청의 Authorization: Bearer 토큰이 roles 중 하나의 토큰인지 확인
+func (a *AuthService) AuthenticateRequest(r *http.Request, roles ...models.Role) (models.Role, error) {
+    token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+    if !ok || token == "" {
+        return "", fmt.Errorf("인증 토큰 없음")
+    }
+    for _, role := range roles {
+        if a.Authenticate(models.HelloData{Role: role, Token: token}) == nil {
+            return role, nil
+        }
+    }
+    return "", fmt.Errorf("허용되지 않은 토큰")
+}
+
+func CanSend(role models.Role, messageType string) bool {
+    return controlMessages[messageType] || sendPermissions[role][messageType]
+}
+
+func CanReceive(role models.Role, messageType string) bool {
+    return controlMessages[messageType] || receivePermissions[role][messageType]
+}
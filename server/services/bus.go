@@ -0,0 +1,169 @@
+package services
+
+import (
+    "fmt"
+    "log"
+    "sync"
+    "time"
+
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/models"
+)
+
+const (
+    // 저장 대기 배치 상한. 세션 스트림이 0.1초마다 배치를 넣으므로 수십 세션 기준 수십 초 분량
+    localBusQueueSize = 1024
+    // 저장 실패 시 재시도 간격 (Consumer와 같은 지수 증가)
+    localBusMinRetryDelay = 500 * time.Millisecond
+    localBusMaxRetryDelay = 30 * time.Second
+    // 이 횟수만큼 실패하고 DB가 살아 있으면 한 건씩 저장해 문제 샘플을 골라낸다
+    localBusIsolateAfterAttempts = 3
+)
+
+// Kafka 없이 프로세스 안에서 저장까지 처리하는 버스. 내장 저장 워커가 Consumer 역할을 한다.
+// 단일 노드 지점 배포나 개발 환경용이며, 프로세스가 죽으면 아직 저장하지 않은 샘플은 사라진다
+type LocalBus struct {
+    db        database.Store
+    batchSize int
+    queue     chan []models.GazeData
+    closed    bool
+    mu        sync.RWMutex
+    stopping  chan struct{}
+    stopOnce  sync.Once
+    finished  chan struct{}
+}
+
+func NewLocalBus(db database.Store, batchSize int) *LocalBus {
+    if batchSize <= 0 {
+        batchSize = 500
+    }
+    bus := &LocalBus{
+        db:        db,
+        batchSize: batchSize,
+        queue:     make(chan []models.GazeData, localBusQueueSize),
+        stopping:  make(chan struct{}),
+        finished:  make(chan struct{}),
+    }
+    go bus.runStorageWorker()
+
+    log.Println("✅ 내장 이벤트 버스 시작 (Kafka 없이 직접 저장)")
+    return bus
+}
+
+// 큐가 가득 차면 저장 워커가 자리를 비울 때까지 기다린다. 호출자(전송 대기열 워커나 세션 스트림)로
+// 역압이 전달되고 샘플은 버리지 않는다. 기다리는 중에 버스가 닫히면 실패를 반환한다
+func (b *LocalBus) SendGazeBatch(samples []models.GazeData) error {
+    b.mu.RLock()
+    defer b.mu.RUnlock()
+
+    if b.closed {
+        return fmt.Errorf("이벤트 버스 종료됨 (%d건)", len(samples))
+    }
+    select {
+    case b.queue <- samples:
+        return nil
+    case <-b.stopping:
+        return fmt.Errorf("저장 대기 중 이벤트 버스 종료됨 (%d건)", len(samples))
+    }
+}
+
+// 새 샘플을 더 받지 않고, 남은 샘플을 저장한 뒤 반환
+func (b *LocalBus) Close() error {
+    // 자리를 기다리는 SendGazeBatch가 읽기 잠금을 놓도록 먼저 깨운다
+    b.stopOnce.Do(func() { close(b.stopping) })
+
+    b.mu.Lock()
+    if b.closed {
+        b.mu.Unlock()
+        return nil
+    }
+    b.closed = true
+    close(b.queue)
+    b.mu.Unlock()
+
+    <-b.finished
+    return nil
+}
+
+func (b *LocalBus) Stats() PublisherStats {
+    return PublisherStats{Backend: EventBusLocal, Queued: len(b.queue)}
+}
+
+func (b *LocalBus) runStorageWorker() {
+    defer close(b.finished)
+
+    for samples := range b.queue {
+        // 이미 쌓여 있는 배치를 batchSize까지 모아 한 트랜잭션으로 저장
+        batch := append([]models.GazeData(nil), samples...)
+    collect:
+        for len(batch) < b.batchSize {
+            select {
+            case more, ok := <-b.queue:
+                if !ok {
+                    break collect
+                }
+                batch = append(batch, more...)
+            default:
+                break collect
+            }
+        }
+        b.store(batch)
+    }
+}
+
+func (b *LocalBus) store(batch []models.GazeData) {
+    // Consumer가 DLQ로 보내는 것과 같은 기준: 세션 ID가 없으면 체인에 넣을 수 없다
+    valid := batch[:0]
+    for _, data := range batch {
+        if data.SessionID != "" {
+            valid = append(valid, data)
+        }
+    }
+    if skipped := len(batch) - len(valid); skipped > 0 {
+        log.Printf("⚠️ 세션 ID 없는 시선 샘플 %d건 버림", skipped)
+    }
+
+    delay := localBusMinRetryDelay
+    for attempt := 1; len(valid) > 0; attempt++ {
+        err := b.db.SaveGazeBatch(valid)
+        if err == nil {
+            return
+        }
+        log.Printf("💾 배치 저장 실패 (%d회): %v", attempt, err)
+
+        if attempt >= localBusIsolateAfterAttempts && b.db.Ping() == nil {
+            valid = b.isolatePoison(valid)
+            continue
+        }
+
+        // 종료 중이면 더 기다리지 않는다
+        select {
+        case <-b.stopping:
+            log.Printf("❌ 종료 중 저장 실패로 시선 샘플 %d건 유실", len(valid))
+            return
+        case <-time.After(delay):
+        }
+        delay *= 2
+        if delay > localBusMaxRetryDelay {
+            delay = localBusMaxRetryDelay
+        }
+    }
+}
+
+// 한 건씩 저장해 보고 계속 실패하는 샘플만 버린다. 저장에 성공한 샘플은 결과에서 뺀다
+func (b *LocalBus) isolatePoison(batch []models.GazeData) []models.GazeData {
+    var remaining []models.GazeData
+    poisoned := 0
+    for i, data := range batch {
+        if err := b.db.SaveGazeBatch([]models.GazeData{data}); err != nil {
+            if b.db.Ping() != nil {
+                // 도중에 DB가 끊기면 나머지는 다시 배치로 재시도
+                return append(remaining, batch[i:]...)
+            }
+            log.Printf("☠️ 저장할 수 없는 시선 샘플 버림 [%s@%d]: %v", data.SessionID, data.Timestamp, err)
+            poisoned++
+        }
+    }
+    log.Printf("🔍 문제 샘플 분리 완료: %d건 버림", poisoned)
+    return remaining
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+    "errors"
+    "reflect"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+)
+
+// release가 닫힐 때까지 저장을 멈추는 저장소
+type gatedStore struct {
+    *database.MemoryStore
+    entered chan struct{}
+    release chan struct{}
+}
+
+func (s *gatedStore) SaveGazeBatch(samples []models.GazeData) error {
+    select {
+    case s.entered <- struct{}{}:
+    default:
+    }
+    <-s.release
+    return s.MemoryStore.SaveGazeBatch(samples)
+}
+
+// 저장 워커를 붙잡아 두고 큐를 가득 채운다. 반환값은 큐가 찬 뒤 보낸 샘플의 전송 결과
+func fillLocalBus(t *testing.T) (*LocalBus, *gatedStore, chan error) {
+    t.Helper()
+
+    db := &gatedStore{MemoryStore: database.NewMemoryStore(), entered: make(chan struct{}, 1), release: make(chan struct{})}
+    bus := NewLocalBus(db, 1)
+
+    if err := bus.SendGazeBatch(gazeSamples("s1", 0, 1)); err != nil {
+        t.Fatalf("전송 실패: %v", err)
+    }
+    <-db.entered
+    for i := 1; i <= localBusQueueSize; i++ {
+        if err := bus.SendGazeBatch(gazeSamples("s1", i, 1)); err != nil {
+            t.Fatalf("%d번째 전송 실패: %v", i, err)
+        }
+    }
+
+    sent := make(chan error, 1)
+    go func() { sent <- bus.SendGazeBatch(gazeSamples("s1", localBusQueueSize+1, 1)) }()
+    select {
+    case err := <-sent:
+        t.Fatalf("가득 찬 큐에서 기다리지 않음: %v", err)
+    case <-time.After(100 * time.Millisecond):
+    }
+    return bus, db, sent
+}
+
+// 큐가 가득 차면 버리지 않고 자리가 날 때까지 기다린다
+func TestLocalBusBlocksUntilQueueHasRoom(t *testing.T) {
+    bus, db, sent := fillLocalBus(t)
+
+    close(db.release)
+    if err := <-sent; err != nil {
+        t.Fatalf("자리가 난 뒤 전송 실패: %v", err)
+    }
+    if err := bus.Close(); err != nil {
+        t.Fatalf("내장 버스 종료 실패: %v", err)
+    }
+
+    records, err := db.GetChainRecords("s1")
+    if err != nil {
+        t.Fatalf("체인 레코드 조회 실패: %v", err)
+    }
+    if want := localBusQueueSize + 2; len(records) != want {
+        t.Fatalf("저장된 샘플 %d건, 기대값 %d건", len(records), want)
+    }
+}
+
+// 기다리는 중에 버스가 닫히면 실패를 반환하고, 큐에 있던 샘플은 저장한다
+func TestLocalBusCloseWakesBlockedSender(t *testing.T) {
+    bus, db, sent := fillLocalBus(t)
+
+    closed := make(chan error, 1)
+    go func() { closed <- bus.Close() }()
+    select {
+    case err := <-sent:
+        if err == nil {
+            t.Fatal("닫힌 버스로 전송이 성공함")
+        }
+    case <-time.After(2 * time.Second):
+        t.Fatal("버스를 닫아도 전송이 계속 기다림")
+    }
+
+    close(db.release)
+    if err := <-closed; err != nil {
+        t.Fatalf("내장 버스 종료 실패: %v", err)
+    }
+    records, err := db.GetChainRecords("s1")
+    if err != nil {
+        t.Fatalf("체인 레코드 조회 실패: %v", err)
+    }
+    if want := localBusQueueSize + 1; len(records) != want {
+        t.Fatalf("저장된 샘플 %d건, 기대값 %d건", len(records), want)
+    }
+}
+
+// EVENT_BUS=local이면 브로커 없이 내장 버스를 쓰고, 모르는 값은 시작 단계에서 거부한다
+func TestNewEventPublisherSelectsBus(t *testing.T) {
+    publisher, err := NewEventPublisher(&config.Config{EventBus: EventBusLocal, ConsumerBatchSize: 10}, database.NewMemoryStore())
+    if err != nil {
+        t.Fatalf("내장 버스 생성 실패: %v", err)
+    }
+    defer publisher.Close()
+
+    bus, ok := publisher.(*LocalBus)
+    if !ok {
+        t.Fatalf("발행자 = %T, 기대값 *LocalBus", publisher)
+    }
+    if bus.batchSize != 10 || publisher.Stats().Backend != EventBusLocal {
+        t.Fatalf("배치 크기 %d, 백엔드 %q", bus.batchSize, publisher.Stats().Backend)
+    }
+
+    if _, err := NewEventPublisher(&config.Config{EventBus: "rabbitmq"}, database.NewMemoryStore()); err == nil {
+        t.Fatal("알 수 없는 이벤트 버스가 허용됨")
+    }
+}
+
+// 특정 timestamp 샘플이 들어 있는 배치는 저장을 거부하는 저장소
+type poisonStore struct {
+    *database.MemoryStore
+    poison int64
+}
+
+func (s *poisonStore) SaveGazeBatch(samples []models.GazeData) error {
+    for _, data := range samples {
+        if data.Timestamp == s.poison {
+            return errors.New("제약 조건 위반")
+        }
+    }
+    return s.MemoryStore.SaveGazeBatch(samples)
+}
+
+// DB는 살아 있는데 배치가 계속 실패하면 문제 샘플만 버리고 나머지는 저장한다. 세션 없는 샘플은 저장하지 않는다
+func TestLocalBusIsolatesPoisonedSample(t *testing.T) {
+    samples := gazeSamples("s1", 0, 5)
+    db := &poisonStore{MemoryStore: database.NewMemoryStore(), poison: samples[2].Timestamp}
+    bus := NewLocalBus(db, 100)
+
+    orphan := gazeSamples("", 10, 1)
+    if err := bus.SendGazeBatch(append(samples, orphan...)); err != nil {
+        t.Fatalf("전송 실패: %v", err)
+    }
+
+    // 재시도 대기 중에 닫으면 저장을 포기하므로 분리가 끝날 때까지 기다린다
+    var records []integrity.Record
+    deadline := time.Now().Add(5 * time.Second)
+    for len(records) < len(samples)-1 && time.Now().Before(deadline) {
+        time.Sleep(50 * time.Millisecond)
+        var err error
+        if records, err = db.GetChainRecords("s1"); err != nil {
+            t.Fatalf("체인 레코드 조회 실패: %v", err)
+        }
+    }
+    if err := bus.Close(); err != nil {
+        t.Fatalf("내장 버스 종료 실패: %v", err)
+    }
+
+    var got []int64
+    for _, r := range records {
+        got = append(got, r.Timestamp)
+    }
+    want := []int64{samples[0].Timestamp, samples[1].Timestamp, samples[3].Timestamp, samples[4].Timestamp}
+    if !reflect.DeepEqual(got, want) {
+        t.Fatalf("저장된 timestamp = %v, 기대값 %v", got, want)
+    }
+    if all, _ := db.GetRecentGazeData(100, ""); len(all) != len(want) {
+        t.Fatalf("전체 저장 샘플 %d건, 기대값 %d건 (세션 없는 샘플 제외)", len(all), len(want))
+    }
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+    "fmt"
+    "log"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/models"
+)
+
+// 시선 보정 결과 저장 및 품질 평가
+type CalibrationService struct {
+    db      database.Store
+    limitPx float64 // 허용 평균 오차 (0이면 확인하지 않음)
+}
+
+func NewCalibrationService(db database.Store, limitPx float64) *CalibrationService {
+    return &CalibrationService{db: db, limitPx: limitPx}
+}
+
+func (s *CalibrationService) RecordCalibration(sessionID string, data models.CalibrationData) (*models.Calibration, error) {
+    quality, err := analysis.EvaluateCalibration(data.Points)
+    if err != nil {
+        return nil, err
+    }
+
+    c := &models.Calibration{
+        SessionID:       sessionID,
+        Points:          data.Points,
+        ReportedErrorPx: data.Error,
+        AccuracyPx:      quality.AccuracyPx,
+        PrecisionPx:     quality.PrecisionPx,
+        MaxErrorPx:      quality.MaxErrorPx,
+        PointCount:      quality.PointCount,
+        SampleCount:     quality.SampleCount,
+        ScreenWidth:     data.ScreenWidth,
+        ScreenHeight:    data.ScreenHeight,
+        Timestamp:       data.Timestamp,
+    }
+    if err := s.db.SaveCalibration(c); err != nil {
+        return nil, fmt.Errorf("보정 결과 저장 실패: %w", err)
+    }
+    s.applyLimit(c)
+
+    if c.ExceedsLimit {
+        log.Printf("⚠️ 보정 오차 초과 [%s]: 정확도 %.1fpx / 허용 %.1fpx", sessionID, c.AccuracyPx, s.limitPx)
+    } else {
+        log.Printf("🎯 보정 결과 저장 [%s]: 정확도 %.1fpx, 정밀도 %.1fpx (%d점)",
+            sessionID, c.AccuracyPx, c.PrecisionPx, c.PointCount)
+    }
+    return c, nil
+}
+
+func (s *CalibrationService) GetCalibrations(sessionID string) ([]models.Calibration, error) {
+    calibrations, err := s.db.GetCalibrations(sessionID)
+    if err != nil {
+        return nil, err
+    }
+    for i := range calibrations {
+        s.applyLimit(&calibrations[i])
+    }
+    return calibrations, nil
+}
+
+// 세션의 마지막 보정 결과. 보정 기록이 없으면 nil
+func (s *CalibrationService) GetLatestCalibration(sessionID string) (*models.Calibration, error) {
+    c, err := s.db.GetLatestCalibration(sessionID)
+    if err != nil || c == nil {
+        return c, err
+    }
+    s.applyLimit(c)
+    return c, nil
+}
+
+func (s *CalibrationService) LimitPx() float64 {
+    return s.limitPx
+}
+
+func (s *CalibrationService) applyLimit(c *models.Calibration) {
+    c.LimitPx = s.limitPx
+    c.ExceedsLimit = s.limitPx > 0 && c.AccuracyPx > s.limitPx
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+    "math"
+    "testing"
+
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/models"
+)
+
+// 목표점 두 곳에 대해 예측점이 각각 dx만큼 오른쪽으로 빗나간 보정 결과
+func calibrationWithError(dx float64) models.CalibrationData {
+    return models.CalibrationData{
+        Points: []models.CalibrationPoint{
+            {TargetX: 100, TargetY: 100, PredictedX: 100 + dx, PredictedY: 100},
+            {TargetX: 500, TargetY: 300, PredictedX: 500 + dx, PredictedY: 300},
+        },
+        Error:     1, // 클라이언트 보고값은 판정에 쓰지 않는다
+        Timestamp: 1726000000000,
+    }
+}
+
+// 보정 오차는 보정 점에서 서버가 계산하고, 허용치를 넘으면 저장 결과와 조회 결과 모두 초과로 표시한다
+func TestCalibrationFlagsAccuracyOverLimit(t *testing.T) {
+    cases := []struct {
+        name    string
+        limitPx float64
+        dx      float64
+        exceeds bool
+    }{
+        {"허용치 이내", 50, 30, false},
+        {"허용치와 같음", 50, 50, false},
+        {"허용치 초과", 50, 80, true},
+        {"허용치 없음", 0, 500, false},
+    }
+
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            service := NewCalibrationService(database.NewMemoryStore(), c.limitPx)
+
+            recorded, err := service.RecordCalibration("s1", calibrationWithError(c.dx))
+            if err != nil {
+                t.Fatalf("보정 결과 기록 실패: %v", err)
+            }
+            if math.Abs(recorded.AccuracyPx-c.dx) > 1e-9 {
+                t.Fatalf("정확도 = %.2fpx, 기대값 %.2fpx", recorded.AccuracyPx, c.dx)
+            }
+            if recorded.ExceedsLimit != c.exceeds || recorded.LimitPx != c.limitPx {
+                t.Fatalf("기록 결과 초과=%v 허용치=%.0f, 기대값 %v / %.0f",
+                    recorded.ExceedsLimit, recorded.LimitPx, c.exceeds, c.limitPx)
+            }
+
+            latest, err := service.GetLatestCalibration("s1")
+            if err != nil || latest == nil {
+                t.Fatalf("마지막 보정 조회 실패: %v, %v", latest, err)
+            }
+            if latest.ExceedsLimit != c.exceeds {
+                t.Fatalf("조회 결과 초과=%v, 기대값 %v", latest.ExceedsLimit, c.exceeds)
+            }
+        })
+    }
+}
+
+// 재보정하면 마지막 결과 기준으로 판단하고, 좌표가 잘못된 보정은 저장하지 않는다
+func TestCalibrationLatestResultWins(t *testing.T) {
+    service := NewCalibrationService(database.NewMemoryStore(), 50)
+
+    if _, err := service.RecordCalibration("s1", calibrationWithError(90)); err != nil {
+        t.Fatalf("첫 보정 기록 실패: %v", err)
+    }
+    retry := calibrationWithError(10)
+    retry.Timestamp++
+    if _, err := service.RecordCalibration("s1", retry); err != nil {
+        t.Fatalf("재보정 기록 실패: %v", err)
+    }
+
+    invalid := calibrationWithError(0)
+    invalid.Points[0].PredictedX = math.NaN()
+    if _, err := service.RecordCalibration("s1", invalid); err == nil {
+        t.Fatal("NaN 좌표 보정이 기록됨")
+    }
+
+    latest, err := service.GetLatestCalibration("s1")
+    if err != nil || latest == nil {
+        t.Fatalf("마지막 보정 조회 실패: %v, %v", latest, err)
+    }
+    if latest.ExceedsLimit || math.Abs(latest.AccuracyPx-10) > 1e-9 {
+        t.Fatalf("마지막 보정 = %.1fpx (초과 %v), 기대값 재보정 결과 10px", latest.AccuracyPx, latest.ExceedsLimit)
+    }
+
+    all, err := service.GetCalibrations("s1")
+    if err != nil {
+        t.Fatalf("보정 목록 조회 실패: %v", err)
+    }
+    if len(all) != 2 || !all[0].ExceedsLimit || all[1].ExceedsLimit {
+        t.Fatalf("보정 목록 = %+v, 기대값 초과 1건 뒤 정상 1건", all)
+    }
+}
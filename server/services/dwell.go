@@ -0,0 +1,207 @@
+package services
+
+import (
+    "sort"
+    "sync"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/models"
+)
+
+type dwellKey struct {
+    pageID    string
+    sectionID string
+}
+
+type sessionDwell struct {
+    totals   map[dwellKey]*models.SectionDwell
+    dirty    bool
+    finished bool // finish가 최종값을 가져간 뒤. 더 쌓지도 저장하지도 않는다
+
+    // 스냅샷과 저장을 한 묶음으로 직렬화한다. 저장은 덮어쓰기(upsert)라서
+    // 먼저 뜬 스냅샷이 나중에 저장되면 최종값이 작은 값으로 되돌아간다
+    saveMu sync.Mutex
+}
+
+// 세션별·섹션별 체류 시간 누적기. 원시 샘플이 아니라 검출된 시선 고정 시간을 더한다.
+// 세션은 restore로만 만든다. 이미 내린 세션에 늦게 온 값이 0부터 다시 쌓여 저장값을 덮어쓰지 않도록
+type dwellTracker struct {
+    mu       sync.Mutex
+    sessions map[string]*sessionDwell
+}
+
+func newDwellTracker() *dwellTracker {
+    return &dwellTracker{
+        sessions: make(map[string]*sessionDwell),
+    }
+}
+
+// 재시작 등으로 메모리에 없는 세션은 저장된 누적값에서 이어서 센다
+func (t *dwellTracker) restore(sessionID string, saved []models.SectionDwell) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    if _, exists := t.sessions[sessionID]; exists {
+        return
+    }
+    state := &sessionDwell{totals: make(map[dwellKey]*models.SectionDwell)}
+    for _, d := range saved {
+        d := d
+        state.totals[dwellKey{d.PageID, d.SectionID}] = &d
+    }
+    t.sessions[sessionID] = state
+}
+
+func (t *dwellTracker) has(sessionID string) bool {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    _, exists := t.sessions[sessionID]
+    return exists
+}
+
+// 원시 샘플 수만 센다 (체류 시간은 addFixation에서)
+func (t *dwellTracker) addSample(data models.GazeData) {
+    section := stringValue(data.SectionID)
+    if section == "" {
+        return
+    }
+
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    state, exists := t.sessions[data.SessionID]
+    if !exists || state.finished {
+        return
+    }
+    state.total(data.SessionID, stringValue(data.CurrentPage), section).SampleCount++
+    state.dirty = true
+}
+
+func (t *dwellTracker) addFixation(f analysis.Fixation) {
+    if f.SectionID == "" {
+        return
+    }
+
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    state, exists := t.sessions[f.SessionID]
+    if !exists || state.finished {
+        return
+    }
+    total := state.total(f.SessionID, f.PageID, f.SectionID)
+    total.DwellMs += f.DurationMs
+    total.FixationCount++
+    state.dirty = true
+}
+
+// 변경된 세션마다 누적값 스냅샷을 떠서 save에 넘기고 dirty 표시를 지운다.
+// 세션별로 스냅샷과 저장 사이에 finish가 끼어들지 못한다
+func (t *dwellTracker) flushDirty(save func(sessionID string, dwell []models.SectionDwell)) {
+    t.mu.Lock()
+    dirty := make(map[string]*sessionDwell)
+    for sessionID, state := range t.sessions {
+        if state.dirty {
+            dirty[sessionID] = state
+        }
+    }
+    t.mu.Unlock()
+
+    for sessionID, state := range dirty {
+        state.saveMu.Lock()
+        t.mu.Lock()
+        var dwell []models.SectionDwell
+        if state.dirty && !state.finished {
+            dwell = state.snapshot()
+            state.dirty = false
+        }
+        t.mu.Unlock()
+
+        if dwell != nil {
+            save(sessionID, dwell)
+        }
+        state.saveMu.Unlock()
+    }
+}
+
+func (t *dwellTracker) snapshot(sessionID string) ([]models.SectionDwell, bool) {
+    t.mu.Lock()
+    defer t.mu.Unlock()
+
+    state, exists := t.sessions[sessionID]
+    if !exists {
+        return nil, false
+    }
+    return state.snapshot(), true
+}
+
+// 세션 종료 시 최종값을 save로 저장하고 메모리에서 제거한다. 진행 중인 주기 저장이 끝난 뒤에 저장하므로
+// 최종값이 마지막으로 남는다. 저장이 끝날 때까지 세션을 맵에 남겨 두어 그사이 들어온 샘플이
+// 저장 전의 값으로 restore되지 않게 한다
+func (t *dwellTracker) finish(sessionID string, save func(dwell []models.SectionDwell) error) ([]models.SectionDwell, error) {
+    t.mu.Lock()
+    state, exists := t.sessions[sessionID]
+    t.mu.Unlock()
+    if !exists {
+        return nil, nil
+    }
+
+    state.saveMu.Lock()
+    defer state.saveMu.Unlock()
+
+    t.mu.Lock()
+    if state.finished {
+        t.mu.Unlock()
+        return nil, nil
+    }
+    state.finished = true
+    dwell := state.snapshot()
+    t.mu.Unlock()
+
+    var err error
+    if len(dwell) > 0 {
+        err = save(dwell)
+    }
+
+    t.mu.Lock()
+    if t.sessions[sessionID] == state {
+        delete(t.sessions, sessionID)
+    }
+    t.mu.Unlock()
+    return dwell, err
+}
+
+func (s *sessionDwell) total(sessionID, pageID, sectionID string) *models.SectionDwell {
+    key := dwellKey{pageID, sectionID}
+    total, exists := s.totals[key]
+    if !exists {
+        total = &models.SectionDwell{
+            SessionID: sessionID,
+            PageID:    pageID,
+            SectionID: sectionID,
+        }
+        s.totals[key] = total
+    }
+    return total
+}
+
+func (s *sessionDwell) snapshot() []models.SectionDwell {
+    result := make([]models.SectionDwell, 0, len(s.totals))
+    for _, total := range s.totals {
+        result = append(result, *total)
+    }
+    sort.Slice(result, func(i, j int) bool {
+        if result[i].PageID != result[j].PageID {
+            return result[i].PageID < result[j].PageID
+        }
+        return result[i].SectionID < result[j].SectionID
+    })
+    return result
+}
+
+func stringValue(s *string) string {
+    if s == nil {
+        return ""
+    }
+    return *s
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+    "sync"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/models"
+)
+
+func addDwellFixation(tracker *dwellTracker, sessionID string, durationMs int64) {
+    tracker.addFixation(analysis.Fixation{
+        SessionID:  sessionID,
+        PageID:     "productJoin",
+        SectionID:  "risk-warning",
+        DurationMs: durationMs,
+    })
+}
+
+// 주기 저장이 스냅샷을 뜬 뒤 저장 전에 멈춘 사이 세션이 끝나도, 최종값이 마지막으로 저장된다
+func TestDwellFinishWaitsForInFlightFlush(t *testing.T) {
+    tracker := newDwellTracker()
+    tracker.restore("s1", nil)
+    addDwellFixation(tracker, "s1", 100)
+
+    var mu sync.Mutex
+    var saved []int64 // 저장된 순서대로의 DwellMs
+    record := func(dwell []models.SectionDwell) {
+        mu.Lock()
+        defer mu.Unlock()
+        saved = append(saved, dwell[0].DwellMs)
+    }
+
+    entered := make(chan struct{})
+    release := make(chan struct{})
+    flushed := make(chan struct{})
+    go func() {
+        tracker.flushDirty(func(sessionID string, dwell []models.SectionDwell) {
+            close(entered)
+            <-release
+            record(dwell)
+        })
+        close(flushed)
+    }()
+
+    // 주기 저장이 100ms 스냅샷을 들고 멈춘 사이에 고정이 더 쌓이고 세션이 끝난다
+    <-entered
+    addDwellFixation(tracker, "s1", 200)
+    finished := make(chan []models.SectionDwell)
+    go func() {
+        dwell, err := tracker.finish("s1", func(dwell []models.SectionDwell) error {
+            record(dwell)
+            return nil
+        })
+        if err != nil {
+            t.Errorf("최종 저장 실패: %v", err)
+        }
+        finished <- dwell
+    }()
+
+    select {
+    case <-finished:
+        t.Fatal("진행 중인 주기 저장보다 최종 저장이 먼저 끝남")
+    case <-time.After(100 * time.Millisecond):
+    }
+    close(release)
+    <-flushed
+    final := <-finished
+
+    if len(final) != 1 || final[0].DwellMs != 300 {
+        t.Fatalf("최종값 = %+v, 기대값 300ms", final)
+    }
+    mu.Lock()
+    defer mu.Unlock()
+    if len(saved) != 2 || saved[0] != 100 || saved[1] != 300 {
+        t.Fatalf("저장 순서 = %v, 기대값 [100 300]", saved)
+    }
+}
+
+// 끝난 세션은 주기 저장 대상에서 빠지고, 다시 restore하기 전까지 값이 쌓이지 않는다
+func TestDwellFinishedSessionIsNotFlushedAgain(t *testing.T) {
+    tracker := newDwellTracker()
+    tracker.restore("s1", nil)
+    addDwellFixation(tracker, "s1", 100)
+
+    if _, err := tracker.finish("s1", func([]models.SectionDwell) error { return nil }); err != nil {
+        t.Fatalf("최종 저장 실패: %v", err)
+    }
+    addDwellFixation(tracker, "s1", 200)
+    tracker.flushDirty(func(sessionID string, dwell []models.SectionDwell) {
+        t.Fatalf("끝난 세션이 다시 저장됨: %s %+v", sessionID, dwell)
+    })
+    if tracker.has("s1") {
+        t.Fatal("끝난 세션이 메모리에 남아 있음")
+    }
+
+    // 재연결하면 저장된 값부터 이어서 센다
+    tracker.restore("s1", []models.SectionDwell{{SessionID: "s1", PageID: "productJoin", SectionID: "risk-warning", DwellMs: 100}})
+    addDwellFixation(tracker, "s1", 50)
+    if dwell, _ := tracker.snapshot("s1"); len(dwell) != 1 || dwell[0].DwellMs != 150 {
+        t.Fatalf("restore 뒤 누적값 = %+v, 기대값 150ms", dwell)
+    }
+}
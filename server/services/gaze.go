@@ -3,86 +3,324 @@ package services
 import (
     "log"
     "sync"
+    "sync/atomic"
     "time"
 
+    "shinhan-eyetracking/server/analysis"
     "shinhan-eyetracking/server/database"
     "shinhan-eyetracking/server/models"
 )
 
 type GazeService struct {
-    db               *database.DB
-    kafkaService     *KafkaService
+    db               database.Store
+    publisher        EventPublisher
     websocketService *WebSocketService
+    dwell            *dwellTracker
+
+    // 세션별 시선 고정 검출기와 아직 저장하지 않은 고정/도약
+    analysisConfig   analysis.Config
+    detectors        map[string]*analysis.Detector
+    pendingFixations []analysis.Fixation
+    pendingSaccades  []analysis.Saccade
+    detectorMu       sync.Mutex
     
-    lastGazeData models.GazeData
-    currentPage  string
-    mu           sync.Mutex
-    pageMu       sync.RWMutex
+    // 세션별 전송 대기 샘플, 대시보드용 마지막 샘플, 현재 페이지
+    streams    map[string]*gazeStream
+    ended      map[string]time.Time // 종료한 세션과 종료 시각. 늦게 온 샘플로 스트림을 되살리지 않는다
+    closing    bool                 // Shutdown 이후. 새 스트림을 만들지 않는다
+    streamsMu  sync.Mutex
+    overflowed atomic.Int64 // 세션 스트림 상한 초과로 버린 샘플 누적
+
+    // 세션 상태를 메모리에서 내릴 때 함께 정리할 곳 (시선 검증기 등). 서버 시작 전에 등록
+    releaseHooks []func(sessionID string)
 }
 
-func NewGazeService(db *database.DB, kafka *KafkaService, websocket *WebSocketService) *GazeService {
+func NewGazeService(db database.Store, publisher EventPublisher, websocket *WebSocketService, analysisConfig analysis.Config) *GazeService {
     service := &GazeService{
         db:               db,
-        kafkaService:     kafka,
+        publisher:        publisher,
         websocketService: websocket,
+        dwell:            newDwellTracker(),
+        analysisConfig:   analysisConfig,
+        detectors:        make(map[string]*analysis.Detector),
+        streams:          make(map[string]*gazeStream),
+        ended:            make(map[string]time.Time),
     }
 
-    // 0.1초마다 데이터 전송하는 고루틴 시작
-    go service.startDataBroadcast()
+    // 1초마다 섹션 체류 시간 저장 및 대시보드 갱신
+    go service.startDwellFlush()
     
-    // 1시간마다 오래된 데이터 정리
+    // 1시간마다 오래된 데이터 정리 (세션 체인에 연결된 증적은 남긴다)
     go service.startDataCleanup()
 
     return service
 }
 
+// 저장 파이프라인(세션 스트림, 전송 대기열, Kafka/스풀/내장 버스) 상태
+func (g *GazeService) PublisherStats() PublisherStats {
+    stats := g.publisher.Stats()
+    stats.StreamOverflowed = g.overflowed.Load()
+    return stats
+}
+
 func (g *GazeService) HandleGazeData(data models.GazeData) {
-    g.mu.Lock()
-    g.lastGazeData = data
-    g.mu.Unlock()
+    if !g.accepting(data.SessionID) {
+        log.Printf("⚠️ 종료된 세션의 시선 데이터 무시 [%s]", data.SessionID)
+        return
+    }
+
+    // 체류 시간은 throttling 이전에 모든 샘플로 계산
+    g.ensureDwellSession(data.SessionID)
+    g.dwell.addSample(data)
+    g.recordEvents(g.detect(data))
+
+    // 저장 파이프라인 전송과 대시보드 갱신은 세션 스트림이 0.1초마다 따로 처리
+    if !g.withStream(data.SessionID, func(s *gazeStream) { s.push(data) }) {
+        log.Printf("⚠️ 종료된 세션의 시선 데이터 무시 [%s]", data.SessionID)
+    }
+}
+
+// 배치로 받은 샘플을 순서대로 처리
+func (g *GazeService) HandleGazeBatch(samples []models.GazeData) {
+    for _, data := range samples {
+        g.HandleGazeData(data)
+    }
 }
 
 func (g *GazeService) HandlePageChange(data models.PageChangeData) {
-    // 현재 페이지 상태 업데이트
-    g.pageMu.Lock()
-    g.currentPage = data.CurrentPage
-    g.pageMu.Unlock()
+    // 세션의 현재 페이지 상태 업데이트
+    accepted := g.withStream(data.SessionID, func(s *gazeStream) {
+        s.currentPage = data.CurrentPage
+        s.lastActivity = time.Now()
+    })
+    if !accepted {
+        log.Printf("⚠️ 종료된 세션의 페이지 변경 무시 [%s]: %s", data.SessionID, data.CurrentPage)
+        return
+    }
+
+    // 이전 페이지에서 진행 중이던 시선 고정 마무리
+    g.recordEvents(g.flushDetector(data.SessionID))
 
     // 데이터베이스에 페이지 변경 이력 저장
     if err := g.db.SavePageChange(data); err != nil {
         log.Printf("❌ 페이지 변경 DB 저장 실패: %v", err)
     }
 
-    // 모든 클라이언트에게 페이지 변경 알림
-    g.websocketService.BroadcastToClients("pageChange", data)
+    // 같은 세션 방의 클라이언트에게만 페이지 변경 알림
+    g.websocketService.BroadcastToRoom(data.SessionID, "pageChange", data)
+
+    log.Printf("📄 페이지 변경 [%s]: %s", data.SessionID, data.CurrentPage)
+}
+
+// 세션의 현재 페이지 (알 수 없으면 빈 문자열)
+func (g *GazeService) GetCurrentPage(sessionID string) string {
+    g.streamsMu.Lock()
+    s, exists := g.streams[sessionID]
+    g.streamsMu.Unlock()
 
-    log.Printf("📄 페이지 변경: %s", data.CurrentPage)
+    if !exists {
+        return ""
+    }
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.currentPage
 }
 
-func (g *GazeService) GetCurrentPage() string {
-    g.pageMu.RLock()
-    defer g.pageMu.RUnlock()
-    return g.currentPage
+// 진행 중인 모든 세션의 현재 페이지
+func (g *GazeService) GetCurrentPages() map[string]string {
+    g.streamsMu.Lock()
+    streams := make([]*gazeStream, 0, len(g.streams))
+    for _, s := range g.streams {
+        streams = append(streams, s)
+    }
+    g.streamsMu.Unlock()
+
+    pages := make(map[string]string, len(streams))
+    for _, s := range streams {
+        s.mu.Lock()
+        if s.currentPage != "" {
+            pages[s.sessionID] = s.currentPage
+        }
+        s.mu.Unlock()
+    }
+    return pages
+}
+
+// 세션의 섹션별 체류 시간. 진행 중이면 메모리 값, 아니면 저장된 값
+func (g *GazeService) GetSectionDwell(sessionID string) ([]models.SectionDwell, error) {
+    if dwell, ok := g.dwell.snapshot(sessionID); ok {
+        return dwell, nil
+    }
+    return g.db.GetSectionDwell(sessionID)
+}
+
+// 아직 저장되지 않은 고정까지 포함한 세션의 시선 고정 목록
+func (g *GazeService) GetFixations(sessionID string) ([]analysis.Fixation, error) {
+    g.saveEvents()
+    return g.db.GetFixations(sessionID)
+}
+
+// 아직 저장되지 않은 도약까지 포함한 세션의 도약 목록
+func (g *GazeService) GetSaccades(sessionID string) ([]analysis.Saccade, error) {
+    g.saveEvents()
+    return g.db.GetSaccades(sessionID)
+}
+
+// 세션 상태를 메모리에서 내릴 때 호출할 함수 등록
+func (g *GazeService) OnSessionRelease(fn func(sessionID string)) {
+    g.releaseHooks = append(g.releaseHooks, fn)
+}
+
+// 세션 종료 시 최종 체류 시간을 저장하고 메모리에서 제거. 이후 들어온 샘플은 받지 않는다
+func (g *GazeService) FinishSession(sessionID string) {
+    g.streamsMu.Lock()
+    g.ended[sessionID] = time.Now()
+    g.streamsMu.Unlock()
+    g.stopStream(sessionID)
+
+    dwell, err := g.releaseSession(sessionID)
+    if err != nil {
+        log.Printf("❌ 최종 체류 시간 저장 실패 [%s]: %v", sessionID, err)
+        return
+    }
+    if len(dwell) == 0 {
+        return
+    }
+    g.websocketService.BroadcastToRoom(sessionID, "dwellUpdate", map[string]interface{}{
+        "sessionId": sessionID,
+        "sections":  dwell,
+    })
+}
+
+// 키오스크 연결이 모두 끊긴 세션. 남은 샘플을 보내고 상태를 저장한 뒤 메모리에서 내린다.
+// 다시 연결되면 저장된 체류 시간부터 이어서 센다
+func (g *GazeService) ReleaseSession(sessionID string) {
+    g.stopStream(sessionID)
+
+    if _, err := g.releaseSession(sessionID); err != nil {
+        log.Printf("❌ 체류 시간 저장 실패 [%s]: %v", sessionID, err)
+    }
 }
 
-func (g *GazeService) startDataBroadcast() {
-    ticker := time.NewTicker(100 * time.Millisecond)
+// 서버 종료 시 진행 중인 모든 세션의 남은 샘플을 보내고 상태를 저장한다. 발행자를 닫기 전에 호출.
+// 이후 들어온 샘플은 받지 않는다 (저장하지 못한 채 새 스트림에 남지 않도록)
+func (g *GazeService) Shutdown() {
+    g.streamsMu.Lock()
+    g.closing = true
+    sessionIDs := make([]string, 0, len(g.streams))
+    for sessionID := range g.streams {
+        sessionIDs = append(sessionIDs, sessionID)
+    }
+    g.streamsMu.Unlock()
+
+    for _, sessionID := range sessionIDs {
+        g.ReleaseSession(sessionID)
+    }
+    g.saveEvents()
+    log.Printf("💾 진행 중이던 세션 %d개 저장 완료", len(sessionIDs))
+}
+
+// 진행 중인 고정을 마무리하고 검출기·체류 시간 상태를 저장한 뒤 제거한다.
+// 세션 종료, 연결 해제, 유휴 스트림 정리가 모두 이 경로를 거친다
+func (g *GazeService) releaseSession(sessionID string) ([]models.SectionDwell, error) {
+    g.recordEvents(g.flushDetector(sessionID))
+    g.detectorMu.Lock()
+    delete(g.detectors, sessionID)
+    g.detectorMu.Unlock()
+    g.saveEvents()
+
+    for _, fn := range g.releaseHooks {
+        fn(sessionID)
+    }
+
+    return g.dwell.finish(sessionID, g.db.SaveSectionDwell)
+}
+
+func (g *GazeService) detect(data models.GazeData) analysis.Events {
+    g.detectorMu.Lock()
+    defer g.detectorMu.Unlock()
+
+    detector, exists := g.detectors[data.SessionID]
+    if !exists {
+        detector = analysis.NewDetector(g.analysisConfig)
+        g.detectors[data.SessionID] = detector
+    }
+    return detector.Add(data)
+}
+
+func (g *GazeService) flushDetector(sessionID string) analysis.Events {
+    g.detectorMu.Lock()
+    defer g.detectorMu.Unlock()
+
+    detector, exists := g.detectors[sessionID]
+    if !exists {
+        return analysis.Events{}
+    }
+    return detector.Flush()
+}
+
+// 검출된 고정을 체류 시간에 반영하고 고정과 도약을 저장 대기열에 넣는다
+func (g *GazeService) recordEvents(events analysis.Events) {
+    if len(events.Fixations) == 0 && len(events.Saccades) == 0 {
+        return
+    }
+    for _, f := range events.Fixations {
+        g.dwell.addFixation(f)
+    }
+
+    g.detectorMu.Lock()
+    g.pendingFixations = append(g.pendingFixations, events.Fixations...)
+    g.pendingSaccades = append(g.pendingSaccades, events.Saccades...)
+    g.detectorMu.Unlock()
+}
+
+func (g *GazeService) saveEvents() {
+    g.detectorMu.Lock()
+    fixations, saccades := g.pendingFixations, g.pendingSaccades
+    g.pendingFixations, g.pendingSaccades = nil, nil
+    g.detectorMu.Unlock()
+
+    if len(fixations) > 0 {
+        if err := g.db.SaveFixations(fixations); err != nil {
+            log.Printf("❌ 시선 고정 저장 실패 (%d개): %v", len(fixations), err)
+        }
+    }
+    if len(saccades) > 0 {
+        if err := g.db.SaveSaccades(saccades); err != nil {
+            log.Printf("❌ 도약 저장 실패 (%d개): %v", len(saccades), err)
+        }
+    }
+}
+
+// 서버 재시작 후 들어온 세션은 저장된 누적값부터 이어서 계산
+func (g *GazeService) ensureDwellSession(sessionID string) {
+    if g.dwell.has(sessionID) {
+        return
+    }
+    saved, err := g.db.GetSectionDwell(sessionID)
+    if err != nil {
+        log.Printf("⚠️ 저장된 체류 시간 조회 실패 [%s]: %v", sessionID, err)
+    }
+    g.dwell.restore(sessionID, saved)
+}
+
+func (g *GazeService) startDwellFlush() {
+    ticker := time.NewTicker(1 * time.Second)
     defer ticker.Stop()
 
     for range ticker.C {
-        g.mu.Lock()
-        if g.lastGazeData.X != 0 || g.lastGazeData.Y != 0 {
-            // Kafka로 전송 (DB 저장은 Consumer가 담당)
-            if err := g.kafkaService.SendGazeData(g.lastGazeData); err != nil {
-                log.Printf("⚠️ Kafka 전송 실패: %v", err)
+        g.saveEvents()
+
+        g.dwell.flushDirty(func(sessionID string, dwell []models.SectionDwell) {
+            if err := g.db.SaveSectionDwell(dwell); err != nil {
+                log.Printf("❌ 체류 시간 저장 실패 [%s]: %v", sessionID, err)
             }
 
-            // 실시간 브로드캐스트만 담당
-            g.websocketService.BroadcastToClients("gazeData", g.lastGazeData)
-						
-            g.lastGazeData = models.GazeData{}
-        }
-        g.mu.Unlock()
+            g.websocketService.BroadcastToRoom(sessionID, "dwellUpdate", map[string]interface{}{
+                "sessionId": sessionID,
+                "sections":  dwell,
+            })
+        })
     }
 }
 
@@ -91,11 +329,13 @@ func (g *GazeService) startDataCleanup() {
     defer ticker.Stop()
 
     for range ticker.C {
+        g.pruneEnded(time.Now().Add(-streamIdleTimeout))
+
         gazeRows, pageRows, err := g.db.CleanOldData()
         if err != nil {
             log.Printf("❌ 데이터 정리 실패: %v", err)
         } else {
-            log.Printf("🗑️ 7일 이전 데이터 정리 완료: 시선 %d개, 페이지 변경 %d개", gazeRows, pageRows)
+            log.Printf("🗑️ 체인에 연결되지 않은 7일 이전 데이터 정리 완료: 시선 %d개, 페이지 변경 %d개", gazeRows, pageRows)
         }
     }
-}
\ No newline at end of file
+}
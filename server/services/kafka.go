@@ -5,107 +5,120 @@ import (
     "encoding/json"
     "fmt"
     "log"
-    "net"
-    "strconv"
     "time"
 
     "shinhan-eyetracking/server/models"
+    "shinhan-eyetracking/server/topics"
 
     "github.com/segmentio/kafka-go"
 )
 
 type KafkaService struct {
-    writer *kafka.Writer
+    brokers      string
+    topic        string
+    createTopics bool
+    topicSpecs   []topics.Spec
+    writer       *kafka.Writer
 }
 
-func NewKafkaService(brokers string) *KafkaService {
-    log.Printf("🔧 Kafka 연결 시도: %s", brokers)
-
-    // 토픽 생성 시도
-    // createTopicIfNotExists(brokers, "gaze-data")
-
+// 세션 ID를 키로 해시 파티셔닝해 한 세션의 샘플은 항상 같은 파티션에 순서대로 들어간다.
+// 브로커에 접속하지 않고 만든다. 보내기 전에 Connect로 토픽과 브로커를 확인한다
+func NewKafkaService(brokers, topic string, createTopics bool, topicSpecs ...topics.Spec) *KafkaService {
     writer := kafka.NewWriter(kafka.WriterConfig{
         Brokers:      []string{brokers},
-        Topic:        "gaze-data",
-        Balancer:     &kafka.LeastBytes{},
+        Topic:        topic,
+        Balancer:     &kafka.Hash{},
         BatchTimeout: 100 * time.Millisecond,
         BatchSize:    100,
     })
 
-    service := &KafkaService{writer: writer}
-    service.testConnection()
+    return &KafkaService{
+        brokers:      brokers,
+        topic:        topic,
+        createTopics: createTopics,
+        topicSpecs:   topicSpecs,
+        writer:       writer,
+    }
+}
+
+// 토픽을 확인(없으면 생성)하고 브로커가 시선 토픽 메타데이터에 응답하는지 본다
+func (k *KafkaService) Connect(ctx context.Context) error {
+    log.Printf("🔧 Kafka 연결 시도: %s (토픽 %s)", k.brokers, k.topic)
+
+    if err := topics.Ensure(ctx, k.brokers, k.createTopics, k.topicSpecs...); err != nil {
+        return fmt.Errorf("Kafka 토픽 확인 실패: %w", err)
+    }
+    if err := k.probe(ctx); err != nil {
+        return fmt.Errorf("Kafka 연결 실패 (%s): %w", k.brokers, err)
+    }
 
-    return service
+    log.Println("✅ Kafka 연결 성공")
+    return nil
 }
 
 func (k *KafkaService) Close() error {
     return k.writer.Close()
 }
 
-func (k *KafkaService) testConnection() {
-    err := k.writer.WriteMessages(context.Background(),
-        kafka.Message{
-            Key:   []byte("test"),
-            Value: []byte(`{"message": "서버 시작됨", "timestamp": "` + time.Now().Format(time.RFC3339) + `"}`),
-        },
-    )
-    if err != nil {
-        log.Printf("❌ Kafka 연결 실패: %v", err)
-    } else {
-        log.Println("✅ Kafka 연결 성공")
-    }
+func (k *KafkaService) Stats() PublisherStats {
+    return PublisherStats{Backend: EventBusKafka}
 }
 
-func (k *KafkaService) SendGazeData(data models.GazeData) error {
-    jsonData, err := json.Marshal(data)
-    if err != nil {
-        return fmt.Errorf("JSON 변환 실패: %w", err)
-    }
+// 토픽에 아무것도 쓰지 않고 메타데이터 요청으로 브로커와 토픽을 확인
+func (k *KafkaService) probe(ctx context.Context) error {
+    ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+    defer cancel()
 
-    err = k.writer.WriteMessages(context.Background(),
-        kafka.Message{
-            Key:   []byte("gaze"),
-            Value: jsonData,
-        },
-    )
+    client := &kafka.Client{Addr: kafka.TCP(k.brokers)}
+    resp, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{k.topic}})
     if err != nil {
-        return fmt.Errorf("Kafka 전송 실패: %w", err)
+        return err
+    }
+    for _, topic := range resp.Topics {
+        if topic.Error != nil {
+            return fmt.Errorf("토픽 %s 조회 실패: %w", topic.Name, topic.Error)
+        }
     }
-
     return nil
 }
 
-func createTopicIfNotExists(brokers, topic string) {
-    conn, err := kafka.Dial("tcp", brokers)
+func (k *KafkaService) SendGazeData(data models.GazeData) error {
+    message, err := gazeMessage(data)
     if err != nil {
-        log.Printf("⚠️ 토픽 생성을 위한 연결 실패: %v", err)
-        return
+        return err
     }
-    defer conn.Close()
 
-    controller, err := conn.Controller()
-    if err != nil {
-        log.Printf("⚠️ Controller 정보 가져오기 실패: %v", err)
-        return
+    if err := k.writer.WriteMessages(context.Background(), message); err != nil {
+        return fmt.Errorf("Kafka 전송 실패: %w", err)
     }
+    return nil
+}
 
-    controllerConn, err := kafka.Dial("tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
-    if err != nil {
-        log.Printf("⚠️ Controller 연결 실패: %v", err)
-        return
+// 샘플마다 메시지 하나씩, 한 번의 쓰기로 전송
+func (k *KafkaService) SendGazeBatch(samples []models.GazeData) error {
+    messages := make([]kafka.Message, 0, len(samples))
+    for _, data := range samples {
+        message, err := gazeMessage(data)
+        if err != nil {
+            return err
+        }
+        messages = append(messages, message)
     }
-    defer controllerConn.Close()
 
-    topicConfig := kafka.TopicConfig{
-        Topic:             topic,
-        NumPartitions:     1,
-        ReplicationFactor: 1,
+    if err := k.writer.WriteMessages(context.Background(), messages...); err != nil {
+        return fmt.Errorf("Kafka 전송 실패 (%d건): %w", len(messages), err)
     }
+    return nil
+}
 
-    err = controllerConn.CreateTopics(topicConfig)
+// 세션 ID를 메시지 키로 쓴다 (파티션 배치와 세션 안의 순서 보장)
+func gazeMessage(data models.GazeData) (kafka.Message, error) {
+    jsonData, err := json.Marshal(data)
     if err != nil {
-        log.Printf("⚠️ 토픽 생성 실패: %v", err)
-    } else {
-        log.Printf("✅ 토픽 생성됨: %s", topic)
+        return kafka.Message{}, fmt.Errorf("JSON 변환 실패: %w", err)
     }
-}
\ No newline at end of file
+    return kafka.Message{
+        Key:   []byte(data.SessionID),
+        Value: jsonData,
+    }, nil
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+    "testing"
+
+    "shinhan-eyetracking/server/models"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// 메시지 키는 세션 ID라 한 세션의 샘플은 Writer의 해시 분배로 항상 같은 파티션에 들어간다
+func TestGazeMessagesAreKeyedBySession(t *testing.T) {
+    partitions := []int{0, 1, 2, 3, 4, 5}
+    balancer := &kafka.Hash{}
+    placed := make(map[string]int)
+
+    for _, sessionID := range []string{"s1", "s2", "s3", "s4"} {
+        for _, data := range gazeSamples(sessionID, 0, 20) {
+            message, err := gazeMessage(data)
+            if err != nil {
+                t.Fatalf("메시지 생성 실패: %v", err)
+            }
+            if string(message.Key) != sessionID {
+                t.Fatalf("메시지 키 = %q, 기대값 %q", message.Key, sessionID)
+            }
+
+            decoded, err := models.DecodeGazeData(message.Value)
+            if err != nil || decoded.SessionID != sessionID || decoded.Timestamp != data.Timestamp {
+                t.Fatalf("메시지 본문 해석 결과 %+v, %v", decoded, err)
+            }
+
+            partition := balancer.Balance(message, partitions...)
+            if first, seen := placed[sessionID]; seen && first != partition {
+                t.Fatalf("[%s] 파티션이 %d 에서 %d 로 바뀜", sessionID, first, partition)
+            }
+            placed[sessionID] = partition
+        }
+    }
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+    "context"
+    "fmt"
+
+    "shinhan-eyetracking/server/config"
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/models"
+    "shinhan-eyetracking/server/spool"
+    "shinhan-eyetracking/server/topics"
+)
+
+const (
+    EventBusKafka = "kafka" // 메인 서버 -> Kafka -> Consumer -> DB (운영)
+    EventBusLocal = "local" // 메인 서버 안에서 바로 저장 (브로커 없는 단일 노드, 개발 환경)
+)
+
+// 시선 샘플을 저장 파이프라인으로 보내는 경로. GazeService는 이 인터페이스에만 의존한다
+type EventPublisher interface {
+    // 한 세션 스트림에서 모은 샘플. 세션 안의 순서를 유지해야 한다
+    SendGazeBatch(samples []models.GazeData) error
+    // 아직 보내지 못한 샘플을 마저 처리하고 닫는다
+    Close() error
+    Stats() PublisherStats
+}
+
+// 전달 결과를 나중에 콜백으로 알려 주는 발행자 (AsyncPublisher).
+// 세션 스트림은 발행자가 이 인터페이스도 구현하면 전송 주기를 막지 않도록 이쪽으로 보낸다
+type DeliveryPublisher interface {
+    EventPublisher
+    Publish(samples []models.GazeData, onDelivery DeliveryCallback)
+}
+
+// 저장 파이프라인 상태 (page-status로 노출)
+type PublisherStats struct {
+    Backend string       `json:"backend"`
+    Queued  int          `json:"queued,omitempty"` // 내장 버스에서 저장을 기다리는 배치 수
+    Spool   *spool.Stats `json:"spool,omitempty"`
+    Async   *AsyncStats  `json:"async,omitempty"`
+
+    // 세션 스트림의 전송 대기 샘플 상한을 넘어 버린 샘플 누적 (GazeService가 채운다)
+    StreamOverflowed int64 `json:"streamOverflowed"`
+}
+
+var (
+    _ EventPublisher    = (*KafkaService)(nil)
+    _ EventPublisher    = (*LocalBus)(nil)
+    _ EventPublisher    = (*SpooledPublisher)(nil)
+    _ DeliveryPublisher = (*AsyncPublisher)(nil)
+    _ spoolTarget       = (*KafkaService)(nil)
+)
+
+// EVENT_BUS 설정에 맞는 발행자를 만든다.
+// 스풀이 있으면 브로커에 닿지 않아도 스풀에 쌓으며 시작하고 연결은 드레인 주기에서 다시 시도한다.
+// 스풀이 없으면 Kafka에 연결할 수 없을 때 데이터를 버리며 계속 돌지 않도록 실패를 반환
+func NewEventPublisher(cfg *config.Config, db database.Store) (EventPublisher, error) {
+    switch cfg.EventBus {
+    case EventBusKafka, "":
+        kafka := NewKafkaService(cfg.KafkaBrokers, cfg.GazeTopic, cfg.KafkaCreateTopics, topics.FromConfig(cfg)...)
+        if cfg.SpoolDir == "" {
+            if err := kafka.Connect(context.Background()); err != nil {
+                kafka.Close()
+                return nil, err
+            }
+            return kafka, nil
+        }
+        publisher, err := NewSpooledPublisher(kafka, spool.Options{
+            Dir:          cfg.SpoolDir,
+            MaxBytes:     cfg.SpoolMaxBytes,
+            SegmentBytes: cfg.SpoolSegmentBytes,
+        })
+        if err != nil {
+            kafka.Close()
+            return nil, fmt.Errorf("스풀 열기 실패: %w", err)
+        }
+        return publisher, nil
+    case EventBusLocal:
+        return NewLocalBus(db, int(cfg.ConsumerBatchSize)), nil
+    default:
+        return nil, fmt.Errorf("알 수 없는 이벤트 버스: %s", cfg.EventBus)
+    }
+}
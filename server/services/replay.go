@@ -0,0 +1,305 @@
+package services
+
+import (
+    "fmt"
+    "log"
+    "sort"
+    "sync"
+    "time"
+
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/models"
+
+    "github.com/gorilla/websocket"
+)
+
+const maxReplaySpeed = 16.0
+
+// 저장된 세션을 기록 당시 간격 그대로 WebSocket으로 다시 보내는 재생 서비스.
+// 연결마다 재생기 하나만 둔다
+type ReplayService struct {
+    db               database.Store
+    websocketService *WebSocketService
+    players          map[*websocket.Conn]*replayPlayer
+    mu               sync.Mutex
+}
+
+func NewReplayService(db database.Store, websocketService *WebSocketService) *ReplayService {
+    return &ReplayService{
+        db:               db,
+        websocketService: websocketService,
+        players:          make(map[*websocket.Conn]*replayPlayer),
+    }
+}
+
+// 세션 기록을 읽어 재생을 시작한다. 이미 재생 중이면 기존 재생을 멈추고 교체
+func (r *ReplayService) Start(conn *websocket.Conn, req models.ReplayStartData) error {
+    if req.Speed == 0 {
+        req.Speed = 1
+    }
+    if err := validateReplaySpeed(req.Speed); err != nil {
+        return err
+    }
+
+    session, err := r.db.GetSession(req.SessionID)
+    if err != nil {
+        return fmt.Errorf("세션 조회 실패: %w", err)
+    }
+    if session == nil {
+        return fmt.Errorf("존재하지 않는 세션: %s", req.SessionID)
+    }
+
+    events, err := r.db.GetReplayEvents(req.SessionID)
+    if err != nil {
+        return fmt.Errorf("재생 기록 조회 실패: %w", err)
+    }
+    if len(events) == 0 {
+        return fmt.Errorf("재생할 기록이 없는 세션: %s", req.SessionID)
+    }
+
+    player := &replayPlayer{
+        sessionID:        req.SessionID,
+        events:           events,
+        conn:             conn,
+        websocketService: r.websocketService,
+        controls:         make(chan models.ReplayControlData),
+        done:             make(chan struct{}),
+    }
+
+    r.mu.Lock()
+    previous := r.players[conn]
+    r.players[conn] = player
+    r.mu.Unlock()
+
+    if previous != nil {
+        previous.stop()
+    }
+
+    log.Printf("⏯️ 세션 재생 시작: %s (%d건, %.1f배속) -> %s",
+        req.SessionID, len(events), req.Speed, conn.RemoteAddr().String())
+    go func() {
+        player.run(req.Speed, req.PositionMs)
+        r.mu.Lock()
+        if r.players[conn] == player {
+            delete(r.players, conn)
+        }
+        r.mu.Unlock()
+    }()
+    return nil
+}
+
+func (r *ReplayService) Control(conn *websocket.Conn, cmd models.ReplayControlData) error {
+    switch cmd.Action {
+    case "pause", "resume", "seek":
+    case "speed":
+        if err := validateReplaySpeed(cmd.Speed); err != nil {
+            return err
+        }
+    default:
+        return fmt.Errorf("알 수 없는 재생 제어: %q", cmd.Action)
+    }
+
+    r.mu.Lock()
+    player := r.players[conn]
+    r.mu.Unlock()
+
+    if player == nil {
+        return fmt.Errorf("재생 중인 세션이 없습니다")
+    }
+
+    select {
+    case player.controls <- cmd:
+        return nil
+    case <-player.done:
+        return fmt.Errorf("재생이 이미 종료되었습니다")
+    }
+}
+
+// 재생 중지 (연결 종료 시에도 호출)
+func (r *ReplayService) Stop(conn *websocket.Conn) {
+    r.mu.Lock()
+    player := r.players[conn]
+    delete(r.players, conn)
+    r.mu.Unlock()
+
+    if player != nil {
+        player.stop()
+        log.Printf("⏹️ 세션 재생 중지: %s", player.sessionID)
+    }
+}
+
+func (r *ReplayService) GetPlayerCount() int {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return len(r.players)
+}
+
+func validateReplaySpeed(speed float64) error {
+    if speed <= 0 || speed > maxReplaySpeed {
+        return fmt.Errorf("재생 배속은 0 초과 %.0f 이하여야 합니다: %v", maxReplaySpeed, speed)
+    }
+    return nil
+}
+
+type replayPlayer struct {
+    sessionID        string
+    events           []models.ReplayEvent
+    conn             *websocket.Conn
+    websocketService *WebSocketService
+    controls         chan models.ReplayControlData
+    done             chan struct{}
+    stopOnce         sync.Once
+}
+
+func (p *replayPlayer) stop() {
+    p.stopOnce.Do(func() { close(p.done) })
+}
+
+// 재생 위치는 첫 기록 기준 경과 ms. 실제 시각(anchorWall)과 위치(anchorPos)를 기준점으로 잡고
+// 배속이나 위치가 바뀔 때마다 기준점을 다시 잡는다
+func (p *replayPlayer) run(speed float64, startPos int64) {
+    defer p.stop()
+
+    origin := p.events[0].Timestamp
+    duration := p.events[len(p.events)-1].Timestamp - origin
+
+    cursor := p.indexAt(startPos)
+    anchorPos := clampPosition(startPos, duration)
+    anchorWall := time.Now()
+    state := "playing"
+
+    position := func() int64 {
+        if state != "playing" {
+            return anchorPos
+        }
+        elapsed := float64(time.Since(anchorWall).Milliseconds()) * speed
+        return clampPosition(anchorPos+int64(elapsed), duration)
+    }
+    notify := func() bool {
+        return p.send("replayState", models.ReplayState{
+            SessionID:  p.sessionID,
+            State:      state,
+            PositionMs: position(),
+            DurationMs: duration,
+            Speed:      speed,
+        })
+    }
+
+    // 중간부터 시작해도 화면이 올바른 페이지를 보이도록 직전 페이지 변경을 먼저 보낸다
+    if !p.sendCurrentPage(cursor) || !notify() {
+        return
+    }
+
+    timer := time.NewTimer(0)
+    if !timer.Stop() {
+        <-timer.C
+    }
+    defer timer.Stop()
+
+    for {
+        if state == "playing" && cursor >= len(p.events) {
+            anchorPos = duration
+            state = "ended"
+            if !notify() {
+                return
+            }
+        }
+
+        var due <-chan time.Time
+        if state == "playing" {
+            offset := p.events[cursor].Timestamp - origin - anchorPos
+            wait := time.Duration(float64(offset)/speed) * time.Millisecond
+            timer.Reset(wait - time.Since(anchorWall))
+            due = timer.C
+        }
+
+        select {
+        case <-p.done:
+            return
+
+        case <-due:
+            if !p.sendEvent(p.events[cursor]) {
+                return
+            }
+            cursor++
+
+        case cmd := <-p.controls:
+            if due != nil && !timer.Stop() {
+                <-timer.C
+            }
+
+            switch cmd.Action {
+            case "pause":
+                anchorPos = position()
+                if state == "playing" {
+                    state = "paused"
+                }
+            case "resume":
+                if state == "paused" {
+                    state = "playing"
+                    anchorWall = time.Now()
+                }
+            case "speed":
+                anchorPos = position()
+                anchorWall = time.Now()
+                speed = cmd.Speed
+            case "seek":
+                anchorPos = clampPosition(cmd.PositionMs, duration)
+                anchorWall = time.Now()
+                cursor = p.indexAt(anchorPos)
+                if state == "ended" {
+                    state = "paused"
+                }
+                if !p.sendCurrentPage(cursor) {
+                    return
+                }
+            }
+            if !notify() {
+                return
+            }
+        }
+    }
+}
+
+// 위치 이후 첫 기록의 인덱스
+func (p *replayPlayer) indexAt(position int64) int {
+    target := p.events[0].Timestamp + position
+    return sort.Search(len(p.events), func(i int) bool {
+        return p.events[i].Timestamp >= target
+    })
+}
+
+func (p *replayPlayer) sendCurrentPage(cursor int) bool {
+    for i := cursor - 1; i >= 0; i-- {
+        if p.events[i].Page != nil {
+            return p.send("pageChange", p.events[i].Page)
+        }
+    }
+    return true
+}
+
+func (p *replayPlayer) sendEvent(event models.ReplayEvent) bool {
+    if event.Page != nil {
+        return p.send("pageChange", event.Page)
+    }
+    return p.send("gazeData", event.Gaze)
+}
+
+// 전송 실패(연결 끊김)면 false
+func (p *replayPlayer) send(messageType string, data interface{}) bool {
+    if err := p.websocketService.SendToClient(p.conn, messageType, data); err != nil {
+        log.Printf("❌ 재생 전송 실패로 재생 중지 [%s]: %v", p.sessionID, err)
+        return false
+    }
+    return true
+}
+
+func clampPosition(position, duration int64) int64 {
+    if position < 0 {
+        return 0
+    }
+    if position > duration {
+        return duration
+    }
+    return position
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+    "testing"
+
+    "shinhan-eyetracking/server/models"
+)
+
+func TestValidateReplaySpeed(t *testing.T) {
+    cases := []struct {
+        speed float64
+        valid bool
+    }{
+        {1, true},
+        {0.25, true},
+        {maxReplaySpeed, true},
+        {maxReplaySpeed + 0.1, false},
+        {0, false},
+        {-1, false},
+    }
+
+    for _, tc := range cases {
+        if err := validateReplaySpeed(tc.speed); (err == nil) != tc.valid {
+            t.Errorf("validateReplaySpeed(%v) = %v, 기대 통과 여부 %v", tc.speed, err, tc.valid)
+        }
+    }
+}
+
+func TestClampPosition(t *testing.T) {
+    cases := []struct {
+        position, duration, want int64
+    }{
+        {-100, 1000, 0},
+        {0, 1000, 0},
+        {500, 1000, 500},
+        {1000, 1000, 1000},
+        {1500, 1000, 1000},
+        {10, 0, 0},
+    }
+
+    for _, tc := range cases {
+        if got := clampPosition(tc.position, tc.duration); got != tc.want {
+            t.Errorf("clampPosition(%d, %d) = %d, 기대값 %d", tc.position, tc.duration, got, tc.want)
+        }
+    }
+}
+
+func TestReplayIndexAt(t *testing.T) {
+    // 첫 기록 기준 0, 20, 20, 60ms
+    p := &replayPlayer{events: []models.ReplayEvent{
+        {Timestamp: 1000}, {Timestamp: 1020}, {Timestamp: 1020}, {Timestamp: 1060},
+    }}
+
+    cases := []struct {
+        position int64
+        want     int
+    }{
+        {0, 0},
+        {1, 1},
+        {20, 1}, // 같은 시각 기록은 모두 위치 이후로 본다
+        {21, 3},
+        {60, 3},
+        {61, 4}, // 끝난 뒤
+    }
+
+    for _, tc := range cases {
+        if got := p.indexAt(tc.position); got != tc.want {
+            t.Errorf("indexAt(%d) = %d, 기대값 %d", tc.position, got, tc.want)
+        }
+    }
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+    "fmt"
+
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/report"
+)
+
+// 분쟁 대응용 상담별 증적 보고서 생성
+type ReportService struct {
+    db             database.Store
+    verdictService *VerdictService
+}
+
+func NewReportService(db database.Store, verdictService *VerdictService) *ReportService {
+    return &ReportService{
+        db:             db,
+        verdictService: verdictService,
+    }
+}
+
+func (s *ReportService) BuildReport(sessionID string) (*report.Report, error) {
+    session, err := s.db.GetSession(sessionID)
+    if err != nil {
+        return nil, fmt.Errorf("세션 조회 실패: %w", err)
+    }
+    if session == nil {
+        return nil, fmt.Errorf("세션을 찾을 수 없음: %s", sessionID)
+    }
+
+    verdict, err := s.verdictService.EvaluateSession(sessionID)
+    if err != nil {
+        return nil, err
+    }
+
+    pageChanges, err := s.db.GetPageChanges(sessionID)
+    if err != nil {
+        return nil, fmt.Errorf("페이지 이력 조회 실패: %w", err)
+    }
+
+    head, err := s.db.GetChainHead(sessionID)
+    if err != nil {
+        return nil, fmt.Errorf("체인 헤드 조회 실패: %w", err)
+    }
+    records, err := s.db.GetChainRecords(sessionID)
+    if err != nil {
+        return nil, fmt.Errorf("체인 레코드 조회 실패: %w", err)
+    }
+
+    chain := integrity.Verify(sessionID, records, head)
+    return report.Build(*session, pageChanges, verdict, chain), nil
+}
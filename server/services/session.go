@@ -0,0 +1,76 @@
+package services
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "log"
+    "time"
+
+    "shinhan-eyetracking/server/catalog"
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/models"
+)
+
+type SessionService struct {
+    db      database.Store
+    catalog *catalog.Catalog
+}
+
+func NewSessionService(db database.Store, terms *catalog.Catalog) *SessionService {
+    return &SessionService{db: db, catalog: terms}
+}
+
+func (s *SessionService) StartSession(req models.SessionStartData) (*models.Session, error) {
+    if _, ok := s.catalog.Product(req.ProductID); !ok {
+        return nil, fmt.Errorf("카탈로그에 없는 상품: %q", req.ProductID)
+    }
+
+    id, err := newSessionID()
+    if err != nil {
+        return nil, fmt.Errorf("세션 ID 생성 실패: %w", err)
+    }
+
+    session := models.Session{
+        ID:           id,
+        BranchID:     req.BranchID,
+        EmployeeID:   req.EmployeeID,
+        ProductID:    req.ProductID,
+        TermsVersion: s.catalog.Version,
+        StartedAt:    time.Now(),
+    }
+
+    if err := s.db.CreateSession(session); err != nil {
+        return nil, fmt.Errorf("세션 저장 실패: %w", err)
+    }
+
+    log.Printf("🆕 상담 세션 시작: %s (지점 %s, 직원 %s, 상품 %s)",
+        session.ID, session.BranchID, session.EmployeeID, session.ProductID)
+    return &session, nil
+}
+
+func (s *SessionService) EndSession(sessionID string) (*models.Session, error) {
+    if err := s.db.EndSession(sessionID, time.Now()); err != nil {
+        return nil, err
+    }
+
+    session, err := s.db.GetSession(sessionID)
+    if err != nil {
+        return nil, err
+    }
+
+    log.Printf("🏁 상담 세션 종료: %s", sessionID)
+    return session, nil
+}
+
+func (s *SessionService) GetSession(sessionID string) (*models.Session, error) {
+    return s.db.GetSession(sessionID)
+}
+
+func newSessionID() (string, error) {
+    buf := make([]byte, 16)
+    if _, err := rand.Read(buf); err != nil {
+        return "", err
+    }
+    return hex.EncodeToString(buf), nil
+}
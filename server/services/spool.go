@@ -0,0 +1,213 @@
+package services
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "log"
+    "sync"
+    "time"
+
+    "shinhan-eyetracking/server/models"
+    "shinhan-eyetracking/server/spool"
+)
+
+// 스풀에 쌓인 샘플을 Kafka로 다시 보내 보는 주기 (브로커 연결 재시도 주기이기도 하다)
+var spoolDrainInterval = time.Second
+
+// 한 번에 Kafka로 보내는 스풀 레코드 수
+const spoolDrainBatch = 500
+
+// 스풀 뒤에서 샘플을 받는 브로커 (KafkaService)
+type spoolTarget interface {
+    Connect(ctx context.Context) error
+    SendGazeBatch(samples []models.GazeData) error
+    Close() error
+}
+
+// Kafka가 받지 못한(ack 실패) 샘플을 디스크 스풀에 적어 두고, 브로커가 돌아오면 순서대로 다시 보낸다.
+// 스풀이 비기 전까지는 새 샘플도 스풀 뒤에 붙여 세션 안의 순서를 지킨다.
+// 브로커에 닿지 않는 상태로 시작해도 스풀에 쌓으며 받고, 토픽 확인과 연결은 드레인 주기마다 다시 시도한다.
+// 다시 보낸 뒤 Ack 전에 죽으면 재시작 후 같은 샘플이 한 번 더 갈 수 있다 (at-least-once)
+type SpooledPublisher struct {
+    kafka spoolTarget
+    spool *spool.Spool
+
+    // spooling은 스풀에 아직 보내지 못한 샘플이 있거나 브로커에 아직 연결하지 못했다는 뜻. 스풀 추가와 해제는 mu 안에서만
+    spooling bool
+    mu       sync.Mutex
+
+    // 드레인 고루틴만 쓴다
+    connected   bool
+    lastConnErr string
+
+    done     chan struct{}
+    cancel   context.CancelFunc
+    finished chan struct{}
+}
+
+func NewSpooledPublisher(kafka spoolTarget, opts spool.Options) (*SpooledPublisher, error) {
+    sp, err := spool.Open(opts)
+    if err != nil {
+        return nil, err
+    }
+
+    ctx, cancel := context.WithCancel(context.Background())
+    p := &SpooledPublisher{
+        kafka:    kafka,
+        spool:    sp,
+        spooling: true, // 브로커 연결을 확인할 때까지는 스풀에 쌓는다
+        done:     make(chan struct{}),
+        cancel:   cancel,
+        finished: make(chan struct{}),
+    }
+    if pending := sp.Pending(); pending > 0 {
+        log.Printf("📼 이전 실행에서 보내지 못한 시선 샘플 %d건을 스풀에서 재전송 예정", pending)
+    }
+    go p.runDrain(ctx)
+
+    log.Printf("✅ Kafka 스풀 사용: %s (최대 %d바이트)", opts.Dir, opts.MaxBytes)
+    return p, nil
+}
+
+func (p *SpooledPublisher) SendGazeBatch(samples []models.GazeData) error {
+    p.mu.Lock()
+    spooling := p.spooling
+    p.mu.Unlock()
+
+    if !spooling {
+        err := p.kafka.SendGazeBatch(samples)
+        if err == nil {
+            return nil
+        }
+        log.Printf("⚠️ %v, 스풀에 기록", err)
+    }
+    return p.append(samples)
+}
+
+func (p *SpooledPublisher) append(samples []models.GazeData) error {
+    records := make([][]byte, 0, len(samples))
+    for _, data := range samples {
+        record, err := json.Marshal(data)
+        if err != nil {
+            return fmt.Errorf("JSON 변환 실패: %w", err)
+        }
+        records = append(records, record)
+    }
+
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    if err := p.spool.Append(records...); err != nil {
+        return fmt.Errorf("스풀 기록 실패 (%d건): %w", len(records), err)
+    }
+    p.spooling = true
+    return nil
+}
+
+func (p *SpooledPublisher) runDrain(ctx context.Context) {
+    defer close(p.finished)
+
+    ticker := time.NewTicker(spoolDrainInterval)
+    defer ticker.Stop()
+
+    for {
+        if p.connect(ctx) {
+            p.drain()
+        }
+        select {
+        case <-p.done:
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+// 아직 연결하지 못했으면 토픽 확인과 연결을 시도한다. 같은 오류는 한 번만 기록
+func (p *SpooledPublisher) connect(ctx context.Context) bool {
+    if p.connected {
+        return true
+    }
+    if err := p.kafka.Connect(ctx); err != nil {
+        if err.Error() != p.lastConnErr {
+            log.Printf("⚠️ %v, 스풀에 쌓으며 %s마다 재시도", err, spoolDrainInterval)
+            p.lastConnErr = err.Error()
+        }
+        return false
+    }
+    p.connected = true
+    return true
+}
+
+// 스풀을 앞에서부터 Kafka로 보낸다. 실패하면 다음 주기에 같은 위치부터 다시 시도
+func (p *SpooledPublisher) drain() {
+    sent := int64(0)
+    defer func() {
+        if sent > 0 {
+            stats := p.spool.Stats()
+            log.Printf("📼 스풀 재전송: %d건 (남은 %d건, %d바이트)", sent, stats.Pending, stats.Bytes)
+        }
+    }()
+
+    for {
+        select {
+        case <-p.done:
+            return
+        default:
+        }
+
+        records, pos, err := p.spool.Read(spoolDrainBatch)
+        if err != nil {
+            log.Printf("❌ 스풀 읽기 실패: %v", err)
+            return
+        }
+        if len(records) == 0 {
+            // 비어 있음을 확인하고 해제하는 사이에 새 샘플이 스풀로 들어오지 않도록 mu 안에서 판단
+            p.mu.Lock()
+            if p.spool.Pending() == 0 {
+                p.spooling = false
+            }
+            p.mu.Unlock()
+            return
+        }
+
+        samples := make([]models.GazeData, 0, len(records))
+        for _, record := range records {
+            data, err := models.DecodeGazeData(record)
+            if err != nil {
+                // CRC를 통과한 레코드라 생길 일은 없지만, 막히지 않도록 건너뛴다
+                log.Printf("⚠️ 스풀 레코드 해석 실패, 건너뜀: %v", err)
+                continue
+            }
+            samples = append(samples, data)
+        }
+
+        if len(samples) > 0 {
+            if err := p.kafka.SendGazeBatch(samples); err != nil {
+                return
+            }
+        }
+        if err := p.spool.Ack(pos); err != nil {
+            log.Printf("❌ 스풀 위치 기록 실패: %v", err)
+            return
+        }
+        sent += int64(len(records))
+    }
+}
+
+// 남은 스풀은 디스크에 그대로 두고 다음 실행에서 보낸다
+func (p *SpooledPublisher) Close() error {
+    close(p.done)
+    p.cancel()
+    <-p.finished
+
+    if err := p.spool.Close(); err != nil {
+        log.Printf("⚠️ 스풀 닫기 실패: %v", err)
+    }
+    return p.kafka.Close()
+}
+
+func (p *SpooledPublisher) Stats() PublisherStats {
+    stats := p.spool.Stats()
+    return PublisherStats{Backend: EventBusKafka, Spool: &stats}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "reflect"
+    "sync"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/models"
+    "shinhan-eyetracking/server/spool"
+)
+
+// 연결 가능 여부를 바꿀 수 있는 브로커. 연결되지 않으면 전송도 실패한다
+type fakeBroker struct {
+    mu       sync.Mutex
+    up       bool
+    attempts int
+    received []int64
+}
+
+func (b *fakeBroker) Connect(ctx context.Context) error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    b.attempts++
+    if !b.up {
+        return errors.New("브로커에 연결할 수 없음")
+    }
+    return nil
+}
+
+func (b *fakeBroker) SendGazeBatch(samples []models.GazeData) error {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    if !b.up {
+        return errors.New("브로커에 연결할 수 없음")
+    }
+    for _, data := range samples {
+        b.received = append(b.received, data.Timestamp)
+    }
+    return nil
+}
+
+func (b *fakeBroker) Close() error { return nil }
+
+func (b *fakeBroker) setUp(up bool) {
+    b.mu.Lock()
+    b.up = up
+    b.mu.Unlock()
+}
+
+func (b *fakeBroker) snapshot() (attempts int, received []int64) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    return b.attempts, append([]int64(nil), b.received...)
+}
+
+// 브로커가 죽은 채로 시작해도 스풀에 쌓으며 받고, 이전 실행의 스풀과 함께 브로커가 돌아오면 순서대로 보낸다
+func TestSpooledPublisherStartsWithBrokerDown(t *testing.T) {
+    defaultInterval := spoolDrainInterval
+    spoolDrainInterval = 10 * time.Millisecond
+    t.Cleanup(func() { spoolDrainInterval = defaultInterval })
+
+    opts := spool.Options{Dir: t.TempDir(), MaxBytes: 1 << 20, SegmentBytes: 4 << 10}
+
+    // 이전 실행에서 보내지 못하고 남은 샘플
+    previous, err := spool.Open(opts)
+    if err != nil {
+        t.Fatalf("스풀 열기 실패: %v", err)
+    }
+    for _, data := range gazeSamples("s1", 0, 5) {
+        record, _ := json.Marshal(data)
+        if err := previous.Append(record); err != nil {
+            t.Fatalf("스풀 기록 실패: %v", err)
+        }
+    }
+    previous.Close()
+
+    broker := &fakeBroker{}
+    p, err := NewSpooledPublisher(broker, opts)
+    if err != nil {
+        t.Fatalf("브로커가 죽어 있다고 시작하지 못함: %v", err)
+    }
+    defer p.Close()
+
+    if err := p.SendGazeBatch(gazeSamples("s1", 5, 5)); err != nil {
+        t.Fatalf("브로커가 죽은 동안 받은 샘플을 스풀에 쓰지 못함: %v", err)
+    }
+
+    // 연결을 여러 번 다시 시도하지만 아무것도 보내지 않는다
+    deadline := time.Now().Add(2 * time.Second)
+    for attempts, _ := broker.snapshot(); attempts < 3 && time.Now().Before(deadline); attempts, _ = broker.snapshot() {
+        time.Sleep(5 * time.Millisecond)
+    }
+    attempts, received := broker.snapshot()
+    if attempts < 3 || len(received) != 0 {
+        t.Fatalf("연결 시도 %d회, 전송 %v (재시도해야 하고 보낸 것은 없어야 함)", attempts, received)
+    }
+    if pending := p.Stats().Spool.Pending; pending != 10 {
+        t.Fatalf("스풀 대기 %d건, 기대값 10건", pending)
+    }
+
+    broker.setUp(true)
+    var want []int64
+    for _, data := range gazeSamples("s1", 0, 10) {
+        want = append(want, data.Timestamp)
+    }
+    deadline = time.Now().Add(2 * time.Second)
+    for _, received = broker.snapshot(); len(received) < len(want) && time.Now().Before(deadline); _, received = broker.snapshot() {
+        time.Sleep(5 * time.Millisecond)
+    }
+    if !reflect.DeepEqual(received, want) {
+        t.Fatalf("브로커가 받은 timestamp = %v, 기대값 %v", received, want)
+    }
+
+    // 스풀이 비면 새 샘플은 바로 보낸다
+    deadline = time.Now().Add(2 * time.Second)
+    for p.Stats().Spool.Pending > 0 && time.Now().Before(deadline) {
+        time.Sleep(5 * time.Millisecond)
+    }
+    if err := p.SendGazeBatch(gazeSamples("s1", 10, 1)); err != nil {
+        t.Fatalf("연결 후 전송 실패: %v", err)
+    }
+    if _, received = broker.snapshot(); len(received) != len(want)+1 {
+        t.Fatalf("연결 후 샘플이 바로 전송되지 않음: %v", received)
+    }
+}
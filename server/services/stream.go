@@ -0,0 +1,208 @@
+package services
+
+import (
+    "log"
+    "sync"
+    "time"
+
+    "shinhan-eyetracking/server/models"
+)
+
+const (
+    // 세션마다 쌓인 샘플을 Kafka로, 마지막 샘플을 대시보드로 보내는 주기
+    streamFlushInterval = 100 * time.Millisecond
+    // 이 시간 동안 아무 데이터도 없으면 스트림과 세션 상태를 정리한다 (세션 종료 없이 끊긴 키오스크)
+    streamIdleTimeout = 5 * time.Minute
+)
+
+// 세션별 전송 대기 샘플 상한 (약 30Hz 기준 5분 분량). 가득 차면 다음 주기에 비울 때까지 새 샘플을 버리고 센다.
+// 수신(WebSocket 읽기)은 저장 파이프라인을 기다리지 않는다
+var maxPendingGaze = 10000
+
+// 세션 하나의 시선 전송 상태. 세션마다 독립된 주기로 비운다
+type gazeStream struct {
+    sessionID    string
+    pending      []models.GazeData
+    overflowed   int64 // 대기 샘플이 상한에 닿아 버린 샘플
+    undelivered  int64 // 전송 대기열 초과나 하위 발행자 오류로 저장 파이프라인에 넘기지 못한 샘플
+    last         *models.GazeData
+    currentPage  string
+    lastActivity time.Time
+    closed       bool
+    mu           sync.Mutex
+
+    done     chan struct{}
+    finished chan struct{}
+}
+
+func newGazeStream(sessionID string) *gazeStream {
+    return &gazeStream{
+        sessionID:    sessionID,
+        lastActivity: time.Now(),
+        done:         make(chan struct{}),
+        finished:     make(chan struct{}),
+    }
+}
+
+// s.mu를 잡은 상태에서 호출. 기다리지 않는다: 대기 샘플이 상한에 닿으면 샘플을 버리고 overflowed로 센다
+func (s *gazeStream) push(data models.GazeData) {
+    s.last = &data
+    s.lastActivity = time.Now()
+    if len(s.pending) >= maxPendingGaze {
+        s.overflowed++
+        return
+    }
+    s.pending = append(s.pending, data)
+}
+
+// 세션 스트림을 찾거나 만들어 잠근 상태로 fn을 실행. 유휴 정리나 연결 해제로 닫힌 스트림이면 새로 만든다.
+// 종료한 세션이거나 서버가 종료 중이면 스트림을 되살리지 않고 false
+func (g *GazeService) withStream(sessionID string, fn func(s *gazeStream)) bool {
+    for {
+        g.streamsMu.Lock()
+        s, exists := g.streams[sessionID]
+        if !exists {
+            if !g.acceptingLocked(sessionID) {
+                g.streamsMu.Unlock()
+                return false
+            }
+            s = newGazeStream(sessionID)
+            g.streams[sessionID] = s
+            go g.runStream(s)
+        }
+        g.streamsMu.Unlock()
+
+        s.mu.Lock()
+        if !s.closed {
+            fn(s)
+            s.mu.Unlock()
+            return true
+        }
+        s.mu.Unlock()
+    }
+}
+
+// 세션의 샘플을 받을 수 있는지 (종료한 세션도, 종료 중인 서버도 아닌지)
+func (g *GazeService) accepting(sessionID string) bool {
+    g.streamsMu.Lock()
+    defer g.streamsMu.Unlock()
+    return g.acceptingLocked(sessionID)
+}
+
+// g.streamsMu를 잡은 상태에서 호출
+func (g *GazeService) acceptingLocked(sessionID string) bool {
+    _, ended := g.ended[sessionID]
+    return !g.closing && !ended
+}
+
+// before 이전에 종료한 세션 기록을 지운다. 그만큼 지난 뒤에는 늦게 올 샘플이 없다
+func (g *GazeService) pruneEnded(before time.Time) {
+    g.streamsMu.Lock()
+    defer g.streamsMu.Unlock()
+    for sessionID, endedAt := range g.ended {
+        if endedAt.Before(before) {
+            delete(g.ended, sessionID)
+        }
+    }
+}
+
+func (g *GazeService) runStream(s *gazeStream) {
+    defer close(s.finished)
+
+    ticker := time.NewTicker(streamFlushInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-s.done:
+            g.flushStream(s)
+            return
+        case <-ticker.C:
+            g.flushStream(s)
+            if g.closeIdleStream(s) {
+                log.Printf("💤 유휴 시선 스트림 정리: %s", s.sessionID)
+                if _, err := g.releaseSession(s.sessionID); err != nil {
+                    log.Printf("❌ 체류 시간 저장 실패 [%s]: %v", s.sessionID, err)
+                }
+                return
+            }
+        }
+    }
+}
+
+func (g *GazeService) flushStream(s *gazeStream) {
+    s.mu.Lock()
+    pending, overflowed, undelivered, last := s.pending, s.overflowed, s.undelivered, s.last
+    s.pending, s.overflowed, s.undelivered, s.last = nil, 0, 0, nil
+    s.mu.Unlock()
+
+    if overflowed > 0 {
+        g.overflowed.Add(overflowed)
+        log.Printf("⚠️ 전송 대기 샘플 상한(%d건) 초과로 버린 시선 샘플 %d건 [%s]", maxPendingGaze, overflowed, s.sessionID)
+    }
+    if undelivered > 0 {
+        log.Printf("⚠️ 저장 파이프라인 전달 실패 시선 샘플 %d건 [%s]", undelivered, s.sessionID)
+    }
+
+    // 비동기 전송 대기열이 있으면 거기에 넣고, 없으면 바로 보낸다. 대기열이 가득 차 기다리는 동안(block 정책)
+    // 새 샘플은 이 스트림에 상한까지 쌓인다. 실패는 콜백으로 받아 다음 주기에 모아서 로그로 남긴다
+    if len(pending) > 0 {
+        onDelivery := func(samples []models.GazeData, err error) {
+            if err == nil {
+                return
+            }
+            s.mu.Lock()
+            s.undelivered += int64(len(samples))
+            s.mu.Unlock()
+        }
+        if publisher, ok := g.publisher.(DeliveryPublisher); ok {
+            publisher.Publish(pending, onDelivery)
+        } else {
+            onDelivery(pending, g.publisher.SendGazeBatch(pending))
+        }
+    }
+
+    // 대시보드에는 주기마다 마지막 샘플만 보낸다
+    if last != nil {
+        g.websocketService.BroadcastToRoom(s.sessionID, "gazeData", *last)
+    }
+}
+
+// 대기 샘플 없이 오래 조용했던 스트림을 맵에서 제거
+func (g *GazeService) closeIdleStream(s *gazeStream) bool {
+    g.streamsMu.Lock()
+    defer g.streamsMu.Unlock()
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if len(s.pending) > 0 || time.Since(s.lastActivity) < streamIdleTimeout {
+        return false
+    }
+    s.closed = true
+    if g.streams[s.sessionID] == s {
+        delete(g.streams, s.sessionID)
+    }
+    return true
+}
+
+// 세션 종료 시 남은 샘플을 마저 보내고 스트림을 멈춘다
+func (g *GazeService) stopStream(sessionID string) {
+    g.streamsMu.Lock()
+    s, exists := g.streams[sessionID]
+    delete(g.streams, sessionID)
+    g.streamsMu.Unlock()
+
+    if !exists {
+        return
+    }
+
+    s.mu.Lock()
+    alreadyClosed := s.closed
+    s.closed = true
+    s.mu.Unlock()
+
+    if !alreadyClosed {
+        close(s.done)
+    }
+    <-s.finished
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+    "sync"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/integrity"
+    "shinhan-eyetracking/server/models"
+)
+
+// 받은 배치를 그대로 기록하는 발행자
+type recordingPublisher struct {
+    mu      sync.Mutex
+    batches [][]models.GazeData
+}
+
+func (p *recordingPublisher) SendGazeBatch(samples []models.GazeData) error {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    p.batches = append(p.batches, append([]models.GazeData(nil), samples...))
+    return nil
+}
+
+func (p *recordingPublisher) Close() error { return nil }
+
+func (p *recordingPublisher) Stats() PublisherStats { return PublisherStats{Backend: "test"} }
+
+// 세션별로 받은 샘플의 timestamp (받은 순서대로)
+func (p *recordingPublisher) timestamps() map[string][]int64 {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    result := make(map[string][]int64)
+    for _, batch := range p.batches {
+        for _, data := range batch {
+            result[data.SessionID] = append(result[data.SessionID], data.Timestamp)
+        }
+    }
+    return result
+}
+
+func gazeSamples(sessionID string, from, count int) []models.GazeData {
+    page := "productDetail"
+    samples := make([]models.GazeData, count)
+    for i := range samples {
+        samples[i] = models.GazeData{
+            X:           100,
+            Y:           100,
+            Timestamp:   int64(from+i) * 20,
+            CurrentPage: &page,
+            SessionID:   sessionID,
+        }
+    }
+    return samples
+}
+
+func expectTimestamps(t *testing.T, got []int64, from, count int) {
+    t.Helper()
+
+    if len(got) != count {
+        t.Fatalf("샘플 %d건, 기대값 %d건: %v", len(got), count, got)
+    }
+    for i, ts := range got {
+        if want := int64(from+i) * 20; ts != want {
+            t.Fatalf("%d번째 샘플 timestamp = %d, 기대값 %d", i, ts, want)
+        }
+    }
+}
+
+// 세션 스트림은 주기마다 세션별로 모은 샘플을 순서대로 보낸다
+func TestGazeStreamFlushesEachSessionInOrder(t *testing.T) {
+    publisher := &recordingPublisher{}
+    g := NewGazeService(database.NewMemoryStore(), publisher, NewWebSocketService(), analysis.DefaultConfig())
+
+    g.HandleGazeBatch(gazeSamples("s1", 0, 30))
+    g.HandleGazeBatch(gazeSamples("s2", 0, 10))
+
+    deadline := time.Now().Add(2 * time.Second)
+    for {
+        got := publisher.timestamps()
+        if len(got["s1"]) == 30 && len(got["s2"]) == 10 {
+            break
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("주기 전송이 끝나지 않음: s1 %d건, s2 %d건", len(got["s1"]), len(got["s2"]))
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+
+    publisher.mu.Lock()
+    for _, batch := range publisher.batches {
+        for _, data := range batch {
+            if data.SessionID != batch[0].SessionID {
+                t.Fatalf("한 배치에 여러 세션이 섞임: %s, %s", batch[0].SessionID, data.SessionID)
+            }
+        }
+    }
+    publisher.mu.Unlock()
+
+    got := publisher.timestamps()
+    expectTimestamps(t, got["s1"], 0, 30)
+    expectTimestamps(t, got["s2"], 0, 10)
+}
+
+// 세션을 끝내면 주기를 기다리지 않고 남은 샘플을 바로 보낸다
+func TestFinishSessionFlushesPendingSamples(t *testing.T) {
+    publisher := &recordingPublisher{}
+    g := NewGazeService(database.NewMemoryStore(), publisher, NewWebSocketService(), analysis.DefaultConfig())
+
+    g.HandleGazeBatch(gazeSamples("s1", 0, 5))
+    g.FinishSession("s1")
+
+    expectTimestamps(t, publisher.timestamps()["s1"], 0, 5)
+    if page := g.GetCurrentPage("s1"); page != "" {
+        t.Fatalf("종료한 세션의 스트림이 남아 있음: %s", page)
+    }
+}
+
+// 메모리 저장소와 내장 버스로 저장까지: 스트림 → 내장 버스 → 세션 해시 체인
+func TestGazeStreamStoresThroughLocalBus(t *testing.T) {
+    db := database.NewMemoryStore()
+    bus := NewLocalBus(db, 0)
+    g := NewGazeService(db, bus, NewWebSocketService(), analysis.DefaultConfig())
+
+    g.HandleGazeBatch(gazeSamples("s1", 0, 20))
+    g.Shutdown()
+    if err := bus.Close(); err != nil {
+        t.Fatalf("내장 버스 종료 실패: %v", err)
+    }
+
+    records, err := db.GetChainRecords("s1")
+    if err != nil {
+        t.Fatalf("체인 레코드 조회 실패: %v", err)
+    }
+    head, err := db.GetChainHead("s1")
+    if err != nil {
+        t.Fatalf("체인 헤드 조회 실패: %v", err)
+    }
+    if report := integrity.Verify("s1", records, head); !report.Valid || report.RecordCount != 20 {
+        t.Fatalf("저장된 체인 이상: %+v", report)
+    }
+}
+
+// 하위 발행자가 막혀 대기 샘플이 상한에 닿아도 수신은 기다리지 않고, 넘친 샘플은 버린 뒤 센다
+func TestGazeStreamDropsAndCountsInsteadOfBlockingWhenFull(t *testing.T) {
+    publisher := newGatedPublisher()
+    g := NewGazeService(database.NewMemoryStore(), publisher, NewWebSocketService(), analysis.DefaultConfig())
+    defaultMaxPending := maxPendingGaze
+    maxPendingGaze = 50
+    t.Cleanup(func() { maxPendingGaze = defaultMaxPending })
+
+    total := 2*maxPendingGaze + 10
+    handled := make(chan struct{})
+    go func() {
+        g.HandleGazeBatch(gazeSamples("s1", 0, total))
+        <-publisher.entered
+        g.HandleGazeBatch(gazeSamples("s1", total, total))
+        close(handled)
+    }()
+
+    select {
+    case <-handled:
+    case <-time.After(2 * time.Second):
+        t.Fatal("전송이 막힌 동안 샘플 수신이 멈춤")
+    }
+
+    close(publisher.release)
+    g.FinishSession("s1")
+
+    got := publisher.timestamps()["s1"]
+    overflowed := g.PublisherStats().StreamOverflowed
+    if overflowed == 0 {
+        t.Fatal("상한을 넘은 샘플이 집계되지 않음")
+    }
+    if int64(len(got))+overflowed != int64(2*total) {
+        t.Fatalf("전송 %d건 + 버림 %d건 != 수신 %d건", len(got), overflowed, 2*total)
+    }
+    for i := 1; i < len(got); i++ {
+        if got[i] <= got[i-1] {
+            t.Fatalf("전송 순서가 바뀜: %v", got)
+        }
+    }
+}
+
+// 종료한 세션에 늦게 온 샘플은 스트림을 되살리지 않는다
+func TestLateSamplesDoNotReviveFinishedSession(t *testing.T) {
+    publisher := &recordingPublisher{}
+    g := NewGazeService(database.NewMemoryStore(), publisher, NewWebSocketService(), analysis.DefaultConfig())
+
+    g.HandleGazeBatch(gazeSamples("s1", 0, 5))
+    g.FinishSession("s1")
+    g.HandleGazeBatch(gazeSamples("s1", 5, 5))
+    g.HandlePageChange(models.PageChangeData{SessionID: "s1", CurrentPage: "productJoin"})
+
+    if page := g.GetCurrentPage("s1"); page != "" {
+        t.Fatalf("종료한 세션의 스트림이 다시 생김: %s", page)
+    }
+    time.Sleep(2 * streamFlushInterval)
+    expectTimestamps(t, publisher.timestamps()["s1"], 0, 5)
+}
+
+// 서버 종료 뒤 들어온 샘플은 저장되지 않을 새 스트림을 만들지 않는다
+func TestShutdownStopsAcceptingSamples(t *testing.T) {
+    publisher := &recordingPublisher{}
+    g := NewGazeService(database.NewMemoryStore(), publisher, NewWebSocketService(), analysis.DefaultConfig())
+
+    g.HandleGazeBatch(gazeSamples("s1", 0, 5))
+    g.Shutdown()
+    g.HandleGazeBatch(gazeSamples("s1", 5, 5))
+    g.HandleGazeBatch(gazeSamples("s2", 0, 5))
+
+    g.streamsMu.Lock()
+    streams := len(g.streams)
+    g.streamsMu.Unlock()
+    if streams != 0 {
+        t.Fatalf("종료 뒤 스트림 %d개가 생김", streams)
+    }
+    got := publisher.timestamps()
+    expectTimestamps(t, got["s1"], 0, 5)
+    if len(got["s2"]) != 0 {
+        t.Fatalf("종료 뒤 들어온 세션 샘플이 전송됨: %v", got["s2"])
+    }
+}
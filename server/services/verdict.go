@@ -0,0 +1,79 @@
+package services
+
+import (
+    "fmt"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/catalog"
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/evaluation"
+)
+
+// 세션 판정 결과는 창구 직원이 서명 단계로 넘어갈 수 있는지의 근거가 된다
+type VerdictService struct {
+    db          database.Store
+    catalog     *catalog.Catalog
+    gazeService *GazeService
+    calibration *CalibrationService
+    rules       evaluation.Rules
+    reading     analysis.ReadingConfig
+}
+
+func NewVerdictService(db database.Store, terms *catalog.Catalog, gazeService *GazeService, calibration *CalibrationService, rules evaluation.Rules, reading analysis.ReadingConfig) *VerdictService {
+    return &VerdictService{
+        db:          db,
+        catalog:     terms,
+        gazeService: gazeService,
+        calibration: calibration,
+        rules:       rules,
+        reading:     reading,
+    }
+}
+
+func (v *VerdictService) EvaluateSession(sessionID string) (*evaluation.Verdict, error) {
+    session, err := v.db.GetSession(sessionID)
+    if err != nil {
+        return nil, fmt.Errorf("세션 조회 실패: %w", err)
+    }
+    if session == nil {
+        return nil, fmt.Errorf("세션을 찾을 수 없음: %s", sessionID)
+    }
+
+    dwell, err := v.gazeService.GetSectionDwell(sessionID)
+    if err != nil {
+        return nil, fmt.Errorf("체류 시간 조회 실패: %w", err)
+    }
+
+    visits, err := v.ClassifyReading(sessionID)
+    if err != nil {
+        return nil, err
+    }
+
+    calibration, err := v.calibration.GetLatestCalibration(sessionID)
+    if err != nil {
+        return nil, fmt.Errorf("보정 결과 조회 실패: %w", err)
+    }
+
+    evidence := evaluation.NewEvidence(dwell)
+    evidence.AddVisits(visits)
+    evidence.Calibration = calibration
+    return evaluation.Evaluate(*session, v.catalog, evidence, v.rules)
+}
+
+// 세션의 섹션 방문별 읽기/훑어보기/응시 분류
+func (v *VerdictService) ClassifyReading(sessionID string) ([]analysis.VisitClassification, error) {
+    fixations, err := v.gazeService.GetFixations(sessionID)
+    if err != nil {
+        return nil, fmt.Errorf("시선 고정 조회 실패: %w", err)
+    }
+
+    var result []analysis.VisitClassification
+    for _, visit := range analysis.SplitVisits(fixations) {
+        sectionLines := 0
+        if section, ok := v.catalog.Section(visit.PageID, visit.SectionID); ok {
+            sectionLines = section.Lines
+        }
+        result = append(result, analysis.ClassifyVisit(visit, sectionLines, v.reading))
+    }
+    return result, nil
+}
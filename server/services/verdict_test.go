@@ -0,0 +1,131 @@
+package services
+
+import (
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/analysis"
+    "shinhan-eyetracking/server/catalog"
+    "shinhan-eyetracking/server/database"
+    "shinhan-eyetracking/server/evaluation"
+    "shinhan-eyetracking/server/models"
+)
+
+const verdictTestTerms = `{
+    "version": "v1",
+    "products": [{"id": "fund", "name": "펀드", "pages": ["join"]}],
+    "pages": [{
+        "id": "join",
+        "title": "가입",
+        "sections": [
+            {"id": "risk", "name": "투자 위험", "required": 10, "priority": "high"},
+            {"id": "fee", "name": "수수료", "required": 5, "priority": "medium"}
+        ]
+    }]
+}`
+
+// 저장된 체류 시간과 보정 결과가 판정에 반영되는지 확인
+func TestVerdictServiceEvaluatesStoredEvidence(t *testing.T) {
+    terms, err := catalog.Parse([]byte(verdictTestTerms))
+    if err != nil {
+        t.Fatalf("카탈로그 파싱 실패: %v", err)
+    }
+
+    cases := []struct {
+        name        string
+        dwell       []models.SectionDwell
+        calibration *models.Calibration
+        passed      bool
+        codes       []string
+    }{
+        {
+            name: "체류 시간과 보정 충족",
+            dwell: []models.SectionDwell{
+                {PageID: "join", SectionID: "risk", DwellMs: 10000},
+                {PageID: "join", SectionID: "fee", DwellMs: 5000},
+            },
+            calibration: &models.Calibration{AccuracyPx: 30},
+            passed:      true,
+        },
+        {
+            name: "체류 시간 부족",
+            dwell: []models.SectionDwell{
+                {PageID: "join", SectionID: "risk", DwellMs: 10000},
+                {PageID: "join", SectionID: "fee", DwellMs: 4000},
+            },
+            calibration: &models.Calibration{AccuracyPx: 30},
+            codes:       []string{evaluation.CodeSectionUnmet},
+        },
+        {
+            name: "보정 오차 초과",
+            dwell: []models.SectionDwell{
+                {PageID: "join", SectionID: "risk", DwellMs: 10000},
+                {PageID: "join", SectionID: "fee", DwellMs: 5000},
+            },
+            calibration: &models.Calibration{AccuracyPx: 80},
+            codes:       []string{evaluation.CodeCalibrationError},
+        },
+        {
+            name:  "근거 자료 없음",
+            codes: []string{evaluation.CodeCalibrationMissing, evaluation.CodeSectionUnmet, evaluation.CodeSectionUnmet},
+        },
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            db := database.NewMemoryStore()
+            gazeService := NewGazeService(db, &recordingPublisher{}, NewWebSocketService(), analysis.DefaultConfig())
+            calibration := NewCalibrationService(db, 50)
+            verdicts := NewVerdictService(db, terms, gazeService, calibration,
+                evaluation.Rules{MaxCalibrationErrorPx: 50}, analysis.DefaultReadingConfig())
+
+            session := models.Session{ID: "s1", ProductID: "fund", TermsVersion: terms.Version, StartedAt: time.Now()}
+            if err := db.CreateSession(session); err != nil {
+                t.Fatalf("세션 생성 실패: %v", err)
+            }
+            for i := range tc.dwell {
+                tc.dwell[i].SessionID = session.ID
+            }
+            if err := db.SaveSectionDwell(tc.dwell); err != nil {
+                t.Fatalf("체류 시간 저장 실패: %v", err)
+            }
+            if tc.calibration != nil {
+                tc.calibration.SessionID = session.ID
+                if err := db.SaveCalibration(tc.calibration); err != nil {
+                    t.Fatalf("보정 결과 저장 실패: %v", err)
+                }
+            }
+
+            verdict, err := verdicts.EvaluateSession(session.ID)
+            if err != nil {
+                t.Fatalf("판정 실패: %v", err)
+            }
+            if verdict.Passed != tc.passed {
+                t.Fatalf("Passed = %v, 기대값 %v: %v", verdict.Passed, tc.passed, verdict.Reasons)
+            }
+            if len(verdict.Findings) != len(tc.codes) {
+                t.Fatalf("판정 사유 %+v, 기대값 %v", verdict.Findings, tc.codes)
+            }
+            for i, code := range tc.codes {
+                if verdict.Findings[i].Code != code {
+                    t.Fatalf("판정 사유 %+v, 기대값 %v", verdict.Findings, tc.codes)
+                }
+            }
+        })
+    }
+}
+
+func TestVerdictServiceRejectsUnknownSession(t *testing.T) {
+    terms, err := catalog.Parse([]byte(verdictTestTerms))
+    if err != nil {
+        t.Fatalf("카탈로그 파싱 실패: %v", err)
+    }
+    db := database.NewMemoryStore()
+    gazeService := NewGazeService(db, &recordingPublisher{}, NewWebSocketService(), analysis.DefaultConfig())
+    verdicts := NewVerdictService(db, terms, gazeService, NewCalibrationService(db, 50),
+        evaluation.DefaultRules(), analysis.DefaultReadingConfig())
+
+    if _, err := verdicts.EvaluateSession("없는-세션"); err == nil {
+        t.Fatal("없는 세션을 판정함")
+    }
+}
@@ -9,62 +9,224 @@ import (
     "github.com/gorilla/websocket"
 )
 
+// 연결별 상태. gorilla/websocket은 동시 쓰기를 허용하지 않으므로 연결마다 쓰기 락을 둔다
+type clientState struct {
+    room    string
+    role    models.Role // 핸드셰이크 전에는 빈 값
+    writeMu sync.Mutex
+}
+
 type WebSocketService struct {
-    clients   map[*websocket.Conn]bool
+    clients   map[*websocket.Conn]*clientState
+    rooms     map[string]map[*websocket.Conn]bool // 세션 ID -> 참여 연결
     clientsMu sync.RWMutex
 }
 
 func NewWebSocketService() *WebSocketService {
     log.Println("🔧 WebSocket 서비스 초기화")
     return &WebSocketService{
-        clients: make(map[*websocket.Conn]bool),
+        clients: make(map[*websocket.Conn]*clientState),
+        rooms:   make(map[string]map[*websocket.Conn]bool),
     }
 }
 
 func (ws *WebSocketService) AddClient(conn *websocket.Conn) {
     ws.clientsMu.Lock()
-    ws.clients[conn] = true
+    ws.clients[conn] = &clientState{}
     clientCount := len(ws.clients)
     ws.clientsMu.Unlock()
 
     // 클라이언트 정보 로깅
     remoteAddr := conn.RemoteAddr().String()
     log.Printf("🟢 새 클라이언트 연결: %s (총 %d개 클라이언트)", remoteAddr, clientCount)
-
-    // 연결 상태를 다른 클라이언트들에게 알림
-    ws.broadcastClientCount()
 }
 
 func (ws *WebSocketService) RemoveClient(conn *websocket.Conn) {
     ws.clientsMu.Lock()
-    if _, exists := ws.clients[conn]; exists {
+    if state, exists := ws.clients[conn]; exists {
+        room := state.room
+        ws.leaveRoomLocked(conn, state)
         delete(ws.clients, conn)
         clientCount := len(ws.clients)
         ws.clientsMu.Unlock()
 
         remoteAddr := conn.RemoteAddr().String()
         log.Printf("🔴 클라이언트 연결 해제: %s (총 %d개 클라이언트)", remoteAddr, clientCount)
-        
-        // 연결 상태를 다른 클라이언트들에게 알림
-        ws.broadcastClientCount()
+
+        // 같은 방의 다른 클라이언트들에게 알림
+        if room != "" {
+            ws.broadcastRoomPresence(room)
+        }
     } else {
         ws.clientsMu.Unlock()
         log.Printf("⚠️ 존재하지 않는 클라이언트 삭제 시도")
     }
 }
 
-func (ws *WebSocketService) BroadcastToClients(messageType string, data interface{}) {
+// 서버 종료 시 모든 연결에 종료를 알리고 닫는다. 각 연결의 핸들러가 읽기를 멈추고 정리 경로를 거친다
+func (ws *WebSocketService) CloseAll() {
+    ws.clientsMu.RLock()
+    conns := make([]*websocket.Conn, 0, len(ws.clients))
+    for conn := range ws.clients {
+        conns = append(conns, conn)
+    }
+    ws.clientsMu.RUnlock()
+
+    message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "서버 종료")
+    for _, conn := range conns {
+        conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+        conn.Close()
+    }
+    log.Printf("🔌 WebSocket 연결 %d개 종료", len(conns))
+}
+
+// 연결을 세션 방에 참여시킨다. 이미 다른 방에 있으면 먼저 나간다
+func (ws *WebSocketService) JoinRoom(conn *websocket.Conn, sessionID string) {
+    ws.clientsMu.Lock()
+    state, exists := ws.clients[conn]
+    if !exists {
+        ws.clientsMu.Unlock()
+        log.Printf("⚠️ 등록되지 않은 클라이언트의 방 참여 시도: %s", sessionID)
+        return
+    }
+
+    previous := state.room
+    if previous == sessionID {
+        ws.clientsMu.Unlock()
+        return
+    }
+    ws.leaveRoomLocked(conn, state)
+
+    members, ok := ws.rooms[sessionID]
+    if !ok {
+        members = make(map[*websocket.Conn]bool)
+        ws.rooms[sessionID] = members
+    }
+    members[conn] = true
+    state.room = sessionID
+    ws.clientsMu.Unlock()
+
+    log.Printf("🚪 방 참여: %s -> %s", conn.RemoteAddr().String(), sessionID)
+
+    if previous != "" {
+        ws.broadcastRoomPresence(previous)
+    }
+    ws.broadcastRoomPresence(sessionID)
+}
+
+func (ws *WebSocketService) LeaveRoom(conn *websocket.Conn) {
+    ws.clientsMu.Lock()
+    state, exists := ws.clients[conn]
+    if !exists || state.room == "" {
+        ws.clientsMu.Unlock()
+        return
+    }
+    room := state.room
+    ws.leaveRoomLocked(conn, state)
+    ws.clientsMu.Unlock()
+
+    ws.broadcastRoomPresence(room)
+}
+
+// 방의 모든 연결을 내보내고 방을 삭제 (세션 종료 시)
+func (ws *WebSocketService) CloseRoom(sessionID string) {
+    ws.clientsMu.Lock()
+    for conn := range ws.rooms[sessionID] {
+        if state, exists := ws.clients[conn]; exists {
+            state.room = ""
+        }
+    }
+    delete(ws.rooms, sessionID)
+    ws.clientsMu.Unlock()
+
+    log.Printf("🚪 방 닫힘: %s", sessionID)
+}
+
+// clientsMu 쓰기 락을 잡은 상태에서 호출
+func (ws *WebSocketService) leaveRoomLocked(conn *websocket.Conn, state *clientState) {
+    if state.room == "" {
+        return
+    }
+    if members, ok := ws.rooms[state.room]; ok {
+        delete(members, conn)
+        if len(members) == 0 {
+            delete(ws.rooms, state.room)
+        }
+    }
+    state.room = ""
+}
+
+// 핸드셰이크로 검증된 역할 기록
+func (ws *WebSocketService) SetRole(conn *websocket.Conn, role models.Role) {
+    ws.clientsMu.Lock()
+    if state, exists := ws.clients[conn]; exists {
+        state.role = role
+    }
+    ws.clientsMu.Unlock()
+}
+
+func (ws *WebSocketService) GetRole(conn *websocket.Conn) models.Role {
+    ws.clientsMu.RLock()
+    defer ws.clientsMu.RUnlock()
+    if state, exists := ws.clients[conn]; exists {
+        return state.role
+    }
+    return ""
+}
+
+// 연결이 현재 참여 중인 세션 ID (없으면 빈 문자열)
+func (ws *WebSocketService) GetRoom(conn *websocket.Conn) string {
+    ws.clientsMu.RLock()
+    defer ws.clientsMu.RUnlock()
+    if state, exists := ws.clients[conn]; exists {
+        return state.room
+    }
+    return ""
+}
+
+// 세션 방에 해당 역할의 연결이 남아 있는지
+func (ws *WebSocketService) RoomHasRole(sessionID string, role models.Role) bool {
+    ws.clientsMu.RLock()
+    defer ws.clientsMu.RUnlock()
+    for conn := range ws.rooms[sessionID] {
+        if state := ws.clients[conn]; state != nil && state.role == role {
+            return true
+        }
+    }
+    return false
+}
+
+// 같은 세션 방에 있는 클라이언트에게만 전송. 다른 방으로는 절대 전달되지 않는다
+func (ws *WebSocketService) BroadcastToRoom(sessionID string, messageType string, data interface{}) {
+    if sessionID == "" {
+        log.Printf("⚠️ 세션 ID 없는 브로드캐스트 스킵: %s", messageType)
+        return
+    }
+
     message := models.WebSocketMessage{
         Type: messageType,
         Data: data,
     }
 
+    type target struct {
+        conn  *websocket.Conn
+        state *clientState
+    }
+
+    // 역할상 수신할 수 없는 연결은 대상에서 제외
     ws.clientsMu.RLock()
-    clientCount := len(ws.clients)
+    targets := make([]target, 0, len(ws.rooms[sessionID]))
+    for conn := range ws.rooms[sessionID] {
+        state := ws.clients[conn]
+        if state == nil || !CanReceive(state.role, messageType) {
+            continue
+        }
+        targets = append(targets, target{conn: conn, state: state})
+    }
     ws.clientsMu.RUnlock()
 
+    clientCount := len(targets)
     if clientCount == 0 {
-        log.Printf("📭 클라이언트가 없어서 브로드캐스트 스킵: %s", messageType)
         return
     }
 
@@ -73,19 +235,17 @@ func (ws *WebSocketService) BroadcastToClients(messageType string, data interfac
     failedCount := 0
     var failedClients []*websocket.Conn
 
-    ws.clientsMu.RLock()
-    for client := range ws.clients {
-        err := client.WriteJSON(message)
+    for _, t := range targets {
+        err := ws.writeMessage(t.conn, t.state, message)
         if err != nil {
             failedCount++
-            failedClients = append(failedClients, client)
-            log.Printf("❌ 브로드캐스트 실패 [%s]: %s -> %v", 
-                messageType, client.RemoteAddr().String(), err)
+            failedClients = append(failedClients, t.conn)
+            log.Printf("❌ 브로드캐스트 실패 [%s]: %s -> %v",
+                messageType, t.conn.RemoteAddr().String(), err)
         } else {
             successCount++
         }
     }
-    ws.clientsMu.RUnlock()
 
     // 실패한 클라이언트들 정리
     if len(failedClients) > 0 {
@@ -95,13 +255,13 @@ func (ws *WebSocketService) BroadcastToClients(messageType string, data interfac
     // 브로드캐스트 결과 로깅
     if messageType == "gazeData" {
         if failedCount > 0 {
-            log.Printf("👁️ 시선 데이터 전송: 성공 %d, 실패 %d", successCount, failedCount)
+            log.Printf("👁️ 시선 데이터 전송 [%s]: 성공 %d, 실패 %d", sessionID, successCount, failedCount)
         }
     } else {
         // 페이지 변경 등 중요한 메시지는 상세히
-        log.Printf("📢 브로드캐스트 [%s]: 성공 %d, 실패 %d, 총 클라이언트 %d", 
-            messageType, successCount, failedCount, clientCount)
-        
+        log.Printf("📢 브로드캐스트 [%s] 방 %s: 성공 %d, 실패 %d, 방 인원 %d",
+            messageType, sessionID, successCount, failedCount, clientCount)
+
         if messageType == "pageChange" {
             if jsonData, err := json.Marshal(data); err == nil {
                 log.Printf("📄 전송된 페이지 변경 데이터: %s", string(jsonData))
@@ -110,27 +270,63 @@ func (ws *WebSocketService) BroadcastToClients(messageType string, data interfac
     }
 }
 
+// 특정 클라이언트에게만 메시지 전송 (요청에 대한 응답 등)
+func (ws *WebSocketService) SendToClient(conn *websocket.Conn, messageType string, data interface{}) error {
+    message := models.WebSocketMessage{
+        Type: messageType,
+        Data: data,
+    }
+
+    ws.clientsMu.RLock()
+    state := ws.clients[conn]
+    ws.clientsMu.RUnlock()
+
+    if state != nil && !CanReceive(state.role, messageType) {
+        log.Printf("🚫 역할 %q 수신 불가 메시지 차단 [%s]: %s",
+            state.role, messageType, conn.RemoteAddr().String())
+        return nil
+    }
+
+    if err := ws.writeMessage(conn, state, message); err != nil {
+        log.Printf("❌ 메시지 전송 실패 [%s]: %s -> %v",
+            messageType, conn.RemoteAddr().String(), err)
+        return err
+    }
+    return nil
+}
+
+func (ws *WebSocketService) writeMessage(conn *websocket.Conn, state *clientState, message models.WebSocketMessage) error {
+    if state == nil {
+        return conn.WriteJSON(message)
+    }
+    state.writeMu.Lock()
+    defer state.writeMu.Unlock()
+    return conn.WriteJSON(message)
+}
+
 func (ws *WebSocketService) cleanupFailedClients(failedClients []*websocket.Conn) {
     ws.clientsMu.Lock()
     cleanedCount := 0
     for _, client := range failedClients {
-        if _, exists := ws.clients[client]; exists {
+        if state, exists := ws.clients[client]; exists {
+            ws.leaveRoomLocked(client, state)
             delete(ws.clients, client)
             cleanedCount++
             client.Close() // 연결 정리
         }
     }
     ws.clientsMu.Unlock()
-    
+
     if cleanedCount > 0 {
         log.Printf("🧹 실패한 클라이언트 %d개 정리 완료", cleanedCount)
     }
 }
 
-func (ws *WebSocketService) broadcastClientCount() {
-    // 현재 연결된 클라이언트 수를 모든 클라이언트에게 알림
-    ws.BroadcastToClients("clientCount", map[string]interface{}{
-        "count":     ws.GetClientCount(),
+func (ws *WebSocketService) broadcastRoomPresence(sessionID string) {
+    // 방에 참여 중인 클라이언트 수를 같은 방 클라이언트들에게만 알림
+    ws.BroadcastToRoom(sessionID, "clientCount", map[string]interface{}{
+        "sessionId": sessionID,
+        "count":     ws.GetRoomCount(sessionID),
         "timestamp": time.Now().Unix(),
     })
 }
@@ -141,19 +337,34 @@ func (ws *WebSocketService) GetClientCount() int {
     return len(ws.clients)
 }
 
+func (ws *WebSocketService) GetRoomCount(sessionID string) int {
+    ws.clientsMu.RLock()
+    defer ws.clientsMu.RUnlock()
+    return len(ws.rooms[sessionID])
+}
+
 // 서비스 상태 정보 제공
 func (ws *WebSocketService) GetStatus() map[string]interface{} {
     ws.clientsMu.RLock()
     defer ws.clientsMu.RUnlock()
-    
+
     clientAddresses := make([]string, 0, len(ws.clients))
-    for client := range ws.clients {
+    roleCounts := make(map[models.Role]int)
+    for client, state := range ws.clients {
         clientAddresses = append(clientAddresses, client.RemoteAddr().String())
+        roleCounts[state.role]++
     }
-    
+
+    roomCounts := make(map[string]int, len(ws.rooms))
+    for room, members := range ws.rooms {
+        roomCounts[room] = len(members)
+    }
+
     return map[string]interface{}{
         "client_count":     len(ws.clients),
         "client_addresses": clientAddresses,
+        "rooms":            roomCounts,
+        "roles":            roleCounts,
         "service_status":   "running",
         "last_update":      time.Now().Format("2006-01-02 15:04:05"),
     }
@@ -164,15 +375,18 @@ func (ws *WebSocketService) StartStatusLogger() {
     go func() {
         ticker := time.NewTicker(30 * time.Second) // 30초마다
         defer ticker.Stop()
-        
+
         for range ticker.C {
             status := ws.GetStatus()
-            log.Printf("📊 WebSocket 서비스 상태: 클라이언트 %d개 연결됨", 
+            log.Printf("📊 WebSocket 서비스 상태: 클라이언트 %d개 연결됨",
                 status["client_count"])
-            
+
             if clientAddrs, ok := status["client_addresses"].([]string); ok && len(clientAddrs) > 0 {
                 log.Printf("🔗 연결된 클라이언트: %v", clientAddrs)
             }
+            if rooms, ok := status["rooms"].(map[string]int); ok && len(rooms) > 0 {
+                log.Printf("🚪 세션 방: %v", rooms)
+            }
         }
     }()
-}
\ No newline at end of file
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/models"
+
+    "github.com/gorilla/websocket"
+)
+
+// 서버 쪽 연결을 등록하고 역할을 정한 뒤, 그 연결로 보낸 메시지를 읽을 클라이언트 쪽 연결을 돌려준다
+func connectClient(t *testing.T, ws *WebSocketService, role models.Role) (server, client *websocket.Conn) {
+    t.Helper()
+
+    accepted := make(chan *websocket.Conn, 1)
+    upgrader := websocket.Upgrader{}
+    httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        conn, err := upgrader.Upgrade(w, r, nil)
+        if err != nil {
+            t.Errorf("업그레이드 실패: %v", err)
+            return
+        }
+        accepted <- conn
+    }))
+    t.Cleanup(httpServer.Close)
+
+    client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+    if err != nil {
+        t.Fatalf("WebSocket 연결 실패: %v", err)
+    }
+    server = <-accepted
+    t.Cleanup(func() {
+        client.Close()
+        server.Close()
+    })
+
+    ws.AddClient(server)
+    ws.SetRole(server, role)
+    return server, client
+}
+
+// 표시용 "error" 메시지를 보내고 그 전까지 받은 메시지 타입을 모은다 (방 인원 알림은 제외)
+func receivedTypes(t *testing.T, ws *WebSocketService, server, client *websocket.Conn) []string {
+    t.Helper()
+
+    if err := ws.SendToClient(server, "error", "끝"); err != nil {
+        t.Fatalf("표시 메시지 전송 실패: %v", err)
+    }
+
+    client.SetReadDeadline(time.Now().Add(2 * time.Second))
+    defer client.SetReadDeadline(time.Time{})
+    var types []string
+    for {
+        var message models.WebSocketMessage
+        if err := client.ReadJSON(&message); err != nil {
+            t.Fatalf("메시지 수신 실패: %v", err)
+        }
+        switch message.Type {
+        case "error":
+            return types
+        case "clientCount":
+        default:
+            types = append(types, message.Type)
+        }
+    }
+}
+
+// 브로드캐스트는 같은 세션 방에서 그 메시지를 받을 수 있는 역할에게만 간다
+func TestBroadcastStaysInRoomAndFiltersByRole(t *testing.T) {
+    ws := NewWebSocketService()
+
+    type member struct {
+        name   string
+        room   string
+        role   models.Role
+        expect []string
+    }
+    members := []member{
+        {"s1 직원", "s1", models.RoleEmployee, []string{"gazeData", "sessionJoined"}},
+        {"s1 감독자", "s1", models.RoleSupervisor, []string{"gazeData", "sessionJoined"}},
+        {"s1 고객", "s1", models.RoleCustomer, []string{"sessionJoined"}}, // 시선 브로드캐스트는 받지 않는다
+        {"s2 직원", "s2", models.RoleEmployee, nil},
+        {"방 없는 직원", "", models.RoleEmployee, nil},
+    }
+
+    servers := make([]*websocket.Conn, len(members))
+    clients := make([]*websocket.Conn, len(members))
+    for i, m := range members {
+        servers[i], clients[i] = connectClient(t, ws, m.role)
+        if m.room != "" {
+            ws.JoinRoom(servers[i], m.room)
+        }
+    }
+
+    ws.BroadcastToRoom("s1", "gazeData", map[string]interface{}{"x": 1, "y": 2})
+    ws.BroadcastToRoom("s1", "sessionJoined", map[string]interface{}{"id": "s1"})
+    ws.BroadcastToRoom("", "gazeData", map[string]interface{}{"x": 1, "y": 2})
+
+    for i, m := range members {
+        if got := receivedTypes(t, ws, servers[i], clients[i]); !reflect.DeepEqual(got, m.expect) {
+            t.Fatalf("%s 수신 = %v, 기대값 %v", m.name, got, m.expect)
+        }
+    }
+    if count := ws.GetRoomCount("s1"); count != 3 {
+        t.Fatalf("s1 방 인원 = %d, 기대값 3", count)
+    }
+}
+
+// 다른 방으로 옮기거나 방이 닫히면 이전 세션의 브로드캐스트를 더 받지 않는다
+func TestRoomMembershipFollowsJoinAndClose(t *testing.T) {
+    ws := NewWebSocketService()
+    server, client := connectClient(t, ws, models.RoleEmployee)
+
+    ws.JoinRoom(server, "s1")
+    ws.JoinRoom(server, "s2")
+    if room := ws.GetRoom(server); room != "s2" {
+        t.Fatalf("참여 방 = %q, 기대값 s2", room)
+    }
+    if count := ws.GetRoomCount("s1"); count != 0 {
+        t.Fatalf("나간 방 s1 인원 = %d, 기대값 0", count)
+    }
+
+    ws.BroadcastToRoom("s1", "pageChange", map[string]interface{}{"currentPage": "productJoin"})
+    ws.BroadcastToRoom("s2", "pageChange", map[string]interface{}{"currentPage": "productDetail"})
+    if got := receivedTypes(t, ws, server, client); !reflect.DeepEqual(got, []string{"pageChange"}) {
+        t.Fatalf("방 이동 후 수신 = %v, 기대값 [pageChange] (s2 것만)", got)
+    }
+
+    ws.CloseRoom("s2")
+    if room := ws.GetRoom(server); room != "" {
+        t.Fatalf("닫힌 방에 남아 있음: %q", room)
+    }
+    ws.BroadcastToRoom("s2", "pageChange", map[string]interface{}{"currentPage": "productDetail"})
+    if got := receivedTypes(t, ws, server, client); len(got) != 0 {
+        t.Fatalf("닫힌 방의 브로드캐스트를 받음: %v", got)
+    }
+}
@@ -0,0 +1,454 @@
+package spool
+
+import (
+    "bufio"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "hash/crc32"
+    "io"
+    "log"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+)
+
+// 디스크에 순서대로 쌓아 두는 write-ahead 스풀. 레코드는 세그먼트 파일 끝에 추가하고,
+// 앞에서부터 읽어 처리한 뒤 Ack한 위치를 head 파일에 기록한다. 다 읽은 세그먼트는 지운다.
+//
+// 레코드 형식: [길이 uint32][CRC32 uint32][payload]. 재시작 시 CRC가 맞지 않는 꼬리는 잘라낸다
+const (
+    headerSize    = 8
+    segmentSuffix = ".seg"
+    headFile      = "head"
+    // 길이 필드가 깨져 터무니없이 큰 할당을 하지 않도록 막는 상한
+    maxRecordBytes = 64 << 20
+)
+
+var ErrFull = errors.New("스풀 용량 초과")
+
+type Options struct {
+    Dir string
+    // 아직 Ack하지 않은 레코드의 바이트 상한. 넘으면 Append가 ErrFull.
+    // Ack했지만 아직 지우지 못한 head 세그먼트의 앞부분은 세지 않으므로 디스크 사용량은 세그먼트 하나만큼 더 클 수 있다
+    MaxBytes     int64
+    SegmentBytes int64 // 세그먼트 하나가 이 크기를 넘으면 새 세그먼트로 넘어간다
+}
+
+type Stats struct {
+    Segments     int   `json:"segments"`
+    Bytes        int64 `json:"bytes"`        // 세그먼트 파일 전체 크기
+    PendingBytes int64 `json:"pendingBytes"` // 그중 아직 Ack하지 않은 레코드 (MaxBytes와 비교하는 값)
+    MaxBytes     int64 `json:"maxBytes"`
+    Pending      int64 `json:"pending"`  // 아직 Ack하지 않은 레코드
+    Appended     int64 `json:"appended"` // 이번 실행에서 추가한 레코드
+    Drained      int64 `json:"drained"`  // 이번 실행에서 Ack한 레코드
+    Rejected     int64 `json:"rejected"` // 용량 초과로 받지 못한 레코드
+}
+
+// Read가 돌려주는 다음 읽기 위치. Ack에 그대로 넘긴다
+type Position struct {
+    segment int64
+    offset  int64
+    count   int64
+}
+
+type Spool struct {
+    opts Options
+    mu   sync.Mutex
+
+    segments []int64         // 오래된 순 세그먼트 번호
+    sizes    map[int64]int64 // 세그먼트별 파일 크기
+
+    writer *os.File // 마지막 세그먼트 (추가 전용)
+
+    headSegment int64
+    headOffset  int64
+
+    pending  int64
+    appended int64
+    drained  int64
+    rejected int64
+}
+
+func Open(opts Options) (*Spool, error) {
+    if opts.SegmentBytes <= 0 {
+        opts.SegmentBytes = 16 << 20
+    }
+    if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+        return nil, fmt.Errorf("스풀 디렉터리 생성 실패: %w", err)
+    }
+
+    s := &Spool{opts: opts, sizes: make(map[int64]int64)}
+    if err := s.load(); err != nil {
+        return nil, err
+    }
+    return s, nil
+}
+
+// 기존 세그먼트와 head 위치를 읽고, 남은 레코드 수를 세면서 손상된 꼬리를 잘라낸다
+func (s *Spool) load() error {
+    entries, err := os.ReadDir(s.opts.Dir)
+    if err != nil {
+        return fmt.Errorf("스풀 디렉터리 읽기 실패: %w", err)
+    }
+    for _, entry := range entries {
+        name := entry.Name()
+        if !strings.HasSuffix(name, segmentSuffix) {
+            continue
+        }
+        id, err := strconv.ParseInt(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+        if err != nil {
+            continue
+        }
+        s.segments = append(s.segments, id)
+    }
+    sort.Slice(s.segments, func(i, j int) bool { return s.segments[i] < s.segments[j] })
+
+    if err := s.readHead(); err != nil {
+        return err
+    }
+
+    // head 이전 세그먼트는 이미 처리된 것이므로 지운다 (Ack 직후 죽은 경우)
+    for len(s.segments) > 0 && s.segments[0] < s.headSegment {
+        os.Remove(s.segmentPath(s.segments[0]))
+        s.segments = s.segments[1:]
+    }
+    if len(s.segments) == 0 || s.segments[0] != s.headSegment {
+        s.headOffset = 0
+        if len(s.segments) > 0 {
+            s.headSegment = s.segments[0]
+        }
+    }
+
+    for _, id := range s.segments {
+        start := int64(0)
+        if id == s.headSegment {
+            start = s.headOffset
+        }
+        count, size, err := s.scanSegment(id, start)
+        if err != nil {
+            return err
+        }
+        s.pending += count
+        s.sizes[id] = size
+    }
+
+    // 세그먼트가 모두 지워졌어도 번호는 head 이후로 이어간다
+    next := s.headSegment
+    if next < 1 {
+        next = 1
+    }
+    if n := len(s.segments); n > 0 {
+        last := s.segments[n-1]
+        if s.sizes[last] < s.opts.SegmentBytes {
+            return s.openWriter(last)
+        }
+        next = last + 1
+    }
+    return s.openWriter(next)
+}
+
+func (s *Spool) readHead() error {
+    content, err := os.ReadFile(filepath.Join(s.opts.Dir, headFile))
+    if os.IsNotExist(err) {
+        if len(s.segments) > 0 {
+            s.headSegment = s.segments[0]
+        }
+        return nil
+    }
+    if err != nil {
+        return fmt.Errorf("스풀 head 읽기 실패: %w", err)
+    }
+    if _, err := fmt.Sscanf(string(content), "%d %d", &s.headSegment, &s.headOffset); err != nil {
+        return fmt.Errorf("스풀 head 형식 오류: %w", err)
+    }
+    return nil
+}
+
+// start부터 유효한 레코드 수를 세고, 깨진 레코드가 나오면 그 위치에서 파일을 자른다
+func (s *Spool) scanSegment(id, start int64) (count, size int64, err error) {
+    path := s.segmentPath(id)
+    f, err := os.Open(path)
+    if err != nil {
+        return 0, 0, fmt.Errorf("스풀 세그먼트 열기 실패: %w", err)
+    }
+    defer f.Close()
+
+    info, err := f.Stat()
+    if err != nil {
+        return 0, 0, err
+    }
+    if start > info.Size() {
+        start = info.Size()
+        s.headOffset = start
+    }
+    if _, err := f.Seek(start, io.SeekStart); err != nil {
+        return 0, 0, err
+    }
+    r := bufio.NewReader(f)
+    offset := start
+    for {
+        payload, err := readRecord(r)
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            log.Printf("⚠️ 스풀 세그먼트 %d 손상된 꼬리 %d바이트 잘라냄: %v", id, info.Size()-offset, err)
+            if err := os.Truncate(path, offset); err != nil {
+                return 0, 0, fmt.Errorf("스풀 세그먼트 복구 실패: %w", err)
+            }
+            break
+        }
+        offset += headerSize + int64(len(payload))
+        count++
+    }
+    return count, offset, nil
+}
+
+func (s *Spool) openWriter(id int64) error {
+    f, err := os.OpenFile(s.segmentPath(id), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+    if err != nil {
+        return fmt.Errorf("스풀 세그먼트 생성 실패: %w", err)
+    }
+    if _, exists := s.sizes[id]; !exists {
+        s.segments = append(s.segments, id)
+        s.sizes[id] = 0
+        if len(s.segments) == 1 {
+            s.headSegment, s.headOffset = id, 0
+        }
+    }
+    s.writer = f
+    return nil
+}
+
+func (s *Spool) segmentPath(id int64) string {
+    return filepath.Join(s.opts.Dir, fmt.Sprintf("%020d%s", id, segmentSuffix))
+}
+
+// 레코드를 순서대로 추가하고 fsync까지 마친 뒤 반환한다. 전부 넣을 수 없으면 하나도 넣지 않는다
+func (s *Spool) Append(payloads ...[]byte) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    var buf []byte
+    for _, p := range payloads {
+        var header [headerSize]byte
+        binary.BigEndian.PutUint32(header[0:4], uint32(len(p)))
+        binary.BigEndian.PutUint32(header[4:8], crc32.ChecksumIEEE(p))
+        buf = append(buf, header[:]...)
+        buf = append(buf, p...)
+    }
+
+    if s.opts.MaxBytes > 0 && s.pendingBytes()+int64(len(buf)) > s.opts.MaxBytes {
+        s.rejected += int64(len(payloads))
+        return ErrFull
+    }
+
+    current := s.segments[len(s.segments)-1]
+    if s.sizes[current] > 0 && s.sizes[current]+int64(len(buf)) > s.opts.SegmentBytes {
+        if err := s.rotate(); err != nil {
+            return err
+        }
+        current = s.segments[len(s.segments)-1]
+    }
+
+    if _, err := s.writer.Write(buf); err != nil {
+        // 일부만 쓰였을 수 있으므로 잘라내서 다음 레코드 위치가 어긋나지 않게 한다
+        s.writer.Truncate(s.sizes[current])
+        return fmt.Errorf("스풀 쓰기 실패: %w", err)
+    }
+    if err := s.writer.Sync(); err != nil {
+        return fmt.Errorf("스풀 fsync 실패: %w", err)
+    }
+
+    s.sizes[current] += int64(len(buf))
+    s.pending += int64(len(payloads))
+    s.appended += int64(len(payloads))
+    return nil
+}
+
+func (s *Spool) rotate() error {
+    if err := s.writer.Close(); err != nil {
+        return fmt.Errorf("스풀 세그먼트 닫기 실패: %w", err)
+    }
+    return s.openWriter(s.segments[len(s.segments)-1] + 1)
+}
+
+// head부터 최대 max개 레코드를 읽는다. 읽은 레코드는 Ack하기 전까지 다시 읽힌다
+func (s *Spool) Read(max int) ([][]byte, Position, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    pos := Position{segment: s.headSegment, offset: s.headOffset}
+    var records [][]byte
+    for i := 0; i < len(s.segments) && len(records) < max; i++ {
+        id := s.segments[i]
+        if id < pos.segment {
+            continue
+        }
+        if id > pos.segment {
+            // 앞 세그먼트를 끝까지 읽었으면 다음 세그먼트 처음으로
+            if pos.offset < s.sizes[pos.segment] {
+                break
+            }
+            pos.segment, pos.offset = id, 0
+        }
+
+        f, err := os.Open(s.segmentPath(id))
+        if err != nil {
+            return nil, Position{}, fmt.Errorf("스풀 세그먼트 열기 실패: %w", err)
+        }
+        if _, err := f.Seek(pos.offset, io.SeekStart); err != nil {
+            f.Close()
+            return nil, Position{}, err
+        }
+        r := bufio.NewReader(io.LimitReader(f, s.sizes[id]-pos.offset))
+        for len(records) < max {
+            payload, err := readRecord(r)
+            if err == io.EOF {
+                break
+            }
+            if err != nil {
+                f.Close()
+                return nil, Position{}, fmt.Errorf("스풀 세그먼트 %d 읽기 실패: %w", id, err)
+            }
+            records = append(records, payload)
+            pos.offset += headerSize + int64(len(payload))
+            pos.count++
+        }
+        f.Close()
+    }
+    return records, pos, nil
+}
+
+// Read로 받은 레코드를 처리했음을 기록. head를 옮긴 뒤 다 읽은 세그먼트를 지운다
+func (s *Spool) Ack(pos Position) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    if pos.count == 0 {
+        return nil
+    }
+
+    // 다 읽은 세그먼트가 쓰기 중인 세그먼트가 아니면 다음 세그먼트 처음을 head로
+    last := s.segments[len(s.segments)-1]
+    if pos.segment != last && pos.offset >= s.sizes[pos.segment] {
+        for _, id := range s.segments {
+            if id > pos.segment {
+                pos.segment, pos.offset = id, 0
+                break
+            }
+        }
+    }
+
+    if err := s.writeHead(pos.segment, pos.offset); err != nil {
+        return err
+    }
+    s.headSegment, s.headOffset = pos.segment, pos.offset
+    s.pending -= pos.count
+    s.drained += pos.count
+
+    for len(s.segments) > 1 && s.segments[0] < s.headSegment {
+        id := s.segments[0]
+        if err := os.Remove(s.segmentPath(id)); err != nil && !os.IsNotExist(err) {
+            log.Printf("⚠️ 스풀 세그먼트 %d 삭제 실패: %v", id, err)
+        }
+        delete(s.sizes, id)
+        s.segments = s.segments[1:]
+    }
+    return nil
+}
+
+// 임시 파일에 쓰고 rename해서 head가 절반만 기록되는 일이 없게 한다
+func (s *Spool) writeHead(segment, offset int64) error {
+    path := filepath.Join(s.opts.Dir, headFile)
+    tmp := path + ".tmp"
+
+    f, err := os.Create(tmp)
+    if err != nil {
+        return fmt.Errorf("스풀 head 기록 실패: %w", err)
+    }
+    if _, err := fmt.Fprintf(f, "%d %d\n", segment, offset); err != nil {
+        f.Close()
+        return fmt.Errorf("스풀 head 기록 실패: %w", err)
+    }
+    if err := f.Sync(); err != nil {
+        f.Close()
+        return fmt.Errorf("스풀 head fsync 실패: %w", err)
+    }
+    if err := f.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp, path)
+}
+
+func (s *Spool) Pending() int64 {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    return s.pending
+}
+
+func (s *Spool) Stats() Stats {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    return Stats{
+        Segments:     len(s.segments),
+        Bytes:        s.totalBytes(),
+        PendingBytes: s.pendingBytes(),
+        MaxBytes:     s.opts.MaxBytes,
+        Pending:      s.pending,
+        Appended:     s.appended,
+        Drained:      s.drained,
+        Rejected:     s.rejected,
+    }
+}
+
+// s.mu를 잡은 상태에서 호출
+func (s *Spool) totalBytes() int64 {
+    var total int64
+    for _, size := range s.sizes {
+        total += size
+    }
+    return total
+}
+
+// s.mu를 잡은 상태에서 호출. head 이전 세그먼트는 Ack 때 지우므로 head 세그먼트의 앞부분만 빼면 된다
+func (s *Spool) pendingBytes() int64 {
+    return s.totalBytes() - s.headOffset
+}
+
+func (s *Spool) Close() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+
+    return s.writer.Close()
+}
+
+func readRecord(r io.Reader) ([]byte, error) {
+    var header [headerSize]byte
+    if _, err := io.ReadFull(r, header[:]); err != nil {
+        if err == io.EOF {
+            return nil, io.EOF
+        }
+        return nil, fmt.Errorf("레코드 헤더 잘림: %w", err)
+    }
+
+    length := binary.BigEndian.Uint32(header[0:4])
+    if length > maxRecordBytes {
+        return nil, fmt.Errorf("레코드 길이 비정상: %d", length)
+    }
+    payload := make([]byte, length)
+    if _, err := io.ReadFull(r, payload); err != nil {
+        return nil, fmt.Errorf("레코드 본문 잘림: %w", err)
+    }
+    if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+        return nil, errors.New("레코드 CRC 불일치")
+    }
+    return payload, nil
+}
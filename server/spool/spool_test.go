@@ -0,0 +1,192 @@
+package spool
+
+import (
+    "os"
+    "strconv"
+    "testing"
+)
+
+func openTestSpool(t *testing.T, dir string) *Spool {
+    t.Helper()
+
+    s, err := Open(Options{Dir: dir, MaxBytes: 1 << 20, SegmentBytes: 64})
+    if err != nil {
+        t.Fatalf("스풀 열기 실패: %v", err)
+    }
+    return s
+}
+
+func appendRecords(t *testing.T, s *Spool, from, to int) {
+    t.Helper()
+
+    for i := from; i < to; i++ {
+        if err := s.Append([]byte("record-" + strconv.Itoa(i))); err != nil {
+            t.Fatalf("레코드 %d 추가 실패: %v", i, err)
+        }
+    }
+}
+
+func readAll(t *testing.T, s *Spool) ([]string, Position) {
+    t.Helper()
+
+    records, pos, err := s.Read(1000)
+    if err != nil {
+        t.Fatalf("스풀 읽기 실패: %v", err)
+    }
+    got := make([]string, len(records))
+    for i, r := range records {
+        got[i] = string(r)
+    }
+    return got, pos
+}
+
+func expectRecords(t *testing.T, got []string, from, to int) {
+    t.Helper()
+
+    if len(got) != to-from {
+        t.Fatalf("레코드 %d건, 기대값 %d건: %v", len(got), to-from, got)
+    }
+    for i, r := range got {
+        if want := "record-" + strconv.Itoa(from+i); r != want {
+            t.Fatalf("%d번째 레코드 = %q, 기대값 %q", i, r, want)
+        }
+    }
+}
+
+// Ack하지 않은 레코드는 재시작 후 다시 읽힌다
+func TestSpoolReplaysUnackedRecordsAfterRestart(t *testing.T) {
+    dir := t.TempDir()
+
+    s := openTestSpool(t, dir)
+    appendRecords(t, s, 0, 10)
+    records, pos, err := s.Read(4)
+    if err != nil || len(records) != 4 {
+        t.Fatalf("앞 4건 읽기 실패: %d건, %v", len(records), err)
+    }
+    if err := s.Ack(pos); err != nil {
+        t.Fatalf("Ack 실패: %v", err)
+    }
+    // 읽기만 하고 Ack 전에 죽은 경우
+    s.Read(3)
+    s.Close()
+
+    s = openTestSpool(t, dir)
+    defer s.Close()
+    if pending := s.Pending(); pending != 6 {
+        t.Fatalf("재시작 후 남은 레코드 %d건, 기대값 6건", pending)
+    }
+    got, pos := readAll(t, s)
+    expectRecords(t, got, 4, 10)
+
+    if err := s.Ack(pos); err != nil {
+        t.Fatalf("Ack 실패: %v", err)
+    }
+    if pending := s.Pending(); pending != 0 {
+        t.Fatalf("모두 Ack한 뒤 남은 레코드 %d건", pending)
+    }
+    // 다 읽은 세그먼트는 지워지고 쓰기 중인 세그먼트만 남는다
+    if stats := s.Stats(); stats.Segments != 1 {
+        t.Fatalf("남은 세그먼트 %d개, 기대값 1개", stats.Segments)
+    }
+}
+
+// 쓰는 도중에 죽어 잘린 꼬리는 재시작 시 잘라내고, 그 뒤로 이어서 쓴다
+func TestSpoolTruncatesTornTailOnRestart(t *testing.T) {
+    cases := map[string][]byte{
+        "헤더 잘림":      {0, 0, 0},
+        "payload 잘림": {0, 0, 0, 20, 0, 0, 0, 0, 'r', 'e'},
+        "CRC 불일치":    {0, 0, 0, 2, 0xde, 0xad, 0xbe, 0xef, 'x', 'y'},
+    }
+
+    for name, tail := range cases {
+        t.Run(name, func(t *testing.T) {
+            dir := t.TempDir()
+
+            s := openTestSpool(t, dir)
+            appendRecords(t, s, 0, 5)
+            last := s.segments[len(s.segments)-1]
+            s.Close()
+
+            f, err := os.OpenFile(s.segmentPath(last), os.O_WRONLY|os.O_APPEND, 0o644)
+            if err != nil {
+                t.Fatalf("세그먼트 열기 실패: %v", err)
+            }
+            f.Write(tail)
+            f.Close()
+
+            s = openTestSpool(t, dir)
+            defer s.Close()
+            if pending := s.Pending(); pending != 5 {
+                t.Fatalf("복구 후 남은 레코드 %d건, 기대값 5건", pending)
+            }
+
+            appendRecords(t, s, 5, 8)
+            got, _ := readAll(t, s)
+            expectRecords(t, got, 0, 8)
+        })
+    }
+}
+
+// Ack 직후 세그먼트를 지우기 전에 죽었으면 재시작 시 지난 세그먼트를 정리한다
+func TestSpoolRemovesDrainedSegmentsOnRestart(t *testing.T) {
+    dir := t.TempDir()
+
+    s := openTestSpool(t, dir)
+    appendRecords(t, s, 0, 10)
+    first := s.segments[0]
+    firstBytes, err := os.ReadFile(s.segmentPath(first))
+    if err != nil {
+        t.Fatalf("세그먼트 읽기 실패: %v", err)
+    }
+    records, pos, err := s.Read(1000)
+    if err != nil || len(records) != 10 {
+        t.Fatalf("전체 읽기 실패: %d건, %v", len(records), err)
+    }
+    appendRecords(t, s, 10, 12)
+    if err := s.Ack(pos); err != nil {
+        t.Fatalf("Ack 실패: %v", err)
+    }
+    s.Close()
+
+    // 지워졌어야 할 세그먼트를 되살린다
+    if err := os.WriteFile(s.segmentPath(first), firstBytes, 0o644); err != nil {
+        t.Fatalf("세그먼트 복원 실패: %v", err)
+    }
+
+    s = openTestSpool(t, dir)
+    defer s.Close()
+    got, _ := readAll(t, s)
+    expectRecords(t, got, 10, 12)
+    if _, err := os.Stat(s.segmentPath(first)); !os.IsNotExist(err) {
+        t.Fatalf("처리한 세그먼트가 남아 있음: %v", err)
+    }
+}
+
+// 용량 상한은 Ack하지 않은 레코드에만 적용된다. Ack한 뒤 세그먼트가 남아 있어도 다시 받는다
+func TestSpoolLimitsUnackedBytesOnly(t *testing.T) {
+    s, err := Open(Options{Dir: t.TempDir(), MaxBytes: 100, SegmentBytes: 1 << 20})
+    if err != nil {
+        t.Fatalf("스풀 열기 실패: %v", err)
+    }
+    defer s.Close()
+
+    // 레코드 하나가 헤더 포함 16바이트 (record-10부터는 17바이트)
+    appendRecords(t, s, 0, 6)
+    if err := s.Append([]byte("record-6")); err != ErrFull {
+        t.Fatalf("상한을 넘는 추가 결과 %v, 기대값 ErrFull", err)
+    }
+
+    got, pos := readAll(t, s)
+    expectRecords(t, got, 0, 6)
+    if err := s.Ack(pos); err != nil {
+        t.Fatalf("Ack 실패: %v", err)
+    }
+
+    appendRecords(t, s, 6, 12)
+    stats := s.Stats()
+    if stats.Segments != 1 || stats.Bytes != 194 || stats.PendingBytes != 98 || stats.Rejected != 1 {
+        t.Fatalf("스풀 상태 이상: %+v", stats)
+    }
+    got, _ = readAll(t, s)
+    expectRecords(t, got, 6, 12)
+}
@@ -0,0 +1,136 @@
+package topics
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log"
+    "time"
+
+    "shinhan-eyetracking/server/config"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// 토픽 하나의 기대 설정
+type Spec struct {
+    Name              string
+    Partitions        int // 0이면 기존 토픽의 파티션 수를 그대로 쓰고, 새로 만들 때는 1개
+    ReplicationFactor int
+}
+
+// 메인 서버, Consumer, DLQ 도구가 같은 기준으로 확인하는 토픽 목록
+func FromConfig(cfg *config.Config) []Spec {
+    return []Spec{
+        {Name: cfg.GazeTopic, Partitions: int(cfg.KafkaPartitions), ReplicationFactor: int(cfg.KafkaReplicationFactor)},
+        {Name: cfg.DLQTopic, Partitions: int(cfg.KafkaPartitions), ReplicationFactor: int(cfg.KafkaReplicationFactor)},
+    }
+}
+
+// 토픽이 없으면 만들고(create가 false면 오류), 있으면 파티션 수와 복제 계수가 설정에 맞는지 확인한다.
+// 파티션 수를 바꾸면 세션 키의 파티션 배치가 달라지므로 자동으로 늘리지 않는다. 설정보다 적으면 오류, 많으면 경고만 남긴다
+func Ensure(ctx context.Context, brokers string, create bool, specs ...Spec) error {
+    ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+    defer cancel()
+
+    client := &kafka.Client{Addr: kafka.TCP(brokers)}
+
+    names := make([]string, len(specs))
+    for i, spec := range specs {
+        names[i] = spec.Name
+    }
+    existing, err := describe(ctx, client, names)
+    if err != nil {
+        return err
+    }
+
+    var missing []kafka.TopicConfig
+    var problems []error
+    for _, spec := range specs {
+        topic, exists := existing[spec.Name]
+        if !exists {
+            partitions := spec.Partitions
+            if partitions <= 0 {
+                partitions = 1
+            }
+            missing = append(missing, kafka.TopicConfig{
+                Topic:             spec.Name,
+                NumPartitions:     partitions,
+                ReplicationFactor: spec.ReplicationFactor,
+            })
+            continue
+        }
+        if err := verify(spec, topic); err != nil {
+            problems = append(problems, err)
+        }
+    }
+    if len(problems) > 0 {
+        return errors.Join(problems...)
+    }
+    if len(missing) == 0 {
+        return nil
+    }
+
+    if !create {
+        var missingNames []string
+        for _, t := range missing {
+            missingNames = append(missingNames, t.Topic)
+        }
+        return fmt.Errorf("토픽 없음: %v (KAFKA_CREATE_TOPICS=false)", missingNames)
+    }
+
+    resp, err := client.CreateTopics(ctx, &kafka.CreateTopicsRequest{Topics: missing})
+    if err != nil {
+        return fmt.Errorf("토픽 생성 요청 실패: %w", err)
+    }
+    for _, t := range missing {
+        err := resp.Errors[t.Topic]
+        // 다른 프로세스가 먼저 만든 경우는 성공으로 본다
+        if err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+            problems = append(problems, fmt.Errorf("토픽 %s 생성 실패: %w", t.Topic, err))
+            continue
+        }
+        if err == nil {
+            log.Printf("✅ 토픽 생성됨: %s (파티션 %d, 복제 %d)", t.Topic, t.NumPartitions, t.ReplicationFactor)
+        }
+    }
+    return errors.Join(problems...)
+}
+
+// 존재하는 토픽만 담아 반환 (자동 생성을 유발하지 않는 메타데이터 요청)
+func describe(ctx context.Context, client *kafka.Client, names []string) (map[string]kafka.Topic, error) {
+    resp, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: names})
+    if err != nil {
+        return nil, fmt.Errorf("Kafka 메타데이터 조회 실패: %w", err)
+    }
+
+    existing := make(map[string]kafka.Topic, len(resp.Topics))
+    for _, topic := range resp.Topics {
+        if errors.Is(topic.Error, kafka.UnknownTopicOrPartition) {
+            continue
+        }
+        if topic.Error != nil {
+            return nil, fmt.Errorf("토픽 %s 조회 실패: %w", topic.Name, topic.Error)
+        }
+        existing[topic.Name] = topic
+    }
+    return existing, nil
+}
+
+func verify(spec Spec, topic kafka.Topic) error {
+    if spec.Partitions > 0 && len(topic.Partitions) < spec.Partitions {
+        return fmt.Errorf("토픽 %s 파티션 수 부족: 실제 %d, 설정 %d (KAFKA_PARTITIONS). 진행 중인 세션이 없을 때 kafka-topics --alter로 늘린다",
+            spec.Name, len(topic.Partitions), spec.Partitions)
+    }
+    if spec.Partitions > 0 && len(topic.Partitions) > spec.Partitions {
+        log.Printf("⚠️ 토픽 %s 파티션이 설정보다 많음: 실제 %d, 설정 %d (KAFKA_PARTITIONS)",
+            spec.Name, len(topic.Partitions), spec.Partitions)
+    }
+    for _, p := range topic.Partitions {
+        if len(p.Replicas) != spec.ReplicationFactor {
+            return fmt.Errorf("토픽 %s 복제 계수 불일치: 파티션 %d 실제 %d, 설정 %d (KAFKA_REPLICATION_FACTOR)",
+                spec.Name, p.ID, len(p.Replicas), spec.ReplicationFactor)
+        }
+    }
+    return nil
+}
@@ -0,0 +1,62 @@
+package topics
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+
+    "shinhan-eyetracking/server/config"
+
+    "github.com/segmentio/kafka-go"
+)
+
+// 시선 토픽과 DLQ 토픽 모두 같은 파티션 수와 복제 계수로 확인한다
+func TestFromConfig(t *testing.T) {
+    cfg := &config.Config{GazeTopic: "gaze-data", DLQTopic: "gaze-data-dlq", KafkaPartitions: 6, KafkaReplicationFactor: 3}
+
+    want := []Spec{
+        {Name: "gaze-data", Partitions: 6, ReplicationFactor: 3},
+        {Name: "gaze-data-dlq", Partitions: 6, ReplicationFactor: 3},
+    }
+    if got := FromConfig(cfg); !reflect.DeepEqual(got, want) {
+        t.Fatalf("토픽 목록 = %+v, 기대값 %+v", got, want)
+    }
+}
+
+func TestVerify(t *testing.T) {
+    spec := Spec{Name: "gaze-data", Partitions: 3, ReplicationFactor: 2}
+    topic := func(partitions, replicas int) kafka.Topic {
+        result := kafka.Topic{Name: "gaze-data"}
+        for id := 0; id < partitions; id++ {
+            result.Partitions = append(result.Partitions, kafka.Partition{ID: id, Replicas: make([]kafka.Broker, replicas)})
+        }
+        return result
+    }
+
+    cases := []struct {
+        name   string
+        spec   Spec
+        topic  kafka.Topic
+        expect string // 비어 있으면 통과
+    }{
+        {"설정과 같음", spec, topic(3, 2), ""},
+        {"파티션이 설정보다 많으면 경고만", spec, topic(6, 2), ""},
+        {"파티션이 설정보다 적음", spec, topic(1, 2), "파티션 수 부족"},
+        {"파티션 설정 없으면 기존 토픽 그대로", Spec{Name: "gaze-data", ReplicationFactor: 2}, topic(1, 2), ""},
+        {"복제 계수 다름", spec, topic(3, 1), "복제 계수 불일치"},
+    }
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            err := verify(c.spec, c.topic)
+            if c.expect == "" {
+                if err != nil {
+                    t.Fatalf("설정과 같은 토픽이 거부됨: %v", err)
+                }
+                return
+            }
+            if err == nil || !strings.Contains(err.Error(), c.expect) {
+                t.Fatalf("오류 = %v, 기대값 %q 포함", err, c.expect)
+            }
+        })
+    }
+}
@@ -0,0 +1,262 @@
+package validation
+
+import (
+    "fmt"
+    "log"
+    "math"
+    "sync"
+    "time"
+
+    "shinhan-eyetracking/server/catalog"
+    "shinhan-eyetracking/server/models"
+)
+
+// 시선 샘플 거부 사유
+type Reason string
+
+const (
+    ReasonMalformed          Reason = "malformed"           // JSON 해석 실패, timestamp 누락 등
+    ReasonNonFinite          Reason = "non_finite"          // NaN, Inf 좌표
+    ReasonBadViewport        Reason = "bad_viewport"        // 뷰포트 크기가 비정상
+    ReasonOutOfBounds        Reason = "out_of_bounds"       // 뷰포트(또는 기본 한계) 밖 좌표
+    ReasonNotMonotonic       Reason = "not_monotonic"       // 직전 샘플보다 같거나 이전 시각
+    ReasonClockSkew          Reason = "clock_skew"          // 서버 시각과 차이가 너무 큼
+    ReasonUnknownLocation    Reason = "unknown_location"    // 카탈로그에 없는 페이지/섹션
+    ReasonSessionUnavailable Reason = "session_unavailable" // 저장소에서 세션을 가져오지 못함 (다음 샘플에서 다시 조회)
+)
+
+const (
+    // 뷰포트를 보고하지 않은 클라이언트에 적용하는 좌표 한계
+    maxCoordinate = 10000
+    maxViewport   = 10000
+    // 거부 사유를 보낸 쪽에 알리는 최소 간격
+    reportInterval = time.Second
+)
+
+type Config struct {
+    MaxClockSkew time.Duration // 클라이언트 timestamp와 서버 시각의 허용 차이
+    BoundsMargin float64       // 뷰포트 밖으로 허용하는 여유 (px)
+}
+
+// 거부된 샘플. error로도 쓸 수 있다
+type Rejection struct {
+    Reason Reason
+    Detail string
+}
+
+func (r *Rejection) Error() string {
+    return fmt.Sprintf("%s: %s", r.Reason, r.Detail)
+}
+
+type sessionState struct {
+    lastTimestamp int64
+    productID     string // 세션의 상품. 페이지/섹션은 이 상품의 약관 안에서만 받는다
+    restored      bool   // 저장소에서 상품과 마지막 timestamp를 가져왔는지
+    rejected      map[Reason]int64
+    lastReport    time.Time
+}
+
+// 핸들러와 GazeService 사이의 시선 샘플 검증 단계. 세션별 마지막 timestamp와 거부 건수를 기억한다
+type GazeValidator struct {
+    catalog  *catalog.Catalog
+    config   Config
+    source   SessionSource
+    sessions map[string]*sessionState
+    totals   map[Reason]int64
+    mu       sync.Mutex
+}
+
+// 세션 상태를 들일 때 조회하는 저장소 (database.Store가 구현)
+type SessionSource interface {
+    GetSession(sessionID string) (*models.Session, error)
+    GetLastGazeTimestamp(sessionID string) (int64, error)
+}
+
+func NewGazeValidator(terms *catalog.Catalog, config Config) *GazeValidator {
+    return &GazeValidator{
+        catalog:  terms,
+        config:   config,
+        sessions: make(map[string]*sessionState),
+        totals:   make(map[Reason]int64),
+    }
+}
+
+// 세션 저장소 등록 (서버 시작 전에). 처음 보거나 Forget으로 내린 세션은 저장된 상품과 마지막 시선 timestamp를 가져와,
+// 다른 상품의 페이지를 거부하고 재연결한 클라이언트가 이전 시각의 샘플을 다시 보내지 못하게 한다.
+// 등록하지 않으면 카탈로그 전체의 페이지를 받는다
+func (v *GazeValidator) RestoreFrom(source SessionSource) {
+    v.source = source
+}
+
+// 샘플을 검증하고 통과하면 세션의 마지막 timestamp를 갱신한다. 거부되면 *Rejection을 반환
+func (v *GazeValidator) Validate(sessionID string, g models.GazeData, now time.Time) *Rejection {
+    productID, rejection := v.restore(sessionID)
+    if rejection == nil {
+        rejection = v.check(productID, g, now)
+    }
+
+    v.mu.Lock()
+    defer v.mu.Unlock()
+
+    state := v.session(sessionID)
+    if rejection == nil && g.Timestamp <= state.lastTimestamp {
+        rejection = &Rejection{ReasonNotMonotonic,
+            fmt.Sprintf("timestamp %d <= 직전 %d", g.Timestamp, state.lastTimestamp)}
+    }
+    if rejection != nil {
+        state.rejected[rejection.Reason]++
+        v.totals[rejection.Reason]++
+        return rejection
+    }
+
+    state.lastTimestamp = g.Timestamp
+    return nil
+}
+
+// 디코딩 단계에서 실패한 샘플 기록
+func (v *GazeValidator) RecordMalformed(sessionID string, err error) *Rejection {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+
+    v.session(sessionID).rejected[ReasonMalformed]++
+    v.totals[ReasonMalformed]++
+    return &Rejection{ReasonMalformed, err.Error()}
+}
+
+// 페이지 변경이 세션 상품의 약관 페이지인지 확인
+func (v *GazeValidator) ValidatePage(sessionID, pageID string) error {
+    productID, rejection := v.restore(sessionID)
+    if rejection != nil {
+        return rejection
+    }
+    return v.catalog.ValidateProductPage(productID, pageID)
+}
+
+func (v *GazeValidator) check(productID string, g models.GazeData, now time.Time) *Rejection {
+    if math.IsNaN(g.X) || math.IsNaN(g.Y) || math.IsInf(g.X, 0) || math.IsInf(g.Y, 0) {
+        return &Rejection{ReasonNonFinite, "좌표가 유한한 값이 아님"}
+    }
+
+    maxX, maxY := float64(maxCoordinate), float64(maxCoordinate)
+    if g.ViewportWidth != 0 || g.ViewportHeight != 0 {
+        if g.ViewportWidth <= 0 || g.ViewportHeight <= 0 || g.ViewportWidth > maxViewport || g.ViewportHeight > maxViewport {
+            return &Rejection{ReasonBadViewport,
+                fmt.Sprintf("뷰포트 %dx%d", g.ViewportWidth, g.ViewportHeight)}
+        }
+        maxX, maxY = float64(g.ViewportWidth), float64(g.ViewportHeight)
+    }
+    margin := v.config.BoundsMargin
+    if g.X < -margin || g.Y < -margin || g.X > maxX+margin || g.Y > maxY+margin {
+        return &Rejection{ReasonOutOfBounds,
+            fmt.Sprintf("(%.0f, %.0f) 범위 0~%.0fx%.0f 밖", g.X, g.Y, maxX, maxY)}
+    }
+
+    if v.config.MaxClockSkew > 0 {
+        skew := time.Duration(g.Timestamp-now.UnixMilli()) * time.Millisecond
+        if skew > v.config.MaxClockSkew || -skew > v.config.MaxClockSkew {
+            return &Rejection{ReasonClockSkew,
+                fmt.Sprintf("서버 시각과 %s 차이", skew.Round(time.Millisecond))}
+        }
+    }
+
+    if err := v.catalog.ValidateGaze(productID, g.CurrentPage, g.SectionID); err != nil {
+        return &Rejection{ReasonUnknownLocation, err.Error()}
+    }
+    return nil
+}
+
+// 거부 사실을 보낸 쪽에 알릴 때인지 확인 (세션당 초당 1회)
+func (v *GazeValidator) ReportDue(sessionID string, now time.Time) bool {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+
+    state := v.session(sessionID)
+    if now.Sub(state.lastReport) < reportInterval {
+        return false
+    }
+    state.lastReport = now
+    return true
+}
+
+// 세션의 사유별 거부 건수
+func (v *GazeValidator) Rejected(sessionID string) map[Reason]int64 {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+
+    result := make(map[Reason]int64)
+    if state, exists := v.sessions[sessionID]; exists {
+        for reason, count := range state.rejected {
+            result[reason] = count
+        }
+    }
+    return result
+}
+
+// 서버 기동 이후 전체 사유별 거부 건수
+func (v *GazeValidator) Totals() map[Reason]int64 {
+    v.mu.Lock()
+    defer v.mu.Unlock()
+
+    result := make(map[Reason]int64, len(v.totals))
+    for reason, count := range v.totals {
+        result[reason] = count
+    }
+    return result
+}
+
+// 아직 들이지 않은 세션이면 저장소에서 상품과 마지막 timestamp(순서 검증의 하한)를 가져온다. 조회는 락 밖에서 하고 세션의 상품을 반환.
+// 가져오지 못하면 상품 범위와 하한 없이 받지 않도록 session_unavailable로 거부하고, 다음 샘플에서 다시 조회한다
+func (v *GazeValidator) restore(sessionID string) (string, *Rejection) {
+    if v.source == nil {
+        return "", nil
+    }
+    v.mu.Lock()
+    state := v.session(sessionID)
+    restored, productID := state.restored, state.productID
+    v.mu.Unlock()
+    if restored {
+        return productID, nil
+    }
+
+    session, err := v.source.GetSession(sessionID)
+    if err != nil {
+        log.Printf("⚠️ 세션 조회 실패 [%s]: %v", sessionID, err)
+        return "", &Rejection{ReasonSessionUnavailable, "세션 조회 실패"}
+    }
+    if session == nil {
+        log.Printf("⚠️ 저장소에 없는 세션 [%s]", sessionID)
+        return "", &Rejection{ReasonSessionUnavailable, "저장소에 없는 세션"}
+    }
+    last, err := v.source.GetLastGazeTimestamp(sessionID)
+    if err != nil {
+        log.Printf("⚠️ 저장된 마지막 시선 timestamp 조회 실패 [%s]: %v", sessionID, err)
+        return "", &Rejection{ReasonSessionUnavailable, "마지막 시선 timestamp 조회 실패"}
+    }
+
+    v.mu.Lock()
+    state = v.session(sessionID)
+    if last > state.lastTimestamp {
+        state.lastTimestamp = last
+    }
+    state.productID = session.ProductID
+    state.restored = true
+    v.mu.Unlock()
+    return session.ProductID, nil
+}
+
+// 세션 종료나 연결 해제로 세션 상태를 내릴 때 정리. 다시 들어오면 저장소에서 상품과 마지막 timestamp를 다시 가져온다
+func (v *GazeValidator) Forget(sessionID string) {
+    v.mu.Lock()
+    delete(v.sessions, sessionID)
+    v.mu.Unlock()
+}
+
+// v.mu를 잡은 상태에서 호출
+func (v *GazeValidator) session(sessionID string) *sessionState {
+    state, exists := v.sessions[sessionID]
+    if !exists {
+        state = &sessionState{rejected: make(map[Reason]int64)}
+        v.sessions[sessionID] = state
+    }
+    return state
+}
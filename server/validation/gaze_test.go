@@ -0,0 +1,327 @@
+package validation
+
+import (
+    "errors"
+    "math"
+    "testing"
+    "time"
+
+    "shinhan-eyetracking/server/catalog"
+    "shinhan-eyetracking/server/models"
+)
+
+var testNow = time.UnixMilli(1726000000000)
+
+func newTestValidator(t *testing.T) *GazeValidator {
+    t.Helper()
+
+    terms, err := catalog.Load("")
+    if err != nil {
+        t.Fatalf("카탈로그 로드 실패: %v", err)
+    }
+    return NewGazeValidator(terms, Config{MaxClockSkew: time.Minute, BoundsMargin: 20})
+}
+
+func strPtr(s string) *string {
+    return &s
+}
+
+func TestValidateReasons(t *testing.T) {
+    valid := func(modify func(g *models.GazeData)) models.GazeData {
+        g := models.GazeData{
+            X:              100,
+            Y:              200,
+            Timestamp:      testNow.UnixMilli(),
+            CurrentPage:    strPtr("productDetail"),
+            SectionID:      strPtr("product-overview"),
+            ViewportWidth:  1920,
+            ViewportHeight: 1080,
+        }
+        if modify != nil {
+            modify(&g)
+        }
+        return g
+    }
+
+    cases := []struct {
+        name   string
+        data   models.GazeData
+        reason Reason // 빈 값이면 통과
+    }{
+        {"정상", valid(nil), ""},
+        {"섹션 밖 시선", valid(func(g *models.GazeData) { g.SectionID = nil }), ""},
+        {"페이지 없음", valid(func(g *models.GazeData) { g.CurrentPage, g.SectionID = nil, nil }), ""},
+        {"NaN 좌표", valid(func(g *models.GazeData) { g.X = math.NaN() }), ReasonNonFinite},
+        {"무한대 좌표", valid(func(g *models.GazeData) { g.Y = math.Inf(-1) }), ReasonNonFinite},
+        {"음수 뷰포트", valid(func(g *models.GazeData) { g.ViewportWidth = -1 }), ReasonBadViewport},
+        {"한쪽만 보고한 뷰포트", valid(func(g *models.GazeData) { g.ViewportHeight = 0 }), ReasonBadViewport},
+        {"너무 큰 뷰포트", valid(func(g *models.GazeData) { g.ViewportWidth = maxViewport + 1 }), ReasonBadViewport},
+        {"여유 안쪽의 뷰포트 밖 좌표", valid(func(g *models.GazeData) { g.X, g.Y = -20, 1100 }), ""},
+        {"여유를 넘은 뷰포트 밖 좌표", valid(func(g *models.GazeData) { g.X = 1941 }), ReasonOutOfBounds},
+        {"여유를 넘은 음수 좌표", valid(func(g *models.GazeData) { g.Y = -21 }), ReasonOutOfBounds},
+        {"뷰포트 없는 큰 좌표", valid(func(g *models.GazeData) {
+            g.ViewportWidth, g.ViewportHeight, g.X = 0, 0, 5000
+        }), ""},
+        {"뷰포트 없는 한계 밖 좌표", valid(func(g *models.GazeData) {
+            g.ViewportWidth, g.ViewportHeight, g.X = 0, 0, maxCoordinate+21
+        }), ReasonOutOfBounds},
+        {"허용 범위 안의 시각 차이", valid(func(g *models.GazeData) { g.Timestamp -= time.Minute.Milliseconds() }), ""},
+        {"미래 시각", valid(func(g *models.GazeData) { g.Timestamp += time.Minute.Milliseconds() + 1 }), ReasonClockSkew},
+        {"과거 시각", valid(func(g *models.GazeData) { g.Timestamp = 1 }), ReasonClockSkew},
+        {"없는 페이지", valid(func(g *models.GazeData) { g.CurrentPage = strPtr("admin") }), ReasonUnknownLocation},
+        {"페이지에 없는 섹션", valid(func(g *models.GazeData) { g.SectionID = strPtr("risk-warning") }), ReasonUnknownLocation},
+        {"페이지 없이 섹션만", valid(func(g *models.GazeData) { g.CurrentPage = nil }), ReasonUnknownLocation},
+    }
+
+    for _, tc := range cases {
+        t.Run(tc.name, func(t *testing.T) {
+            v := newTestValidator(t)
+            rejection := v.Validate("s1", tc.data, testNow)
+
+            if tc.reason == "" {
+                if rejection != nil {
+                    t.Fatalf("정상 샘플을 거부: %v", rejection)
+                }
+                return
+            }
+            if rejection == nil || rejection.Reason != tc.reason {
+                t.Fatalf("거부 사유 %v, 기대값 %s", rejection, tc.reason)
+            }
+            if got := v.Rejected("s1")[tc.reason]; got != 1 {
+                t.Fatalf("세션 거부 건수 %d", got)
+            }
+        })
+    }
+}
+
+// 세션마다 timestamp가 계속 증가해야 하고, 거부된 샘플은 기준 시각을 바꾸지 않는다
+func TestValidateRequiresIncreasingTimestamps(t *testing.T) {
+    v := newTestValidator(t)
+    at := func(ts int64) models.GazeData {
+        return models.GazeData{X: 1, Y: 1, Timestamp: testNow.UnixMilli() + ts}
+    }
+
+    steps := []struct {
+        sessionID string
+        data      models.GazeData
+        reason    Reason
+    }{
+        {"s1", at(0), ""},
+        {"s1", at(20), ""},
+        {"s1", at(20), ReasonNotMonotonic},
+        {"s1", at(10), ReasonNotMonotonic},
+        // 시각 차이로 거부된 샘플이 기준을 미래로 옮기지 않는다
+        {"s1", at(time.Hour.Milliseconds()), ReasonClockSkew},
+        {"s1", at(40), ""},
+        // 다른 세션은 따로 센다
+        {"s2", at(0), ""},
+    }
+
+    for i, step := range steps {
+        rejection := v.Validate(step.sessionID, step.data, testNow)
+        if step.reason == "" && rejection != nil || step.reason != "" && (rejection == nil || rejection.Reason != step.reason) {
+            t.Fatalf("%d단계 결과 %v, 기대값 %q", i, rejection, step.reason)
+        }
+    }
+
+    rejected := v.Rejected("s1")
+    if rejected[ReasonNotMonotonic] != 2 || rejected[ReasonClockSkew] != 1 || len(v.Rejected("s2")) != 0 {
+        t.Fatalf("세션별 거부 건수 이상: s1 %v, s2 %v", rejected, v.Rejected("s2"))
+    }
+
+    v.Forget("s1")
+    if rejection := v.Validate("s1", at(0), testNow); rejection != nil {
+        t.Fatalf("정리한 세션의 이전 timestamp가 남아 있음: %v", rejection)
+    }
+    if len(v.Rejected("s1")) != 0 {
+        t.Fatalf("정리한 세션의 거부 건수가 남아 있음: %v", v.Rejected("s1"))
+    }
+    // 전체 건수는 세션을 정리해도 남는다
+    if totals := v.Totals(); totals[ReasonNotMonotonic] != 2 || totals[ReasonClockSkew] != 1 {
+        t.Fatalf("전체 거부 건수 이상: %v", totals)
+    }
+}
+
+func TestRecordMalformed(t *testing.T) {
+    v := newTestValidator(t)
+
+    rejection := v.RecordMalformed("s1", errors.New("timestamp 누락"))
+    if rejection.Reason != ReasonMalformed || rejection.Error() != "malformed: timestamp 누락" {
+        t.Fatalf("거부 결과 이상: %v", rejection)
+    }
+    if v.Rejected("s1")[ReasonMalformed] != 1 || v.Totals()[ReasonMalformed] != 1 {
+        t.Fatalf("해석 실패 건수 이상: %v, %v", v.Rejected("s1"), v.Totals())
+    }
+}
+
+// 거부 알림은 세션당 초당 한 번
+func TestReportDue(t *testing.T) {
+    v := newTestValidator(t)
+
+    steps := []struct {
+        sessionID string
+        after     time.Duration
+        due       bool
+    }{
+        {"s1", 0, true},
+        {"s1", 500 * time.Millisecond, false},
+        {"s2", 500 * time.Millisecond, true},
+        {"s1", reportInterval, true},
+        {"s1", reportInterval + 999*time.Millisecond, false},
+        {"s1", 2 * reportInterval, true},
+    }
+
+    for i, step := range steps {
+        if due := v.ReportDue(step.sessionID, testNow.Add(step.after)); due != step.due {
+            t.Fatalf("%d단계 ReportDue = %v, 기대값 %v", i, due, step.due)
+        }
+    }
+}
+
+// 세션과 저장된 마지막 timestamp를 돌려주는 테스트용 저장소
+type fakeSource struct {
+    products map[string]string // 세션ID -> 상품ID
+    stored   map[string]int64
+    err      error // GetSession 실패
+    lastErr  error // GetLastGazeTimestamp 실패
+    lookups  int
+}
+
+func (f *fakeSource) GetSession(sessionID string) (*models.Session, error) {
+    f.lookups++
+    if f.err != nil {
+        return nil, f.err
+    }
+    productID, ok := f.products[sessionID]
+    if !ok {
+        return nil, nil
+    }
+    return &models.Session{ID: sessionID, ProductID: productID}, nil
+}
+
+func (f *fakeSource) GetLastGazeTimestamp(sessionID string) (int64, error) {
+    if f.lastErr != nil {
+        return 0, f.lastErr
+    }
+    return f.stored[sessionID], nil
+}
+
+// 내렸다가 다시 들어온 세션은 저장된 마지막 timestamp 이후의 샘플만 받는다
+func TestValidateRestoresFloorFromStorage(t *testing.T) {
+    v := newTestValidator(t)
+    source := &fakeSource{
+        products: map[string]string{"s1": "shinhan-global-multi-asset"},
+        stored:   map[string]int64{"s1": testNow.UnixMilli() + 100},
+    }
+    v.RestoreFrom(source)
+    at := func(ts int64) models.GazeData {
+        return models.GazeData{X: 1, Y: 1, Timestamp: testNow.UnixMilli() + ts}
+    }
+
+    if rejection := v.Validate("s1", at(50), testNow); rejection == nil || rejection.Reason != ReasonNotMonotonic {
+        t.Fatalf("저장된 샘플보다 이전 시각이 통과함: %v", rejection)
+    }
+    if rejection := v.Validate("s1", at(120), testNow); rejection != nil {
+        t.Fatalf("저장된 샘플 이후 시각이 거부됨: %v", rejection)
+    }
+
+    // 연결 해제로 내린 뒤 재연결해 이전 샘플을 다시 보내도 통과하지 않는다
+    v.Forget("s1")
+    source.stored["s1"] = testNow.UnixMilli() + 120
+    if rejection := v.Validate("s1", at(110), testNow); rejection == nil || rejection.Reason != ReasonNotMonotonic {
+        t.Fatalf("재연결 후 이전 시각이 통과함: %v", rejection)
+    }
+    if rejection := v.Validate("s1", at(130), testNow); rejection != nil {
+        t.Fatalf("재연결 후 새 샘플이 거부됨: %v", rejection)
+    }
+    if source.lookups != 2 {
+        t.Fatalf("저장소 조회 %d회, 기대값 2회 (세션을 들일 때마다 한 번)", source.lookups)
+    }
+}
+
+// 저장소에서 세션을 가져오지 못하면 상품 범위·하한 없이 받지 않고 session_unavailable로 거부한 뒤, 다음 샘플에서 다시 조회한다
+func TestValidateRejectsWhenSessionUnavailable(t *testing.T) {
+    cases := []struct {
+        name string
+        fail func(f *fakeSource)
+    }{
+        {"세션 조회 실패", func(f *fakeSource) { f.err = errors.New("DB 연결 끊김") }},
+        {"저장소에 없는 세션", func(f *fakeSource) { delete(f.products, "s1") }},
+        {"마지막 timestamp 조회 실패", func(f *fakeSource) { f.lastErr = errors.New("DB 연결 끊김") }},
+    }
+
+    for _, c := range cases {
+        t.Run(c.name, func(t *testing.T) {
+            v := newTestValidator(t)
+            source := &fakeSource{stored: map[string]int64{"s1": testNow.UnixMilli() + 100}}
+            v.RestoreFrom(source)
+            at := func(ts int64) models.GazeData {
+                return models.GazeData{X: 1, Y: 1, Timestamp: testNow.UnixMilli() + ts}
+            }
+
+            source.products = map[string]string{"s1": "shinhan-global-multi-asset"}
+            c.fail(source)
+            for i, ts := range []int64{10, 20} {
+                if rejection := v.Validate("s1", at(ts), testNow); rejection == nil || rejection.Reason != ReasonSessionUnavailable {
+                    t.Fatalf("%d번째 샘플 = %v, 기대값 %s", i, rejection, ReasonSessionUnavailable)
+                }
+            }
+            if err := v.ValidatePage("s1", "productJoin"); err == nil {
+                t.Fatal("세션을 가져오지 못했는데 페이지 변경이 통과함")
+            }
+            if rejected := v.Rejected("s1"); rejected[ReasonSessionUnavailable] != 2 {
+                t.Fatalf("거부 건수 = %v, 기대값 %s 2건", rejected, ReasonSessionUnavailable)
+            }
+            if source.lookups != 3 {
+                t.Fatalf("저장소 조회 %d회, 기대값 3회 (실패하면 매번 다시 조회)", source.lookups)
+            }
+
+            // 저장소가 돌아오면 다음 샘플에서 상품과 하한을 들인다
+            source.products["s1"] = "shinhan-global-multi-asset"
+            source.err, source.lastErr = nil, nil
+            if rejection := v.Validate("s1", at(50), testNow); rejection == nil || rejection.Reason != ReasonNotMonotonic {
+                t.Fatalf("복구 뒤 저장된 샘플보다 이전 시각이 통과함: %v", rejection)
+            }
+            if rejection := v.Validate("s1", at(120), testNow); rejection != nil {
+                t.Fatalf("복구 뒤 새 샘플이 거부됨: %v", rejection)
+            }
+        })
+    }
+}
+
+// 페이지와 섹션은 세션 상품의 약관 안에서만 받는다
+func TestValidateScopesPagesToSessionProduct(t *testing.T) {
+    terms, err := catalog.Parse([]byte(`{
+        "version": "test",
+        "products": [
+            {"id": "fund", "name": "펀드", "pages": ["fundTerms"]},
+            {"id": "loan", "name": "대출", "pages": ["loanTerms"]}
+        ],
+        "pages": [
+            {"id": "fundTerms", "title": "펀드 약관", "sections": [{"id": "risk", "name": "위험", "required": 5, "priority": "high"}]},
+            {"id": "loanTerms", "title": "대출 약관", "sections": [{"id": "rate", "name": "금리", "required": 5, "priority": "high"}]}
+        ]
+    }`))
+    if err != nil {
+        t.Fatalf("카탈로그 파싱 실패: %v", err)
+    }
+    v := NewGazeValidator(terms, Config{})
+    v.RestoreFrom(&fakeSource{products: map[string]string{"s1": "fund"}})
+
+    gaze := func(ts int64, page, section string) models.GazeData {
+        return models.GazeData{X: 1, Y: 1, Timestamp: ts, CurrentPage: strPtr(page), SectionID: strPtr(section)}
+    }
+    if rejection := v.Validate("s1", gaze(1, "fundTerms", "risk"), testNow); rejection != nil {
+        t.Fatalf("세션 상품의 페이지가 거부됨: %v", rejection)
+    }
+    if rejection := v.Validate("s1", gaze(2, "loanTerms", "rate"), testNow); rejection == nil || rejection.Reason != ReasonUnknownLocation {
+        t.Fatalf("다른 상품의 페이지가 통과함: %v", rejection)
+    }
+
+    if err := v.ValidatePage("s1", "fundTerms"); err != nil {
+        t.Fatalf("세션 상품의 페이지 변경이 거부됨: %v", err)
+    }
+    if err := v.ValidatePage("s1", "loanTerms"); err == nil {
+        t.Fatalf("다른 상품의 페이지 변경이 통과함")
+    }
+}